@@ -29,6 +29,10 @@ func main() {
 
 	redisx.InitConfig(config.GetRedisConf())
 	redisx.Init()
+
+	// 初始化第三方服务地址（转录任务依赖 dlyt/asr/translate）
+	config.InitSvc()
+
 	queue.InitServer(config.GetRedisConf())
 	task.Init()
 	if err := queue.Start(); err != nil {
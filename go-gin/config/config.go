@@ -5,6 +5,7 @@ import (
 	"go-gin/internal/component/logx"
 	"go-gin/internal/component/redisx"
 	filex "go-gin/internal/file"
+	"go-gin/internal/llm"
 	"sync"
 )
 
@@ -15,9 +16,17 @@ type Config struct {
 	Log     logx.Config   `yaml:"log"`
 	Svc     SvcConfig     `yaml:"svc"`
 	Creds   CredsConfig   `yaml:"creds"`
+	LLM     LLMConfig     `yaml:"llm"`
 	Monitor MonitorConfig `yaml:"monitor"`
 }
 
+// LLMConfig OpenAI 兼容的模型提供方，翻译等功能按名称引用
+type LLMConfig struct {
+	// 默认提供方名称，为空时依次取 svc.translate_provider、deepseek
+	Default   string               `yaml:"default"`
+	Providers []llm.ProviderConfig `yaml:"providers"`
+}
+
 var instance *Config
 var once sync.Once
 
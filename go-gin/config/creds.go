@@ -10,12 +10,23 @@ type CredsConfig struct {
 	} `yaml:"bailian"`
 
 	Volc struct {
-		AppId         string `yaml:"app_id"`
-		AccessKey     string `yaml:"access_key"`
-		ASRResourceId string `yaml:"asr_resource_id"`
-		TTSResourceId string `yaml:"tts_resource_id"`
+		AppId               string `yaml:"app_id"`
+		AccessKey           string `yaml:"access_key"`
+		ASRResourceId       string `yaml:"asr_resource_id"`
+		ASRSubmitResourceId string `yaml:"asr_submit_resource_id"`
+		TTSResourceId       string `yaml:"tts_resource_id"`
 	} `yaml:"volc"`
 
+	// OpenAI 兼容转写服务（可为自建 whisper）
+	Whisper struct {
+		ApiKey string `yaml:"api_key"`
+	} `yaml:"whisper"`
+
+	// 管理接口令牌（请求头 X-Admin-Token），为空时管理接口一律拒绝
+	Admin struct {
+		Token string `yaml:"token"`
+	} `yaml:"admin"`
+
 	Qiniu struct {
 		AccessKey string `yaml:"access_key"`
 		SecretKey string `yaml:"secret_key"`
@@ -2,8 +2,12 @@ package config
 
 import (
 	"log"
+	"time"
 
+	"go-gin/internal/llm"
 	"go-gin/internal/qiniu"
+	"go-gin/logic"
+	"go-gin/middleware"
 	"go-gin/rest/asr"
 	"go-gin/rest/dlyt"
 	"go-gin/rest/login"
@@ -14,11 +18,54 @@ import (
 )
 
 type SvcConfig struct {
-	UserSvcUrl        string `yaml:"user_url"`
-	LoginSvcUrl       string `yaml:"login_url"`
-	ASRUrl            string `yaml:"asr_url"`
-	TTSUrl            string `yaml:"tts_url"`
+	UserSvcUrl  string `yaml:"user_url"`
+	LoginSvcUrl string `yaml:"login_url"`
+	ASRUrl      string `yaml:"asr_url"`
+	TTSUrl      string `yaml:"tts_url"`
+	// 翻译使用的 llm 提供方名称（见 llm.providers，内置 deepseek | bailian），为空时使用 llm 默认提供方
 	TranslateProvider string `yaml:"translate_provider"`
+	// ASR 默认识别语言：auto | en | zh ...（默认 auto，请求可用 source_lang 覆盖）
+	ASRLanguage string `yaml:"asr_language"`
+	// ASR 提供方，按优先级逗号分隔并依次故障转移：volc_flash | volc_submit | whisper（默认 volc_flash）
+	ASRProvider string `yaml:"asr_provider"`
+	// OpenAI 兼容转写服务地址（如自建 whisper），为空时不启用 whisper 提供方
+	WhisperUrl string `yaml:"whisper_url"`
+	// whisper 模型名（默认 whisper-1）
+	WhisperModel string `yaml:"whisper_model"`
+	// 长音频切片时长（默认 10m），超过该时长的音频切片后并发识别；设为负值关闭切片
+	ASRChunkWindow time.Duration `yaml:"asr_chunk_window"`
+	// 固定窗口切片的重叠时长（默认 5s）
+	ASRChunkOverlap time.Duration `yaml:"asr_chunk_overlap"`
+	// 切片识别并发数（默认 3）
+	ASRChunkConcurrency int `yaml:"asr_chunk_concurrency"`
+	// 已有转录的新鲜期（如 720h），超过后重新识别；为空或 0 表示永不过期
+	TranscriptCacheTTL time.Duration `yaml:"transcript_cache_ttl"`
+	// 转录缓存命中的计费策略：full | free（默认 full，与 TTS 历史命中一致）
+	TranscriptCacheBilling string `yaml:"transcript_cache_billing"`
+	// 平台字幕使用策略：off | manual | auto（默认 manual，仅人工字幕）
+	CaptionMode string `yaml:"caption_mode"`
+	// 摘要使用的 llm 提供方名称，为空时使用 llm 默认提供方
+	SummaryProvider string `yaml:"summary_provider"`
+	// 转录问答使用的 llm 提供方名称，为空时使用 llm 默认提供方
+	AskProvider string `yaml:"ask_provider"`
+	// 长转录问答的片段检索方式：bm25 | embedding（默认 bm25）
+	AskRetrieval string `yaml:"ask_retrieval"`
+	// 向量检索使用的 llm 提供方名称（需配置 llm.providers[].embedding_model），为空时使用 llm 默认提供方
+	AskEmbeddingProvider string `yaml:"ask_embedding_provider"`
+	// 分句级翻译记忆：on | off（默认 on），命中的分句不再调用模型、不计翻译字符
+	TranslationMemory string `yaml:"translation_memory"`
+	// 转录复核默认置信度阈值（1~100，默认 60），请求可用 threshold 覆盖
+	TranscriptReviewThreshold int `yaml:"transcript_review_threshold"`
+	// 超过该字数的合成文本走异步任务（默认 3000）
+	TTSAsyncThreshold int `yaml:"tts_async_threshold"`
+	// 长文本切分的片段字数上限（默认 500，不超过 1000）
+	TTSChunkRunes int `yaml:"tts_chunk_runes"`
+	// 长文本片段合成并发数（默认 3）
+	TTSConcurrency int `yaml:"tts_concurrency"`
+	// 分句级合成音频缓存：on | off（默认 on），命中的分句不再调用上游、不计合成字数
+	TTSSentenceCache string `yaml:"tts_sentence_cache"`
+	// 分句音频缓存未被使用超过该时长后清理（默认 720h）
+	TTSSentenceCacheTTL time.Duration `yaml:"tts_sentence_cache_ttl"`
 	// Bilibili 音频处理模式：local | url（默认 local）
 	BilibiliAudioMode string `yaml:"bilibili_audio_mode"`
 	// Bilibili URL 模式策略：raw | proxy（当前实现仅 raw，占位）
@@ -39,30 +86,46 @@ func InitSvc() {
 		BilibiliURLStrategy: svcConfig.BilibiliURLStrategy,
 	})
 	asr.Init(svcConfig.ASRUrl)
-	translate.Init("") // URL在service内部写死
+	asr.SetOptions(asr.Options{
+		Language:         svcConfig.ASRLanguage,
+		Provider:         svcConfig.ASRProvider,
+		ChunkWindow:      svcConfig.ASRChunkWindow,
+		ChunkOverlap:     svcConfig.ASRChunkOverlap,
+		ChunkConcurrency: svcConfig.ASRChunkConcurrency,
+	})
+	translate.Init()
+	translate.SetMemoryEnabled(svcConfig.TranslationMemory != "off")
 	tts.Init(svcConfig.TTSUrl)
+	logic.SetTranscriptOptions(logic.TranscriptOptions{
+		CacheTTL:        svcConfig.TranscriptCacheTTL,
+		CacheBilling:    svcConfig.TranscriptCacheBilling,
+		CaptionMode:     svcConfig.CaptionMode,
+		ReviewThreshold: svcConfig.TranscriptReviewThreshold,
+	})
+	logic.SetSummaryOptions(logic.SummaryOptions{Provider: svcConfig.SummaryProvider})
+	logic.SetTTSOptions(logic.TTSOptions{
+		AsyncThreshold:       svcConfig.TTSAsyncThreshold,
+		ChunkRunes:           svcConfig.TTSChunkRunes,
+		Concurrency:          svcConfig.TTSConcurrency,
+		DisableSentenceCache: svcConfig.TTSSentenceCache == "off",
+		SentenceCacheTTL:     svcConfig.TTSSentenceCacheTTL,
+	})
+	logic.SetAskOptions(logic.AskOptions{
+		Provider:          svcConfig.AskProvider,
+		Retrieval:         svcConfig.AskRetrieval,
+		EmbeddingProvider: svcConfig.AskEmbeddingProvider,
+	})
 
 	// 注入火山凭据
 	volc := instance.Creds.Volc
-	asr.SetVolcCreds(asr.VolcCreds{AppId: volc.AppId, AccessKey: volc.AccessKey, ASRResourceId: volc.ASRResourceId})
+	asr.SetVolcCreds(asr.VolcCreds{AppId: volc.AppId, AccessKey: volc.AccessKey, ASRResourceId: volc.ASRResourceId, ASRSubmitResourceId: volc.ASRSubmitResourceId})
+	asr.SetWhisperConfig(asr.WhisperConfig{URL: svcConfig.WhisperUrl, ApiKey: instance.Creds.Whisper.ApiKey, Model: svcConfig.WhisperModel})
 	tts.SetVolcCreds(tts.VolcCreds{AppId: volc.AppId, AccessKey: volc.AccessKey, TTSResourceId: volc.TTSResourceId})
+	middleware.SetAdminToken(instance.Creds.Admin.Token)
 
-	// 注入DeepSeek凭据
-	ds := instance.Creds.Deepseek
-	translate.SetDeepSeekCreds(translate.DeepSeekCreds{ApiKey: ds.ApiKey})
-	log.Printf("deepseek config: api_key=%s", maskKey(ds.ApiKey))
-
-	// 注入百炼凭据
-	bl := instance.Creds.Bailian
-	translate.SetBailianCreds(translate.BailianCreds{ApiKey: bl.ApiKey})
-	log.Printf("bailian config: api_key=%s", maskKey(bl.ApiKey))
-
-	// 选择翻译提供方（默认 deepseek），可在 yaml: svc.translate_provider=bailian 切换
-	provider := translate.ProviderDeepSeek
-	if svcConfig.TranslateProvider == "bailian" {
-		provider = translate.ProviderBailian
-	}
-	translate.SetProvider(provider)
+	initLLM()
+	// 翻译提供方为 llm 提供方名称（默认 deepseek），可在 yaml: svc.translate_provider=bailian 切换
+	translate.SetProvider(translate.Provider(svcConfig.TranslateProvider))
 
 	// 注入七牛凭据（保持与 dlyt 一致的参数命名）
 	q := instance.Creds.Qiniu
@@ -79,6 +142,38 @@ func InitSvc() {
 	}
 }
 
+// initLLM 注册 llm 提供方：优先使用 llm.providers，未配置的内置提供方沿用 creds 中的 api_key
+func initLLM() {
+	conf := instance.LLM
+	configured := map[string]bool{}
+	for _, p := range conf.Providers {
+		llm.Register(p)
+		configured[p.Name] = true
+		log.Printf("llm provider config: name=%s api_key=%s", p.Name, maskKey(p.ApiKey))
+	}
+	builtin := []llm.ProviderConfig{
+		{Name: string(translate.ProviderDeepSeek), BaseURL: "https://api.deepseek.com", ApiKey: instance.Creds.Deepseek.ApiKey, Model: "deepseek-chat"},
+		{Name: string(translate.ProviderBailian), BaseURL: "https://dashscope.aliyuncs.com/compatible-mode/v1", ApiKey: instance.Creds.Bailian.ApiKey, Model: "qwen-turbo-latest"},
+	}
+	for _, p := range builtin {
+		if configured[p.Name] || p.ApiKey == "" {
+			continue
+		}
+		llm.Register(p)
+		log.Printf("%s config: api_key=%s", p.Name, maskKey(p.ApiKey))
+	}
+
+	name := conf.Default
+	if name == "" {
+		name = instance.Svc.TranslateProvider
+	}
+	if name == "" {
+		name = string(translate.ProviderDeepSeek)
+	}
+	llm.SetDefault(name)
+	log.Printf("llm providers: %v, default: %s", llm.Names(), name)
+}
+
 func maskKey(key string) string {
 	if len(key) <= 8 {
 		return "***"
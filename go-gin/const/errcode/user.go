@@ -10,14 +10,54 @@ var (
 	ErrUserNameOrPwdFaild = errorx.New(20002, "用户名或者密码错误")
 	ErrUserMustLogin      = errorx.New(20003, "请先登录")
 	ErrUserNeedLoginAgain = errorx.New(20004, "token已过期,请重新登录")
+	ErrAdminForbidden     = errorx.New(20005, "无管理权限")
 
 	// 第三方服务错误
 	ErrDLYTUpstream = errorx.New(20020, "视频服务错误")
 	ErrASRUpstream  = errorx.New(20021, "语音识别服务错误")
 	ErrTranslateUp  = errorx.New(20022, "翻译服务错误")
 	ErrTTSUpstream  = errorx.New(20023, "语音合成服务错误")
+	ErrLLMUpstream  = errorx.New(20025, "大模型服务错误")
+
+	ErrLangNotSupported = errorx.New(20024, "不支持的语言")
 
 	// 业务扩展错误
 	ErrUserVoiceNotConfigured = errorx.New(20030, "未配置我的声音")
 	ErrQuotaNotEnough         = errorx.New(20031, "余额不足，请联系管理员或稍后再试")
+
+	// 异步任务错误
+	ErrTranscriptJobNotFound = errorx.New(20040, "转录任务不存在")
+	ErrTranscriptJobDispatch = errorx.New(20041, "转录任务提交失败，请稍后再试")
+	ErrTranscriptNotFound    = errorx.New(20042, "转录记录不存在")
+	ErrSubtitleEmpty         = errorx.New(20043, "没有可导出的字幕内容")
+	ErrSpeakerNotFound       = errorx.New(20044, "说话人不存在")
+	ErrRevisionNotFound      = errorx.New(20045, "修订记录不存在")
+	ErrTranscriptEditEmpty   = errorx.New(20046, "没有需要保存的修改")
+	ErrSegmentNotFound       = errorx.New(20047, "分句不存在")
+	ErrSummaryEmpty          = errorx.New(20048, "转录内容为空，无法生成摘要")
+	ErrQuestionEmpty         = errorx.New(20049, "问题不能为空")
+
+	// 流水线错误
+	ErrPipelineNotFound  = errorx.New(20050, "流水线不存在")
+	ErrPipelineNotFailed = errorx.New(20051, "仅失败的流水线可以重新驱动")
+	ErrPipelineDispatch  = errorx.New(20052, "流水线重新驱动提交失败，请稍后再试")
+
+	// 术语表错误
+	ErrGlossaryTermNotFound   = errorx.New(20060, "术语不存在")
+	ErrGlossaryTermExists     = errorx.New(20061, "该原文术语已存在")
+	ErrGlossaryImportInvalid  = errorx.New(20062, "术语表文件格式错误")
+	ErrGlossaryShareSelf      = errorx.New(20063, "不能共享给自己")
+	ErrGlossaryInviteNotFound = errorx.New(20064, "共享邀请不存在")
+
+	// 搜索错误
+	ErrSearchQueryTooShort = errorx.New(20070, "搜索词至少需要两个字符")
+
+	// 合成任务错误
+	ErrTTSJobNotFound = errorx.New(20080, "合成任务不存在")
+	ErrTTSJobDispatch = errorx.New(20081, "合成任务提交失败，请稍后再试")
+	ErrTTSNotFound    = errorx.New(20082, "合成记录不存在")
+	ErrTTSTimingsNone = errorx.New(20083, "该合成记录没有时间戳")
+
+	// 转录编辑错误
+	ErrTranscriptEditWhole = errorx.New(20090, "该转录包含分句，请按分句修改")
 )
@@ -27,5 +27,5 @@ func (c *accountController) Usage(ctx *httpx.Context) (any, error) {
 	identity := httpx.Identity(ctx)
 	l := logic.NewAccountLogic()
 	items, _ := l.Usage(ctx, identity)
-	return map[string]any{"days": items}, nil
+	return map[string]any{"days": items, "translation_memory": l.TranslationMemory(items)}, nil
 }
@@ -1,9 +1,15 @@
 package controller
 
 import (
+	"time"
+
+	"go-gin/const/errcode"
 	"go-gin/internal/httpx"
 	"go-gin/internal/httpx/validators"
+	"go-gin/internal/queue"
 	"go-gin/logic"
+	"go-gin/task"
+	"go-gin/typing"
 )
 
 type adminController struct{}
@@ -11,9 +17,10 @@ type adminController struct{}
 var AdminController = &adminController{}
 
 type SeedQuotaReq struct {
-	Identities []string `json:"identities" binding:"required" label:"账号列表"`
-	AsrChars   int      `json:"asr_chars" binding:"required" label:"ASR额度"`
-	TtsChars   int      `json:"tts_chars" binding:"required" label:"TTS额度"`
+	Identities    []string `json:"identities" binding:"required" label:"账号列表"`
+	AsrChars      int      `json:"asr_chars" binding:"required" label:"ASR额度"`
+	TtsChars      int      `json:"tts_chars" binding:"required" label:"TTS额度"`
+	SummaryTokens int      `json:"summary_tokens" binding:"omitempty,min=0" label:"摘要额度"` // 为空时使用默认摘要额度
 }
 
 func (c *adminController) SeedQuota(ctx *httpx.Context) (any, error) {
@@ -25,8 +32,62 @@ func (c *adminController) SeedQuota(ctx *httpx.Context) (any, error) {
 		return nil, err
 	}
 	l := logic.NewAdminLogic()
-	if err := l.SeedQuota(ctx, req.Identities, req.AsrChars, req.TtsChars); err != nil {
+	if req.SummaryTokens == 0 {
+		req.SummaryTokens = logic.DefaultSummaryTokens
+	}
+	if err := l.SeedQuota(ctx, req.Identities, req.AsrChars, req.TtsChars, req.SummaryTokens); err != nil {
 		return nil, err
 	}
 	return map[string]any{"ok": true}, nil
 }
+
+// Pipelines 分页查询流水线，可按状态过滤（如 failed）
+func (c *adminController) Pipelines(ctx *httpx.Context) (any, error) {
+	var req typing.PipelineListReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return nil, err
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Size <= 0 || req.Size > 100 {
+		req.Size = 20
+	}
+	items, total, err := logic.NewPipelineLogic().List(ctx, req.Status, req.Page, req.Size)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"items": items,
+		"pagination": map[string]any{
+			"page":  req.Page,
+			"size":  req.Size,
+			"total": total,
+		},
+	}, nil
+}
+
+// Pipeline 查询流水线详情及各阶段输入输出
+func (c *adminController) Pipeline(ctx *httpx.Context) (any, error) {
+	var req typing.PipelineReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewPipelineLogic().Detail(ctx, req.Id)
+}
+
+// RedrivePipeline 重新驱动失败的流水线，从第一个未完成的阶段继续
+func (c *adminController) RedrivePipeline(ctx *httpx.Context) (any, error) {
+	var req typing.PipelineReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	run, err := logic.NewPipelineLogic().PrepareRedrive(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := queue.NewOption().MaxRetry(0).Timeout(30 * time.Minute).Dispatch(task.NewPipelineRedriveTask(run.Id)); err != nil {
+		return nil, errcode.ErrPipelineDispatch
+	}
+	return &typing.PipelineRedriveReply{RunId: run.Id, JobId: run.JobId}, nil
+}
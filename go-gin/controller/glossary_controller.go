@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"go-gin/const/errcode"
+	"go-gin/internal/httpx"
+	"go-gin/internal/httpx/validators"
+	"go-gin/logic"
+	"go-gin/typing"
+)
+
+// CSV 导入文件大小上限
+const glossaryImportMaxBytes = 2 << 20
+
+type glossaryController struct{}
+
+var GlossaryController = &glossaryController{}
+
+// List 获取生效的术语（自己的 + 共享给我的）
+func (c *glossaryController) List(ctx *httpx.Context) (any, error) {
+	return logic.NewGlossaryLogic().List(ctx, httpx.Identity(ctx))
+}
+
+// Create 新增术语
+func (c *glossaryController) Create(ctx *httpx.Context) (any, error) {
+	var req typing.GlossaryTermReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	if err := validators.Validate(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewGlossaryLogic().Create(ctx, httpx.Identity(ctx), req)
+}
+
+// Update 修改术语
+func (c *glossaryController) Update(ctx *httpx.Context) (any, error) {
+	var req typing.GlossaryTermUpdateReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewGlossaryLogic().Update(ctx, httpx.Identity(ctx), req)
+}
+
+// Delete 删除术语
+func (c *glossaryController) Delete(ctx *httpx.Context) (any, error) {
+	var req typing.GlossaryTermIdReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	return nil, logic.NewGlossaryLogic().Delete(ctx, httpx.Identity(ctx), req.Id)
+}
+
+// Import 通过 CSV 文件（表单字段 file）批量导入术语
+func (c *glossaryController) Import(ctx *httpx.Context) (any, error) {
+	fh, err := ctx.FormFile("file")
+	if err != nil || fh.Size == 0 || fh.Size > glossaryImportMaxBytes {
+		return nil, errcode.ErrGlossaryImportInvalid
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, errcode.ErrGlossaryImportInvalid
+	}
+	defer f.Close()
+	return logic.NewGlossaryLogic().Import(ctx, httpx.Identity(ctx), f)
+}
+
+// Members 获取术语表共享关系
+func (c *glossaryController) Members(ctx *httpx.Context) (any, error) {
+	return logic.NewGlossaryLogic().Members(ctx, httpx.Identity(ctx))
+}
+
+// AddMember 邀请团队成员共享术语表
+func (c *glossaryController) AddMember(ctx *httpx.Context) (any, error) {
+	var req typing.GlossaryMemberReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewGlossaryLogic().AddMember(ctx, httpx.Identity(ctx), req.Identity)
+}
+
+// RemoveMember 取消共享
+func (c *glossaryController) RemoveMember(ctx *httpx.Context) (any, error) {
+	var req typing.GlossaryMemberUriReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewGlossaryLogic().RemoveMember(ctx, httpx.Identity(ctx), req.Identity)
+}
+
+// AcceptInvite 接受术语表共享邀请
+func (c *glossaryController) AcceptInvite(ctx *httpx.Context) (any, error) {
+	var req typing.GlossaryOwnerUriReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewGlossaryLogic().AcceptInvite(ctx, httpx.Identity(ctx), req.Owner)
+}
+
+// LeaveShare 拒绝共享邀请或退出共享
+func (c *glossaryController) LeaveShare(ctx *httpx.Context) (any, error) {
+	var req typing.GlossaryOwnerUriReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewGlossaryLogic().LeaveShare(ctx, httpx.Identity(ctx), req.Owner)
+}
+
+// Check 用当前术语表校验转录译文
+func (c *glossaryController) Check(ctx *httpx.Context) (any, error) {
+	var req typing.YtTranscriptReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewGlossaryLogic().CheckTranscript(ctx, httpx.Identity(ctx), req.Id)
+}
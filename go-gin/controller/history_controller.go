@@ -1,9 +1,12 @@
 package controller
 
 import (
+	"fmt"
 	"go-gin/internal/component/logx"
 	"go-gin/internal/httpx"
 	"go-gin/logic"
+	"go-gin/typing"
+	"net/http"
 	"strconv"
 )
 
@@ -51,3 +54,26 @@ func (c *historyController) ListTTS(ctx *httpx.Context) (any, error) {
 		},
 	}, nil
 }
+
+// TTSTimings 获取合成记录的逐句、逐词时间戳，format=vtt 时导出字幕文件
+func (c *historyController) TTSTimings(ctx *httpx.Context) (any, error) {
+	var req typing.TTSTimingsReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return nil, err
+	}
+	l := logic.NewHistoryLogic()
+	identity := httpx.Identity(ctx)
+	if req.Format != "vtt" {
+		return l.TTSTimings(ctx, identity, req.Id)
+	}
+	file, err := l.ExportTTSTimings(ctx, identity, req.Id, req.Level == "word")
+	if err != nil {
+		return nil, err
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Name))
+	ctx.Data(http.StatusOK, file.ContentType, file.Data)
+	return nil, nil
+}
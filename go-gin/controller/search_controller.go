@@ -0,0 +1,20 @@
+package controller
+
+import (
+	"go-gin/internal/httpx"
+	"go-gin/logic"
+	"go-gin/typing"
+)
+
+type searchController struct{}
+
+var SearchController = &searchController{}
+
+// Search 搜索当前用户的转录、视频标题与合成历史
+func (c *searchController) Search(ctx *httpx.Context) (any, error) {
+	var req typing.SearchReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewSearchLogic().Search(ctx, httpx.Identity(ctx), req)
+}
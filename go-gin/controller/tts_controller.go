@@ -1,13 +1,20 @@
 package controller
 
 import (
+	"encoding/base64"
 	"fmt"
+	"go-gin/const/errcode"
 	"go-gin/internal/component/logx"
+	"go-gin/internal/errorx"
 	"go-gin/internal/httpx"
 	"go-gin/internal/httpx/validators"
+	"go-gin/internal/queue"
 	"go-gin/logic"
+	"go-gin/task"
 	"go-gin/typing"
+	"net/http"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
@@ -24,18 +31,23 @@ func (c *ttsController) Synthesize(ctx *httpx.Context) (any, error) {
 		return nil, err
 	}
 
-	// 自定义验证：检查字符数不超过1000
-	if utf8.RuneCountInString(req.Text) > 1000 {
-		return nil, fmt.Errorf("文本字数不能超过1000字，当前%d字", utf8.RuneCountInString(req.Text))
+	// 自定义验证：检查字符数上限，超过 1000 字的文本分段合成后拼接
+	chars := utf8.RuneCountInString(req.Text)
+	if chars > logic.TTSMaxRunes {
+		return nil, fmt.Errorf("文本字数不能超过%d字，当前%d字", logic.TTSMaxRunes, chars)
 	}
 
 	identity := httpx.Identity(ctx)
+	// 超长文本转为异步任务，前端轮询 /tts/jobs/:id 获取进度
+	if logic.TTSAsync(chars) {
+		return c.submitJob(ctx, identity, req)
+	}
 	l := logic.NewTTSLogic()
-	item, err := l.Synthesize(ctx, identity, req.Text, req.Speaker, req.UseMyVoice)
+	item, err := l.Synthesize(ctx, identity, req.Text, req.Speaker, req.UseMyVoice, req.Prosody())
 	if err != nil {
 		return nil, err
 	}
-	resp := map[string]any{"audio_url": item.AudioUrl, "char_count": item.CharCount}
+	resp := map[string]any{"audio_url": item.AudioUrl, "char_count": item.CharCount, "billed_chars": item.BilledChars}
 	// 业务日志：返回给前端的关键字段（避免打印巨大 data URL 全量，仅打印类型与长度）
 	urlType := "remote"
 	if strings.HasPrefix(item.AudioUrl, "data:") {
@@ -47,9 +59,138 @@ func (c *ttsController) Synthesize(ctx *httpx.Context) (any, error) {
 			"audio_url_type": urlType,
 			"audio_url_len":  len(item.AudioUrl),
 			"char_count":     item.CharCount,
+			"billed_chars":   item.BilledChars,
 			"speaker":        req.Speaker,
 			"use_my_voice":   req.UseMyVoice,
 		},
 	)
 	return resp, nil
 }
+
+func (c *ttsController) submitJob(ctx *httpx.Context, identity string, req typing.TTSSynthesizeReq) (any, error) {
+	l := logic.NewTTSJobLogic()
+	job, err := l.Submit(ctx, identity, req)
+	if err != nil {
+		return nil, err
+	}
+	// 分段合成失败不自动重试，由用户重新提交
+	if err := queue.NewOption().MaxRetry(0).Timeout(30 * time.Minute).Dispatch(task.NewTTSTask(job.JobId)); err != nil {
+		_ = l.Fail(ctx, job.JobId, err)
+		return nil, errcode.ErrTTSJobDispatch
+	}
+	logx.WithContext(ctx).Info("tts_job_submitted", map[string]any{"job_id": job.JobId, "char_count": job.CharCount, "speaker": req.Speaker})
+	return &typing.TTSJobSubmitReply{
+		JobId:     job.JobId,
+		Status:    job.Status,
+		CharCount: job.CharCount,
+		Async:     true,
+	}, nil
+}
+
+// Job 查询长文本合成任务进度
+func (c *ttsController) Job(ctx *httpx.Context) (any, error) {
+	var req typing.TTSJobReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewTTSJobLogic().Get(ctx, httpx.Identity(ctx), req.JobId)
+}
+
+// Stream 流式合成：format=mpeg 时以 chunked audio/mpeg 边合成边输出，format=sse 时逐帧推送 base64 音频事件；
+// 完整音频在结束后入库计费。输出开始前的错误按普通 JSON 返回，开始后只能记录日志（sse 额外推送 error 事件）
+func (c *ttsController) Stream(ctx *httpx.Context) (any, error) {
+	var req typing.TTSStreamReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		return nil, err
+	}
+	if err := validators.Validate(&req); err != nil {
+		return nil, err
+	}
+	chars := utf8.RuneCountInString(req.Text)
+	if chars > logic.TTSMaxRunes {
+		return nil, fmt.Errorf("文本字数不能超过%d字，当前%d字", logic.TTSMaxRunes, chars)
+	}
+
+	sse := req.Format == "sse"
+	started := false
+	start := func() {
+		if sse {
+			ctx.Header("Content-Type", "text/event-stream")
+		} else {
+			ctx.Header("Content-Type", "audio/mpeg")
+		}
+		ctx.Header("Cache-Control", "no-cache")
+		ctx.Header("X-Accel-Buffering", "no") // 关闭 nginx 缓冲
+		ctx.Status(http.StatusOK)
+		started = true
+	}
+	onAudio := func(chunk []byte) error {
+		if err := ctx.Request.Context().Err(); err != nil {
+			return err // 客户端已断开，中止合成（不入库、不计费）
+		}
+		if !started {
+			start()
+		}
+		if sse {
+			ctx.SSEvent("audio", base64.StdEncoding.EncodeToString(chunk))
+		} else if _, err := ctx.Writer.Write(chunk); err != nil {
+			return err
+		}
+		ctx.Writer.Flush()
+		return nil
+	}
+
+	identity := httpx.Identity(ctx)
+	item, hit, err := logic.NewTTSLogic().SynthesizeStream(ctx, identity, req.Text, req.Speaker, req.UseMyVoice, req.Prosody(), onAudio)
+	if err != nil {
+		if !started {
+			return nil, err
+		}
+		logx.WithContext(ctx).Error("tts_stream_failed", map[string]any{"identity": identity, "char_count": chars, "err": err.Error()})
+		if sse {
+			bizErr, ok := err.(errorx.BizError)
+			if !ok {
+				bizErr = errcode.ErrTTSUpstream
+			}
+			ctx.SSEvent("error", map[string]any{"code": bizErr.Code, "message": bizErr.Msg})
+			ctx.Writer.Flush()
+		}
+		return nil, nil
+	}
+
+	logx.WithContext(ctx).Info("tts_stream_done", map[string]any{
+		"id":           item.Id,
+		"char_count":   item.CharCount,
+		"billed_chars": item.BilledChars,
+		"history_hit":  hit,
+		"format":       req.Format,
+	})
+	if sse {
+		if !started {
+			start()
+		}
+		ctx.SSEvent("done", &typing.TTSStreamDone{
+			Id:          item.Id,
+			AudioUrl:    item.AudioUrl,
+			CharCount:   item.CharCount,
+			BilledChars: item.BilledChars,
+			DurationMs:  item.DurationMs,
+			Cached:      hit,
+		})
+		ctx.Writer.Flush()
+		return nil, nil
+	}
+	if hit {
+		// 命中历史：远程音频直接跳转，data URL 解码后输出
+		if data, ok := strings.CutPrefix(item.AudioUrl, "data:audio/mp3;base64,"); ok {
+			audio, err := base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				return nil, err
+			}
+			ctx.Data(http.StatusOK, "audio/mpeg", audio)
+			return nil, nil
+		}
+		ctx.Redirect(http.StatusFound, item.AudioUrl)
+	}
+	return nil, nil
+}
@@ -1,11 +1,19 @@
 package controller
 
 import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-gin/const/errcode"
 	"go-gin/internal/httpx"
 	"go-gin/internal/httpx/validators"
+	"go-gin/internal/queue"
 	"go-gin/logic"
 	"go-gin/rest/dlyt"
+	"go-gin/task"
 	"go-gin/typing"
+	"go-gin/util/subtitle"
 )
 
 type ytController struct{}
@@ -33,12 +41,212 @@ func (c *ytController) Text(ctx *httpx.Context) (any, error) {
 	}
 	identity := httpx.Identity(ctx)
 	l := logic.NewTranscriptLogic()
-	tr, err := l.GetOrCreateWithPlatform(ctx, req.IdOrUrl, req.TargetLan, identity, req.Platform)
+	tr, err := l.Process(ctx, identity, logic.TranscriptParams{
+		IdOrUrl:      req.IdOrUrl,
+		Platform:     req.Platform,
+		TargetLang:   req.TargetLan,
+		SourceLang:   req.SourceLang,
+		ForceRefresh: req.ForceRefresh,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &typing.YtTextReply{
+		TranscriptId:   tr.Id,
+		DetectedLang:   tr.DetectedLang,
+		TextSource:     tr.TextSource,
 		TranslatedText: tr.TranslatedText,
 	}, nil
 }
+
+// SubmitJob 提交异步转录任务，立即返回任务ID
+func (c *ytController) SubmitJob(ctx *httpx.Context) (any, error) {
+	var req typing.YtTextReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		return nil, err
+	}
+	if err := validators.Validate(&req); err != nil {
+		return nil, err
+	}
+	identity := httpx.Identity(ctx)
+	l := logic.NewTranscriptJobLogic()
+	job, err := l.Submit(ctx, identity, req)
+	if err != nil {
+		return nil, err
+	}
+	// 转录流程已自带阶段记录，失败不自动重试，由用户重新提交
+	if err := queue.NewOption().MaxRetry(0).Timeout(30 * time.Minute).Dispatch(task.NewTranscriptTask(job.JobId)); err != nil {
+		_ = l.Fail(ctx, job.JobId, err)
+		return nil, errcode.ErrTranscriptJobDispatch
+	}
+	return &typing.YtJobSubmitReply{
+		JobId: job.JobId,
+		Stage: job.Stage,
+	}, nil
+}
+
+// Job 查询异步转录任务状态
+func (c *ytController) Job(ctx *httpx.Context) (any, error) {
+	var req typing.YtJobReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	identity := httpx.Identity(ctx)
+	return logic.NewTranscriptJobLogic().Get(ctx, identity, req.JobId)
+}
+
+// Segments 获取转录的带时间戳分句
+func (c *ytController) Segments(ctx *httpx.Context) (any, error) {
+	var req typing.YtTranscriptReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewTranscriptLogic().Segments(ctx, req.Id)
+}
+
+// Review 获取需要复核的低置信度词与片段
+func (c *ytController) Review(ctx *httpx.Context) (any, error) {
+	var req typing.YtReviewReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewTranscriptLogic().Review(ctx, req.Id, req.Threshold)
+}
+
+// Speakers 获取转录中的说话人
+func (c *ytController) Speakers(ctx *httpx.Context) (any, error) {
+	var req typing.YtTranscriptReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewTranscriptLogic().Speakers(ctx, req.Id)
+}
+
+// RenameSpeakers 修改说话人显示名
+func (c *ytController) RenameSpeakers(ctx *httpx.Context) (any, error) {
+	var req typing.YtRenameSpeakersReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewTranscriptLogic().RenameSpeakers(ctx, httpx.Identity(ctx), req.Id, req.Names)
+}
+
+// Edit 修改转录文本（整段或按分句），保存为新修订
+func (c *ytController) Edit(ctx *httpx.Context) (any, error) {
+	var req typing.YtTranscriptEditReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewTranscriptLogic().Edit(ctx, httpx.Identity(ctx), req)
+}
+
+// Revisions 获取转录的修订历史
+func (c *ytController) Revisions(ctx *httpx.Context) (any, error) {
+	var req typing.YtTranscriptReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewTranscriptLogic().Revisions(ctx, httpx.Identity(ctx), req.Id)
+}
+
+// DiffRevisions 对比两个修订（to 为空时与当前文本对比）
+func (c *ytController) DiffRevisions(ctx *httpx.Context) (any, error) {
+	var req typing.YtRevisionDiffReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewTranscriptLogic().DiffRevisions(ctx, httpx.Identity(ctx), req.Id, req.From, req.To)
+}
+
+// RestoreRevision 恢复到指定修订
+func (c *ytController) RestoreRevision(ctx *httpx.Context) (any, error) {
+	var req typing.YtRevisionReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewTranscriptLogic().RestoreRevision(ctx, httpx.Identity(ctx), req.Id, req.RevNo)
+}
+
+// Summarize 生成转录的摘要、要点与章节
+func (c *ytController) Summarize(ctx *httpx.Context) (any, error) {
+	var req typing.YtSummarizeReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	// 请求体可为空
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			return nil, err
+		}
+	}
+	return logic.NewTranscriptLogic().Summarize(ctx, httpx.Identity(ctx), req)
+}
+
+// Ask 依据转录内容回答问题并引用时间戳
+func (c *ytController) Ask(ctx *httpx.Context) (any, error) {
+	var req typing.YtAskReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewTranscriptLogic().Ask(ctx, httpx.Identity(ctx), req)
+}
+
+// Questions 当前用户在该转录下的问答历史
+func (c *ytController) Questions(ctx *httpx.Context) (any, error) {
+	var req typing.YtQuestionsReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return nil, err
+	}
+	return logic.NewTranscriptLogic().Questions(ctx, httpx.Identity(ctx), req)
+}
+
+// ClearQuestions 清空当前用户在该转录下的问答历史
+func (c *ytController) ClearQuestions(ctx *httpx.Context) (any, error) {
+	var req typing.YtQuestionsReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	deleted, err := logic.NewTranscriptLogic().ClearQuestions(ctx, httpx.Identity(ctx), req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"deleted": deleted}, nil
+}
+
+// Subtitles 导出字幕文件（srt/vtt/ass）
+func (c *ytController) Subtitles(ctx *httpx.Context) (any, error) {
+	var req typing.YtSubtitleReq
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		return nil, err
+	}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		return nil, err
+	}
+	format, _ := subtitle.ParseFormat(req.Format)
+	file, err := logic.NewSubtitleLogic().Export(ctx, req.Id, format, req.Track)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Name))
+	ctx.Data(http.StatusOK, file.ContentType, file.Data)
+	return nil, nil
+}
@@ -8,5 +8,6 @@ func Init() {
 	// cronx.AddJob("@every 3s", &SampleJob{})
 	cronx.Schedule(&SampleJob{}).EveryMinute()
 	cronx.AddFunc("@every 5s", SampleFunc)
+	cronx.Schedule(&TTSSentenceCachePurgeJob{}).DailyAt("04:30")
 	// cronx.ScheduleFunc(SampleFunc).EveryMinute()
 }
@@ -0,0 +1,18 @@
+package cron
+
+import (
+	"context"
+	"go-gin/logic"
+	"log"
+)
+
+// TTSSentenceCachePurgeJob 清理长期未使用的分句音频缓存
+type TTSSentenceCachePurgeJob struct{}
+
+func (j *TTSSentenceCachePurgeJob) Handle(ctx context.Context) error {
+	n, err := logic.PurgeTTSSentenceCache(ctx)
+	if n > 0 {
+		log.Printf("[Cron] 清理分句音频缓存 - Deleted: %d", n)
+	}
+	return err
+}
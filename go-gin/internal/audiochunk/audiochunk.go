@@ -0,0 +1,201 @@
+package audiochunk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Chunk 一个切片在原音频中的位置；Keep 区间用于合并时去除重叠部分的重复结果
+type Chunk struct {
+	Index     int
+	StartMs   int64
+	EndMs     int64
+	KeepFrom  int64 // 起始时间落在 [KeepFrom, KeepTo) 的识别结果归属本切片
+	KeepTo    int64
+	BySilence bool // 是否在静音处切分（静音切分无需重叠）
+}
+
+// Silence 检测到的静音区间
+type Silence struct {
+	StartMs int64
+	EndMs   int64
+}
+
+// Options 切分参数
+type Options struct {
+	WindowMs  int64 // 目标切片时长
+	OverlapMs int64 // 固定窗口切分时相邻切片的重叠时长
+	SlackMs   int64 // 在目标切点前多长范围内寻找静音
+}
+
+func getFFmpeg() string {
+	if b := strings.TrimSpace(os.Getenv("FFMPEG_BIN")); b != "" {
+		return b
+	}
+	return "ffmpeg"
+}
+
+func getFFprobe() string {
+	if b := strings.TrimSpace(os.Getenv("FFPROBE_BIN")); b != "" {
+		return b
+	}
+	return "ffprobe"
+}
+
+// Available ffmpeg 与 ffprobe 是否可用
+func Available() bool {
+	if _, err := exec.LookPath(getFFmpeg()); err != nil {
+		return false
+	}
+	_, err := exec.LookPath(getFFprobe())
+	return err == nil
+}
+
+// Probe 返回音频时长（毫秒），input 可以是本地路径或 URL
+func Probe(ctx context.Context, input string) (int64, error) {
+	cctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(cctx, getFFprobe(), "-v", "error", "-show_entries", "format=duration", "-of", "csv=p=0", input).Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe failed: %w", err)
+	}
+	sec, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse ffprobe duration failed: %w", err)
+	}
+	return int64(sec * 1000), nil
+}
+
+var (
+	silenceStartRe = regexp.MustCompile(`silence_start:\s*(-?[\d.]+)`)
+	silenceEndRe   = regexp.MustCompile(`silence_end:\s*(-?[\d.]+)`)
+)
+
+// DetectSilences 使用 ffmpeg silencedetect 检测静音区间
+func DetectSilences(ctx context.Context, input string, noiseDb string, minSilence time.Duration) ([]Silence, error) {
+	filter := fmt.Sprintf("silencedetect=noise=%s:d=%.2f", noiseDb, minSilence.Seconds())
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(cctx, getFFmpeg(), "-hide_banner", "-nostats", "-i", input, "-af", filter, "-f", "null", "-")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg silencedetect failed: %w", err)
+	}
+	return parseSilences(stderr.Bytes()), nil
+}
+
+func parseSilences(log []byte) []Silence {
+	var silences []Silence
+	start := int64(-1)
+	sc := bufio.NewScanner(bytes.NewReader(log))
+	for sc.Scan() {
+		line := sc.Text()
+		if m := silenceStartRe.FindStringSubmatch(line); m != nil {
+			if v, err := strconv.ParseFloat(m[1], 64); err == nil {
+				start = max(int64(v*1000), 0)
+			}
+			continue
+		}
+		if m := silenceEndRe.FindStringSubmatch(line); m != nil && start >= 0 {
+			if v, err := strconv.ParseFloat(m[1], 64); err == nil {
+				silences = append(silences, Silence{StartMs: start, EndMs: int64(v * 1000)})
+			}
+			start = -1
+		}
+	}
+	return silences
+}
+
+// Plan 规划切片：在目标切点前的静音中点处切分；找不到静音时按固定窗口切分并保留重叠
+func Plan(durationMs int64, silences []Silence, opt Options) []Chunk {
+	if opt.WindowMs <= 0 || durationMs <= opt.WindowMs {
+		return []Chunk{{StartMs: 0, EndMs: durationMs, KeepFrom: 0, KeepTo: durationMs}}
+	}
+	if opt.OverlapMs < 0 || opt.OverlapMs >= opt.WindowMs/2 {
+		opt.OverlapMs = 0
+	}
+	if opt.SlackMs <= 0 || opt.SlackMs >= opt.WindowMs {
+		opt.SlackMs = opt.WindowMs / 5
+	}
+
+	var chunks []Chunk
+	start := int64(0)
+	for start < durationMs {
+		target := start + opt.WindowMs
+		if target >= durationMs {
+			chunks = append(chunks, Chunk{StartMs: start, EndMs: durationMs})
+			break
+		}
+		if cut, ok := silenceCut(silences, target-opt.SlackMs, target); ok {
+			chunks = append(chunks, Chunk{StartMs: start, EndMs: cut, BySilence: true})
+			start = cut
+			continue
+		}
+		chunks = append(chunks, Chunk{StartMs: start, EndMs: target})
+		start = target - opt.OverlapMs
+	}
+
+	// 重叠区间以中点为界分配给前后两个切片
+	for i := range chunks {
+		chunks[i].Index = i
+		chunks[i].KeepFrom = chunks[i].StartMs
+		chunks[i].KeepTo = chunks[i].EndMs
+		if i > 0 && chunks[i].StartMs < chunks[i-1].EndMs {
+			mid := (chunks[i].StartMs + chunks[i-1].EndMs) / 2
+			chunks[i-1].KeepTo = mid
+			chunks[i].KeepFrom = mid
+		}
+	}
+	chunks[len(chunks)-1].KeepTo = durationMs + 1
+	return chunks
+}
+
+// silenceCut 在 [from, to] 内选择最靠近 to 的静音，返回其中点
+func silenceCut(silences []Silence, from, to int64) (int64, bool) {
+	best, found := int64(0), false
+	for _, s := range silences {
+		mid := (s.StartMs + s.EndMs) / 2
+		if mid > from && mid <= to && (!found || mid > best) {
+			best, found = mid, true
+		}
+	}
+	return best, found
+}
+
+// Extract 将切片转码为 16kHz 单声道 mp3 写入 outDir，返回文件路径
+func Extract(ctx context.Context, input string, c Chunk, outDir string) (string, error) {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return "", err
+	}
+	out := filepath.Join(outDir, fmt.Sprintf("chunk_%03d.mp3", c.Index))
+	args := []string{
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-ss", formatSec(c.StartMs), "-t", formatSec(c.EndMs - c.StartMs),
+		"-i", input,
+		"-vn", "-ac", "1", "-ar", "16000", "-c:a", "libmp3lame", "-b:a", "64k",
+		out,
+	}
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(cctx, getFFmpeg(), args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("ffmpeg extract chunk %d failed: %w: %s", c.Index, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+func formatSec(ms int64) string {
+	return strconv.FormatFloat(float64(ms)/1000, 'f', 3, 64)
+}
@@ -0,0 +1,256 @@
+package audiochunk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoMP3Frames 数据中没有可识别的 MPEG Layer III 帧
+	ErrNoMP3Frames = errors.New("audiochunk: no mp3 frames")
+	// ErrSampleRateMismatch 各段采样率不一致，不能直接按帧拼接
+	ErrSampleRateMismatch = errors.New("audiochunk: sample rate mismatch")
+)
+
+// MP3Info 一段 mp3 的帧信息；DurationMs 为去除编码延迟与补齐后的可播放时长
+type MP3Info struct {
+	SampleRate int
+	Frames     int
+	Samples    int // 全部帧的采样数，含编码延迟与补齐
+	Delay      int // 编码延迟采样数，无 LAME 扩展时为 0
+	DurationMs int64
+	OffsetMs   int64 // 拼接后本段可播放部分在整段中的起点，仅由 JoinMP3Frames/ConcatMP3 填写
+}
+
+var (
+	mp3BitratesV1 = [16]int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0}
+	mp3BitratesV2 = [16]int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0}
+	mp3Rates      = map[byte][3]int{3: {44100, 48000, 32000}, 2: {22050, 24000, 16000}, 0: {11025, 12000, 8000}}
+)
+
+// mp3Frame 帧头解析结果
+type mp3Frame struct {
+	size       int
+	sampleRate int
+	samples    int // 每帧采样数
+	sideInfo   int // 帧头之后的边信息长度，Xing/Info 标签紧随其后
+}
+
+// parseMP3Header 解析 4 字节帧头，仅支持 Layer III
+func parseMP3Header(b []byte) (mp3Frame, bool) {
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return mp3Frame{}, false
+	}
+	version := (b[1] >> 3) & 3
+	layer := (b[1] >> 1) & 3
+	brIndex := b[2] >> 4
+	srIndex := (b[2] >> 2) & 3
+	if version == 1 || layer != 1 || brIndex == 0 || brIndex == 15 || srIndex == 3 {
+		return mp3Frame{}, false
+	}
+	padding := int((b[2] >> 1) & 1)
+	mono := (b[3]>>6)&3 == 3
+	f := mp3Frame{sampleRate: mp3Rates[version][srIndex]}
+	if version == 3 {
+		f.samples = 1152
+		f.size = 144000*mp3BitratesV1[brIndex]/f.sampleRate + padding
+		f.sideInfo = 32
+		if mono {
+			f.sideInfo = 17
+		}
+	} else {
+		f.samples = 576
+		f.size = 72000*mp3BitratesV2[brIndex]/f.sampleRate + padding
+		f.sideInfo = 17
+		if mono {
+			f.sideInfo = 9
+		}
+	}
+	return f, f.size > 4
+}
+
+// splitMP3Frames 去掉 ID3v2/ID3v1 标签与 Xing/Info/VBRI 信息帧，返回音频帧数据与帧信息
+func splitMP3Frames(data []byte) ([]byte, MP3Info, error) {
+	if len(data) >= 10 && string(data[:3]) == "ID3" {
+		size := int(data[6]&0x7F)<<21 | int(data[7]&0x7F)<<14 | int(data[8]&0x7F)<<7 | int(data[9]&0x7F)
+		size += 10
+		if data[5]&0x10 != 0 {
+			size += 10 // footer
+		}
+		data = data[min(size, len(data)):]
+	}
+	if len(data) >= 128 && string(data[len(data)-128:len(data)-125]) == "TAG" {
+		data = data[:len(data)-128]
+	}
+
+	var out bytes.Buffer
+	var info MP3Info
+	delay, padding := 0, 0
+	samplesPerFrame := 0
+	for pos := 0; pos+4 <= len(data); {
+		f, ok := parseMP3Header(data[pos:])
+		if !ok || pos+f.size > len(data) {
+			pos++ // 重新同步
+			continue
+		}
+		frame := data[pos : pos+f.size]
+		pos += f.size
+		if info.Frames == 0 {
+			if d, p, isTag := parseXingFrame(frame, f); isTag {
+				delay, padding = d, p
+				continue
+			}
+		}
+		if info.SampleRate == 0 {
+			info.SampleRate, samplesPerFrame = f.sampleRate, f.samples
+		} else if f.sampleRate != info.SampleRate {
+			continue // 流中混入的异常帧
+		}
+		out.Write(frame)
+		info.Frames++
+	}
+	if info.Frames == 0 {
+		return nil, info, ErrNoMP3Frames
+	}
+	info.Samples, info.Delay = info.Frames*samplesPerFrame, delay
+	samples := max(info.Samples-delay-padding, 0)
+	info.DurationMs = int64(samples) * 1000 / int64(info.SampleRate)
+	return out.Bytes(), info, nil
+}
+
+// parseXingFrame 识别首帧中的 Xing/Info/VBRI 信息帧，存在 LAME 扩展时返回编码延迟与补齐采样数
+func parseXingFrame(frame []byte, f mp3Frame) (int, int, bool) {
+	if len(frame) >= 4+32+4 && string(frame[36:40]) == "VBRI" {
+		return 0, 0, true
+	}
+	off := 4 + f.sideInfo
+	if len(frame) < off+8 {
+		return 0, 0, false
+	}
+	tag := string(frame[off : off+4])
+	if tag != "Xing" && tag != "Info" {
+		return 0, 0, false
+	}
+	flags := frame[off+7]
+	p := off + 8
+	if flags&1 != 0 {
+		p += 4 // 帧数
+	}
+	if flags&2 != 0 {
+		p += 4 // 字节数
+	}
+	if flags&4 != 0 {
+		p += 100 // TOC
+	}
+	if flags&8 != 0 {
+		p += 4 // 质量
+	}
+	// LAME 扩展：9 字节编码器版本 + 12 字节其它信息后是 3 字节 延迟(12bit)|补齐(12bit)
+	if len(frame) >= p+24 && (string(frame[p:p+4]) == "LAME" || string(frame[p:p+4]) == "Lavc" || string(frame[p:p+4]) == "Lavf") {
+		d := frame[p+21 : p+24]
+		return int(d[0])<<4 | int(d[1])>>4, int(d[1]&0x0F)<<8 | int(d[2]), true
+	}
+	return 0, 0, true
+}
+
+// ProbeMP3 解析 mp3 的采样率、帧数与可播放时长
+func ProbeMP3(data []byte) (MP3Info, error) {
+	_, info, err := splitMP3Frames(data)
+	return info, err
+}
+
+// JoinMP3Frames 按帧拼接多段 mp3：去掉各段的标签与信息帧，要求采样率一致；
+// 信息帧去掉后播放器不再跳过各段的编码延迟与补齐，OffsetMs 按之前各段的全部帧计算
+func JoinMP3Frames(parts [][]byte) ([]byte, []MP3Info, error) {
+	var out bytes.Buffer
+	infos := make([]MP3Info, len(parts))
+	samples := 0
+	for i, part := range parts {
+		frames, info, err := splitMP3Frames(part)
+		if err != nil {
+			return nil, nil, fmt.Errorf("part %d: %w", i, err)
+		}
+		if i > 0 && info.SampleRate != infos[0].SampleRate {
+			return nil, nil, fmt.Errorf("part %d: %w: %d != %d", i, ErrSampleRateMismatch, info.SampleRate, infos[0].SampleRate)
+		}
+		info.OffsetMs = int64(samples+info.Delay) * 1000 / int64(info.SampleRate)
+		samples += info.Samples
+		infos[i] = info
+		out.Write(frames)
+	}
+	return out.Bytes(), infos, nil
+}
+
+// ConcatMP3 拼接多段 mp3 为一个文件：ffmpeg 可用时解码后统一重采样并重新编码（去除段间的编码延迟，
+// 无缝衔接），否则按帧拼接（要求采样率一致）；sampleRate 为输出采样率，返回每段的帧信息，
+// 两种方式下各段的 OffsetMs 都是其在输出中的实际起点
+func ConcatMP3(ctx context.Context, parts [][]byte, sampleRate int) ([]byte, []MP3Info, error) {
+	infos := make([]MP3Info, len(parts))
+	for i, part := range parts {
+		info, err := ProbeMP3(part)
+		if err != nil {
+			return nil, nil, fmt.Errorf("part %d: %w", i, err)
+		}
+		infos[i] = info
+	}
+	if len(parts) == 1 {
+		return parts[0], infos, nil
+	}
+	if Available() {
+		out, err := concatWithFFmpeg(ctx, parts, sampleRate)
+		if err == nil {
+			var offset int64
+			for i := range infos {
+				infos[i].OffsetMs = offset
+				offset += infos[i].DurationMs
+			}
+			return out, infos, nil
+		}
+		log.Printf("[AudioChunk] ffmpeg 拼接失败，回退为按帧拼接 - Parts: %d, Error: %v", len(parts), err)
+	}
+	return JoinMP3Frames(parts)
+}
+
+func concatWithFFmpeg(ctx context.Context, parts [][]byte, sampleRate int) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "mp3concat-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	args := []string{"-hide_banner", "-loglevel", "error", "-y"}
+	var filter strings.Builder
+	for i, part := range parts {
+		name := filepath.Join(dir, fmt.Sprintf("part_%03d.mp3", i))
+		if err := os.WriteFile(name, part, 0o644); err != nil {
+			return nil, err
+		}
+		args = append(args, "-i", name)
+		fmt.Fprintf(&filter, "[%d:a]aresample=%d[a%d];", i, sampleRate, i)
+	}
+	for i := range parts {
+		fmt.Fprintf(&filter, "[a%d]", i)
+	}
+	fmt.Fprintf(&filter, "concat=n=%d:v=0:a=1[out]", len(parts))
+	out := filepath.Join(dir, "out.mp3")
+	args = append(args, "-filter_complex", filter.String(), "-map", "[out]",
+		"-ar", fmt.Sprint(sampleRate), "-c:a", "libmp3lame", "-b:a", "128k", out)
+
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(cctx, getFFmpeg(), args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg concat failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return os.ReadFile(out)
+}
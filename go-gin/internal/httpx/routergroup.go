@@ -160,6 +160,10 @@ func wrapHandlers(handler []HandlerFunc) []gin.HandlerFunc {
 			return func(c *gin.Context) {
 				ctx := NewContext(c)
 				resp, err := h(ctx)
+				// 处理函数已自行输出响应（如文件下载），不再追加 JSON
+				if err == nil && c.Writer.Written() {
+					return
+				}
 				Handle(ctx, resp, err)
 			}
 		}(h)
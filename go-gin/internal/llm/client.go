@@ -0,0 +1,317 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-gin/internal/httpc"
+	"go-gin/internal/traceid"
+)
+
+const (
+	chatCompletionsPath = "/chat/completions"
+	embeddingsPath      = "/embeddings"
+)
+
+// Client 一个提供方的 chat/completions 客户端
+type Client struct {
+	cfg    ProviderConfig
+	http   *httpc.Client
+	stream *httpc.Client
+}
+
+// NewClient 按配置创建客户端（不注册）
+func NewClient(cfg ProviderConfig) *Client {
+	cfg.Name = strings.TrimSpace(cfg.Name)
+	cfg.BaseURL = strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	return &Client{
+		cfg:    cfg,
+		http:   httpc.NewClient().SetTimeout(cfg.Timeout),
+		stream: httpc.NewStreamingClient().SetTimeout(cfg.Timeout),
+	}
+}
+
+// Name 提供方名称
+func (c *Client) Name() string { return c.cfg.Name }
+
+// Model 提供方使用的模型
+func (c *Client) Model() string { return c.cfg.Model }
+
+// EmbeddingModel 提供方使用的向量模型，为空表示不支持 Embed
+func (c *Client) EmbeddingModel() string { return c.cfg.EmbeddingModel }
+
+type chatBody struct {
+	Model         string         `json:"model"`
+	Messages      []Message      `json:"messages"`
+	Stream        bool           `json:"stream"`
+	MaxTokens     int            `json:"max_tokens,omitempty"`
+	Temperature   *float64       `json:"temperature,omitempty"`
+	StreamOptions map[string]any `json:"stream_options,omitempty"`
+}
+
+type chatResult struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (c *Client) body(req Request, stream bool) chatBody {
+	b := chatBody{Model: c.cfg.Model, Messages: req.Messages, Stream: stream, MaxTokens: req.MaxTokens, Temperature: req.Temperature}
+	if b.MaxTokens <= 0 {
+		b.MaxTokens = c.cfg.MaxTokens
+	}
+	if stream {
+		// 流式响应在最后一帧携带用量
+		b.StreamOptions = map[string]any{"include_usage": true}
+	}
+	return b
+}
+
+func (c *Client) newRequest(ctx context.Context, client *httpc.Client, body any) *httpc.Request {
+	return c.newPathRequest(ctx, client, chatCompletionsPath, body)
+}
+
+func (c *Client) newPathRequest(ctx context.Context, client *httpc.Client, path string, body any) *httpc.Request {
+	return client.NewRequest().SetContext(ctx).POST(c.cfg.BaseURL + path).SetHeaders(map[string]string{
+		"Authorization": "Bearer " + c.cfg.ApiKey,
+		"Content-Type":  "application/json",
+	}).SetBody(body)
+}
+
+// Chat 非流式对话；连接失败、429 与 5xx 按退避重试
+func (c *Client) Chat(ctx context.Context, req Request) (*Response, error) {
+	requestId := traceid.New()
+	body := c.body(req, false)
+	var resp *Response
+	err := c.retry(ctx, requestId, func() error {
+		res, err := c.newRequest(ctx, c.http, body).Send()
+		if err != nil {
+			return err
+		}
+		var result chatResult
+		if err := json.Unmarshal(res.Body(), &result); err != nil && res.StatusCode() < http.StatusBadRequest {
+			return fmt.Errorf("llm: parse response failed: %w", err)
+		}
+		if err := c.statusError(res.StatusCode(), &result, res.String()); err != nil {
+			return err
+		}
+		if len(result.Choices) == 0 {
+			return &APIError{Provider: c.cfg.Name, Status: res.StatusCode(), Message: "empty choices"}
+		}
+		resp = &Response{
+			Provider:     c.cfg.Name,
+			Model:        result.Model,
+			Content:      strings.TrimSpace(result.Choices[0].Message.Content),
+			FinishReason: result.Choices[0].FinishReason,
+		}
+		if result.Usage != nil {
+			resp.Usage = *result.Usage
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[LLM] 对话完成 - RequestId: %s, Provider: %s, Model: %s, Tokens: %d/%d, FinishReason: %s",
+		requestId, c.cfg.Name, resp.Model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens, resp.FinishReason)
+	return resp, nil
+}
+
+// ChatStream 流式对话，每收到一段增量调用 onDelta，返回完整内容与用量；
+// 只在收到首个增量前重试，onDelta 返回错误时中止
+func (c *Client) ChatStream(ctx context.Context, req Request, onDelta func(delta string) error) (*Response, error) {
+	requestId := traceid.New()
+	body := c.body(req, true)
+	var raw io.ReadCloser
+	err := c.retry(ctx, requestId, func() error {
+		res, err := c.newRequest(ctx, c.stream, body).SetDoNotParseResponse(true).Send()
+		if err != nil {
+			return err
+		}
+		if res.StatusCode() >= http.StatusBadRequest {
+			data, _ := io.ReadAll(io.LimitReader(res.RawBody(), 4096))
+			_ = res.RawBody().Close()
+			var result chatResult
+			_ = json.Unmarshal(data, &result)
+			return c.statusError(res.StatusCode(), &result, string(data))
+		}
+		raw = res.RawBody()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = raw.Close() }()
+
+	resp := &Response{Provider: c.cfg.Name, Model: c.cfg.Model}
+	var sb strings.Builder
+	reader := bufio.NewReader(raw)
+	for {
+		line, err := reader.ReadString('\n')
+		if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data:"); ok {
+			data = strings.TrimSpace(data)
+			if data == "[DONE]" {
+				break
+			}
+			var chunk chatResult
+			if jsonErr := json.Unmarshal([]byte(data), &chunk); jsonErr != nil {
+				log.Printf("[LLM] 跳过无法解析的流式帧 - RequestId: %s, Data: %.200s", requestId, data)
+			} else if chunk.Error != nil {
+				return nil, &APIError{Provider: c.cfg.Name, Status: http.StatusOK, Message: chunk.Error.Message}
+			} else {
+				if chunk.Model != "" {
+					resp.Model = chunk.Model
+				}
+				if chunk.Usage != nil {
+					resp.Usage = *chunk.Usage
+				}
+				if len(chunk.Choices) > 0 {
+					if delta := chunk.Choices[0].Delta.Content; delta != "" {
+						sb.WriteString(delta)
+						if cbErr := onDelta(delta); cbErr != nil {
+							return nil, cbErr
+						}
+					}
+					if reason := chunk.Choices[0].FinishReason; reason != "" {
+						resp.FinishReason = reason
+					}
+				}
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			log.Printf("[LLM] 读取流式响应失败 - RequestId: %s, Error: %v", requestId, err)
+			return nil, err
+		}
+	}
+	resp.Content = strings.TrimSpace(sb.String())
+	log.Printf("[LLM] 流式对话完成 - RequestId: %s, Provider: %s, Model: %s, Tokens: %d/%d, FinishReason: %s",
+		requestId, c.cfg.Name, resp.Model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens, resp.FinishReason)
+	return resp, nil
+}
+
+type embeddingResult struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+	Usage *Usage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Embed 计算文本向量，返回顺序与 inputs 一致；重试策略同 Chat
+func (c *Client) Embed(ctx context.Context, inputs []string) ([][]float64, Usage, error) {
+	if c.cfg.EmbeddingModel == "" {
+		return nil, Usage{}, fmt.Errorf("%w: %s", ErrEmbeddingNotConfigured, c.cfg.Name)
+	}
+	if len(inputs) == 0 {
+		return nil, Usage{}, nil
+	}
+	requestId := traceid.New()
+	body := map[string]any{"model": c.cfg.EmbeddingModel, "input": inputs}
+	var vectors [][]float64
+	var usage Usage
+	err := c.retry(ctx, requestId, func() error {
+		res, err := c.newPathRequest(ctx, c.http, embeddingsPath, body).Send()
+		if err != nil {
+			return err
+		}
+		var result embeddingResult
+		if err := json.Unmarshal(res.Body(), &result); err != nil && res.StatusCode() < http.StatusBadRequest {
+			return fmt.Errorf("llm: parse embedding response failed: %w", err)
+		}
+		if err := c.statusError(res.StatusCode(), &chatResult{Error: result.Error}, res.String()); err != nil {
+			return err
+		}
+		if len(result.Data) != len(inputs) {
+			return &APIError{Provider: c.cfg.Name, Status: res.StatusCode(), Message: fmt.Sprintf("embedding count mismatch: %d/%d", len(result.Data), len(inputs))}
+		}
+		vectors = make([][]float64, len(inputs))
+		for _, d := range result.Data {
+			if d.Index < 0 || d.Index >= len(inputs) {
+				return &APIError{Provider: c.cfg.Name, Status: res.StatusCode(), Message: "embedding index out of range"}
+			}
+			vectors[d.Index] = d.Embedding
+		}
+		if result.Usage != nil {
+			usage = *result.Usage
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, Usage{}, err
+	}
+	log.Printf("[LLM] 向量计算完成 - RequestId: %s, Provider: %s, Model: %s, Inputs: %d, Tokens: %d",
+		requestId, c.cfg.Name, c.cfg.EmbeddingModel, len(inputs), usage.PromptTokens)
+	return vectors, usage, nil
+}
+
+func (c *Client) statusError(status int, result *chatResult, raw string) error {
+	if status < http.StatusBadRequest && result.Error == nil {
+		return nil
+	}
+	msg := raw
+	if result.Error != nil && result.Error.Message != "" {
+		msg = result.Error.Message
+	}
+	if len(msg) > 500 {
+		msg = msg[:500]
+	}
+	return &APIError{Provider: c.cfg.Name, Status: status, Message: msg}
+}
+
+// retry 连接失败与可重试的状态码按 500ms、1s、2s… 退避重试
+func (c *Client) retry(ctx context.Context, requestId string, fn func() error) error {
+	var err error
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && !apiErr.Retryable() {
+			break
+		}
+		if ctx.Err() != nil || attempt == c.cfg.MaxRetries {
+			break
+		}
+		log.Printf("[LLM] 请求失败，准备重试 - RequestId: %s, Provider: %s, Attempt: %d, Error: %v", requestId, c.cfg.Name, attempt+1, err)
+		select {
+		case <-time.After(defaultRetryDelay << attempt):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	log.Printf("[LLM] 请求失败 - RequestId: %s, Provider: %s, Error: %v", requestId, c.cfg.Name, err)
+	return err
+}
@@ -0,0 +1,141 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+const (
+	defaultTimeout    = 3 * time.Minute
+	defaultMaxRetries = 2
+	defaultRetryDelay = 500 * time.Millisecond
+)
+
+// ErrProviderNotFound 未配置的提供方
+var ErrProviderNotFound = errors.New("llm: provider not found")
+
+// ErrEmbeddingNotConfigured 提供方未配置向量模型
+var ErrEmbeddingNotConfigured = errors.New("llm: embedding model not configured")
+
+// ProviderConfig 一个 OpenAI 兼容的 chat/completions 提供方
+type ProviderConfig struct {
+	Name       string        `yaml:"name"`
+	BaseURL    string        `yaml:"base_url"` // 如 https://api.deepseek.com，请求 {base_url}/chat/completions
+	ApiKey     string        `yaml:"api_key"`
+	Model      string        `yaml:"model"`
+	Timeout    time.Duration `yaml:"timeout"`     // 单次请求超时（默认 3m）
+	MaxTokens  int           `yaml:"max_tokens"`  // 默认输出 token 上限，0 表示不限制
+	MaxRetries int           `yaml:"max_retries"` // 连接失败、429 与 5xx 的重试次数（默认 2，负数不重试）
+	// EmbeddingModel 向量模型，请求 {base_url}/embeddings；为空时该提供方不支持 Embed
+	EmbeddingModel string `yaml:"embedding_model"`
+}
+
+// Message 对话消息
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request 一次对话请求；MaxTokens 为 0 时使用提供方配置
+type Request struct {
+	Messages    []Message
+	MaxTokens   int
+	Temperature *float64
+}
+
+// Usage token 消耗
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Response 对话结果
+type Response struct {
+	Provider     string
+	Model        string
+	Content      string
+	FinishReason string // length 表示输出被截断
+	Usage        Usage
+}
+
+// APIError 提供方返回的错误
+type APIError struct {
+	Provider string
+	Status   int
+	Message  string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("llm: %s returned status %d: %s", e.Provider, e.Status, e.Message)
+}
+
+// Retryable 限流与服务端错误可以重试
+func (e *APIError) Retryable() bool {
+	return e.Status == 429 || e.Status >= 500
+}
+
+// System 构造 system 消息
+func System(content string) Message { return Message{Role: RoleSystem, Content: content} }
+
+// User 构造 user 消息
+func User(content string) Message { return Message{Role: RoleUser, Content: content} }
+
+var (
+	mu          sync.RWMutex
+	clients     = map[string]*Client{}
+	defaultName string
+)
+
+// Register 注册（或覆盖）提供方，第一个注册的提供方为默认提供方
+func Register(cfg ProviderConfig) *Client {
+	c := NewClient(cfg)
+	mu.Lock()
+	defer mu.Unlock()
+	clients[c.cfg.Name] = c
+	if defaultName == "" {
+		defaultName = c.cfg.Name
+	}
+	log.Printf("[LLM] 注册提供方 - Name: %s, BaseURL: %s, Model: %s", c.cfg.Name, c.cfg.BaseURL, c.cfg.Model)
+	return c
+}
+
+// SetDefault 指定默认提供方
+func SetDefault(name string) { mu.Lock(); defaultName = strings.TrimSpace(name); mu.Unlock() }
+
+// Get 按名称获取提供方，名称为空时返回默认提供方
+func Get(name string) (*Client, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if name = strings.TrimSpace(name); name == "" {
+		name = defaultName
+	}
+	c, ok := clients[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrProviderNotFound, name)
+	}
+	return c, nil
+}
+
+// Names 已注册的提供方名称
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(clients))
+	for name := range clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,201 @@
+// Package llmtest 提供本地的 OpenAI 兼容假服务，用于不依赖外部模型的测试
+package llmtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"go-gin/internal/llm"
+	"go-gin/util/retrieval"
+)
+
+// EmbeddingDims 假向量的维度
+const EmbeddingDims = 64
+
+// Reply 假服务的应答：Status 非 0 且不是 200 时返回错误状态
+type Reply struct {
+	Content      string
+	FinishReason string
+	Status       int
+}
+
+// Handler 根据请求消息生成应答
+type Handler func(messages []llm.Message) Reply
+
+// Server 本地假 chat/completions 服务
+type Server struct {
+	*httptest.Server
+	Handler Handler
+
+	mu       sync.Mutex
+	requests []Request
+	failures []int
+}
+
+// Request 假服务收到的请求
+type Request struct {
+	Model     string        `json:"model"`
+	Messages  []llm.Message `json:"messages"`
+	Stream    bool          `json:"stream"`
+	MaxTokens int           `json:"max_tokens"`
+	ApiKey    string        `json:"-"`
+}
+
+// NewServer 启动假服务，handler 为空时原样回显最后一条 user 消息
+func NewServer(handler Handler) *Server {
+	s := &Server{Handler: handler}
+	if s.Handler == nil {
+		s.Handler = Echo
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Echo 回显最后一条 user 消息
+func Echo(messages []llm.Message) Reply {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == llm.RoleUser {
+			return Reply{Content: messages[i].Content}
+		}
+	}
+	return Reply{}
+}
+
+// FailNext 接下来的请求依次返回给定的错误状态码
+func (s *Server) FailNext(statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statuses...)
+}
+
+// Requests 已收到的请求
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Config 指向假服务的提供方配置
+func (s *Server) Config(name string) llm.ProviderConfig {
+	return llm.ProviderConfig{Name: name, BaseURL: s.URL, ApiKey: "test-key", Model: "fake-model", MaxRetries: 2, EmbeddingModel: "fake-embedding"}
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/embeddings") {
+		s.serveEmbeddings(w, r)
+		return
+	}
+	if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/chat/completions") {
+		http.NotFound(w, r)
+		return
+	}
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.ApiKey = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	status := 0
+	if len(s.failures) > 0 {
+		status, s.failures = s.failures[0], s.failures[1:]
+	}
+	s.mu.Unlock()
+	if status != 0 {
+		writeError(w, status, "injected failure")
+		return
+	}
+
+	reply := s.Handler(req.Messages)
+	if reply.Status != 0 && reply.Status != http.StatusOK {
+		writeError(w, reply.Status, reply.Content)
+		return
+	}
+	if reply.FinishReason == "" {
+		reply.FinishReason = "stop"
+	}
+	usage := llm.Usage{PromptTokens: promptTokens(req.Messages), CompletionTokens: len([]rune(reply.Content))}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+
+	if !req.Stream {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"model": req.Model,
+			"choices": []map[string]any{{
+				"index":         0,
+				"message":       map[string]string{"role": llm.RoleAssistant, "content": reply.Content},
+				"finish_reason": reply.FinishReason,
+			}},
+			"usage": usage,
+		})
+		return
+	}
+
+	// 流式：每个字符一帧，最后一帧携带用量
+	w.Header().Set("Content-Type", "text/event-stream")
+	flusher, _ := w.(http.Flusher)
+	send := func(v any) {
+		b, _ := json.Marshal(v)
+		fmt.Fprintf(w, "data: %s\n\n", b)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	for _, r := range reply.Content {
+		send(map[string]any{"model": req.Model, "choices": []map[string]any{{"index": 0, "delta": map[string]string{"content": string(r)}}}})
+	}
+	send(map[string]any{"model": req.Model, "choices": []map[string]any{{"index": 0, "delta": map[string]string{}, "finish_reason": reply.FinishReason}}})
+	send(map[string]any{"model": req.Model, "choices": []any{}, "usage": usage})
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+// serveEmbeddings 词袋哈希向量：共享检索词越多的文本余弦相似度越高
+func (s *Server) serveEmbeddings(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Model string   `json:"model"`
+		Input []string `json:"input"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	data := make([]map[string]any, 0, len(req.Input))
+	tokens := 0
+	for i, text := range req.Input {
+		vec := make([]float64, EmbeddingDims)
+		for _, t := range retrieval.Tokenize(text) {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(t))
+			vec[h.Sum32()%EmbeddingDims]++
+			tokens++
+		}
+		data = append(data, map[string]any{"index": i, "embedding": vec})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"model": req.Model,
+		"data":  data,
+		"usage": llm.Usage{PromptTokens: tokens, TotalTokens: tokens},
+	})
+}
+
+func promptTokens(messages []llm.Message) int {
+	n := 0
+	for _, m := range messages {
+		n += len([]rune(m.Content))
+	}
+	return n
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]string{"message": msg}})
+}
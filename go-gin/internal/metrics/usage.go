@@ -35,3 +35,49 @@ func AddUsage(ctx context.Context, identity string, asrChars, ttsChars, requests
 	fmt.Printf("AddUsage success for identity=%s\n", identity)
 	return nil
 }
+
+// AddTranslationMemory 记录翻译记忆命中/未命中的分句数，按 (user_identity, date) 聚合
+func AddTranslationMemory(ctx context.Context, identity string, hits, misses int) error {
+	if hits <= 0 && misses <= 0 {
+		return nil
+	}
+	if identity == "" {
+		identity = "guest"
+	}
+
+	today := time.Now().Format("2006-01-02")
+	sql := `INSERT INTO usage_daily (user_identity, date, tm_hits, tm_misses, created_at)
+            VALUES (?, ?, ?, ?, NOW())
+            ON DUPLICATE KEY UPDATE
+                tm_hits = tm_hits + VALUES(tm_hits),
+                tm_misses = tm_misses + VALUES(tm_misses)`
+
+	err := db.WithContext(ctx).Exec(sql, identity, today, hits, misses).Error()
+	if err != nil {
+		fmt.Printf("AddTranslationMemory error: %v\n", err)
+	}
+	return err
+}
+
+// AddSummaryUsage 记录摘要消耗的 token，按 (user_identity, date) 聚合
+func AddSummaryUsage(ctx context.Context, identity string, tokens int) error {
+	if tokens <= 0 {
+		return nil
+	}
+	if identity == "" {
+		identity = "guest"
+	}
+
+	today := time.Now().Format("2006-01-02")
+	sql := `INSERT INTO usage_daily (user_identity, date, summary_tokens, requests, created_at)
+            VALUES (?, ?, ?, 1, NOW())
+            ON DUPLICATE KEY UPDATE
+                summary_tokens = summary_tokens + VALUES(summary_tokens),
+                requests = requests + VALUES(requests)`
+
+	err := db.WithContext(ctx).Exec(sql, identity, today, tokens).Error()
+	if err != nil {
+		fmt.Printf("AddSummaryUsage error: %v\n", err)
+	}
+	return err
+}
@@ -0,0 +1,189 @@
+package ytdl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"go-gin/util/subtitle"
+)
+
+// ErrNoSubtitles 视频没有符合条件的字幕
+var ErrNoSubtitles = errors.New("ytdl: no subtitles available")
+
+// 字幕来源
+const (
+	SubtitleSourceManual = "manual" // 上传者提供的字幕
+	SubtitleSourceAuto   = "auto"   // 平台自动生成的字幕
+)
+
+// SubtitleOptions 字幕选择条件
+type SubtitleOptions struct {
+	// Langs 按优先级排列的语言代码（en、zh…），为空时使用视频的原始语言
+	Langs []string
+	// IncludeAuto 没有人工字幕时是否使用自动字幕
+	IncludeAuto bool
+}
+
+// Subtitles 下载并解析后的字幕
+type Subtitles struct {
+	Lang   string // 字幕语言（平台原始标识，如 en-US、zh-Hans）
+	Source string // manual | auto
+	Ext    string // vtt | srv3 | srt
+	Cues   []subtitle.Cue
+}
+
+// subtitleFormat yt-dlp -J 中 subtitles / automatic_captions 的单个格式
+type subtitleFormat struct {
+	Ext  string `json:"ext"`
+	URL  string `json:"url"`
+	Data string `json:"data"` // 部分提取器（如 B站）直接内嵌字幕内容
+	Name string `json:"name"`
+}
+
+type ytDlpSubtitlesJSON struct {
+	Language          string                      `json:"language"`
+	Subtitles         map[string][]subtitleFormat `json:"subtitles"`
+	AutomaticCaptions map[string][]subtitleFormat `json:"automatic_captions"`
+}
+
+// 优先选择的字幕格式：srv3 没有滚动重复，其次 vtt、srt
+var preferredSubtitleExts = []string{"srv3", "vtt", "srt"}
+
+// FetchSubtitles 读取 yt-dlp -J 输出中的 subtitles / automatic_captions，
+// 按语言优先级先选人工字幕、再选自动字幕，下载并解析为带时间轴的字幕条目
+func FetchSubtitles(ctx context.Context, idOrURL, platform string, opt SubtitleOptions) (*Subtitles, error) {
+	bin := getBin()
+	args := []string{"-J", "--no-playlist", "--skip-download"}
+	if c := getCookies(ctx, platform); c != "" {
+		args = append(args, "--cookies", c)
+	}
+	if p := getProxy(); p != "" {
+		args = append(args, "--proxy", p)
+	}
+	args = append(args, idOrURL)
+
+	cctx, cancel := context.WithTimeout(ctx, 45*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(cctx, bin, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("yt-dlp -J failed: %w", err)
+	}
+	var data ytDlpSubtitlesJSON
+	if err := json.Unmarshal(out, &data); err != nil {
+		return nil, fmt.Errorf("parse yt-dlp json failed: %w", err)
+	}
+
+	langs := opt.Langs
+	if len(langs) == 0 && data.Language != "" {
+		langs = []string{data.Language}
+	}
+	log.Printf("ytdl: subtitles manual=%d auto=%d video_lang=%s wanted=%v", len(data.Subtitles), len(data.AutomaticCaptions), data.Language, langs)
+
+	lang, formats := pickSubtitleTrack(data.Subtitles, langs, true)
+	source := SubtitleSourceManual
+	if formats == nil && opt.IncludeAuto {
+		// 自动字幕里还有大量机器翻译的语言，只接受原始语言
+		lang, formats = pickSubtitleTrack(data.AutomaticCaptions, langs, false)
+		source = SubtitleSourceAuto
+	}
+	if formats == nil {
+		return nil, ErrNoSubtitles
+	}
+
+	f, ok := pickSubtitleFormat(formats)
+	if !ok {
+		return nil, ErrNoSubtitles
+	}
+	body := []byte(f.Data)
+	if len(body) == 0 {
+		if body, err = downloadSubtitle(ctx, f.URL); err != nil {
+			return nil, err
+		}
+	}
+	cues, err := subtitle.Parse(f.Ext, body)
+	if err != nil {
+		return nil, err
+	}
+	if len(cues) == 0 {
+		return nil, ErrNoSubtitles
+	}
+	log.Printf("ytdl: subtitles picked lang=%s source=%s ext=%s cues=%d", lang, source, f.Ext, len(cues))
+	return &Subtitles{Lang: lang, Source: source, Ext: f.Ext, Cues: cues}, nil
+}
+
+// pickSubtitleTrack 按语言优先级选择字幕轨；anyFallback 为 true 时无匹配则取任一可用轨
+func pickSubtitleTrack(tracks map[string][]subtitleFormat, langs []string, anyFallback bool) (string, []subtitleFormat) {
+	usable := func(key string) bool {
+		// 弹幕与直播聊天不是字幕
+		return key != "danmaku" && key != "live_chat" && len(tracks[key]) > 0
+	}
+	for _, want := range langs {
+		want = baseLang(want)
+		if want == "" {
+			continue
+		}
+		// YouTube 自动字幕的原始语言带 -orig 后缀，优先匹配
+		if key := want + "-orig"; usable(key) {
+			return key, tracks[key]
+		}
+		for key := range tracks {
+			if usable(key) && baseLang(key) == want {
+				return key, tracks[key]
+			}
+		}
+	}
+	if anyFallback {
+		for key := range tracks {
+			if usable(key) {
+				return key, tracks[key]
+			}
+		}
+	}
+	return "", nil
+}
+
+func pickSubtitleFormat(formats []subtitleFormat) (subtitleFormat, bool) {
+	for _, ext := range preferredSubtitleExts {
+		for _, f := range formats {
+			if f.Ext == ext && (f.URL != "" || f.Data != "") {
+				return f, true
+			}
+		}
+	}
+	return subtitleFormat{}, false
+}
+
+// baseLang en-US -> en，zh-Hans -> zh
+func baseLang(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, "-_"); i > 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+func downloadSubtitle(ctx context.Context, url string) ([]byte, error) {
+	cctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(cctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download subtitle failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download subtitle failed: status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 20<<20))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"go-gin/internal/component/db"
 	"go-gin/model"
+	"math"
 	"time"
 )
 
@@ -14,15 +15,18 @@ func NewAccountLogic() *AccountLogic { return &AccountLogic{} }
 
 // UserPackageView 是前端所需的套餐视图，包含总量/已用/余额
 type UserPackageView struct {
-	PackageId      int64   `json:"package_id"`
-	PackageName    string  `json:"package_name"`
-	QuotaASRChars  int     `json:"quota_asr_chars"`
-	QuotaTTSChars  int     `json:"quota_tts_chars"`
-	RemainASRChars int     `json:"remain_asr_chars"`
-	RemainTTSChars int     `json:"remain_tts_chars"`
-	UsedASRChars   int     `json:"used_asr_chars"`
-	UsedTTSChars   int     `json:"used_tts_chars"`
-	ExpireAt       *string `json:"expire_at"`
+	PackageId           int64   `json:"package_id"`
+	PackageName         string  `json:"package_name"`
+	QuotaASRChars       int     `json:"quota_asr_chars"`
+	QuotaTTSChars       int     `json:"quota_tts_chars"`
+	RemainASRChars      int     `json:"remain_asr_chars"`
+	RemainTTSChars      int     `json:"remain_tts_chars"`
+	UsedASRChars        int     `json:"used_asr_chars"`
+	UsedTTSChars        int     `json:"used_tts_chars"`
+	QuotaSummaryTokens  int     `json:"quota_summary_tokens"`
+	RemainSummaryTokens int     `json:"remain_summary_tokens"`
+	UsedSummaryTokens   int     `json:"used_summary_tokens"` // 摘要 token 单独计量
+	ExpireAt            *string `json:"expire_at"`
 }
 
 func clampInt(value int, min int, max int) int {
@@ -86,6 +90,7 @@ func (l *AccountLogic) Packages(ctx context.Context, identity string) ([]UserPac
 		p := pkgMap[up.PackageId]
 		quotaASR := p.QuotaASRChars
 		quotaTTS := p.QuotaTTSChars
+		quotaSummary := p.QuotaSummaryTokens
 
 		// 校验余额不能为负
 		remainASR := up.RemainASRChars
@@ -96,6 +101,7 @@ func (l *AccountLogic) Packages(ctx context.Context, identity string) ([]UserPac
 		if remainTTS < 0 {
 			remainTTS = 0
 		}
+		remainSummary := max(up.RemainSummaryTokens, 0)
 
 		// 若总配额缺失（历史数据或未设置），以余额为上限作为总配额，避免进度条失真
 		if quotaASR <= 0 {
@@ -104,10 +110,14 @@ func (l *AccountLogic) Packages(ctx context.Context, identity string) ([]UserPac
 		if quotaTTS <= 0 {
 			quotaTTS = remainTTS
 		}
+		if quotaSummary <= 0 {
+			quotaSummary = remainSummary
+		}
 
 		// 进一步保证余额不超过总配额
 		remainASR = clampInt(remainASR, 0, quotaASR)
 		remainTTS = clampInt(remainTTS, 0, quotaTTS)
+		remainSummary = clampInt(remainSummary, 0, quotaSummary)
 
 		usedASR := quotaASR - remainASR
 		usedTTS := quotaTTS - remainTTS
@@ -115,15 +125,18 @@ func (l *AccountLogic) Packages(ctx context.Context, identity string) ([]UserPac
 		usedTTS = clampInt(usedTTS, 0, quotaTTS)
 
 		views = append(views, UserPackageView{
-			PackageId:      up.PackageId,
-			PackageName:    p.Name,
-			QuotaASRChars:  quotaASR,
-			QuotaTTSChars:  quotaTTS,
-			RemainASRChars: remainASR,
-			RemainTTSChars: remainTTS,
-			UsedASRChars:   usedASR,
-			UsedTTSChars:   usedTTS,
-			ExpireAt:       up.ExpireAt,
+			PackageId:           up.PackageId,
+			PackageName:         p.Name,
+			QuotaASRChars:       quotaASR,
+			QuotaTTSChars:       quotaTTS,
+			RemainASRChars:      remainASR,
+			RemainTTSChars:      remainTTS,
+			UsedASRChars:        usedASR,
+			UsedTTSChars:        usedTTS,
+			QuotaSummaryTokens:  quotaSummary,
+			RemainSummaryTokens: remainSummary,
+			UsedSummaryTokens:   quotaSummary - remainSummary,
+			ExpireAt:            up.ExpireAt,
 		})
 	}
 
@@ -180,13 +193,17 @@ func (l *AccountLogic) Usage(ctx context.Context, identity string) ([]model.Usag
 	for _, it := range items {
 		key := normalizeDate(it.Date)
 		dayToItem[key] = model.UsageDaily{
-			Id:           it.Id,
-			UserIdentity: it.UserIdentity,
-			Date:         key,
-			ASRChars:     it.ASRChars,
-			TTSChars:     it.TTSChars,
-			Requests:     it.Requests,
-			CreatedAt:    it.CreatedAt,
+			Id:            it.Id,
+			UserIdentity:  it.UserIdentity,
+			Date:          key,
+			ASRChars:      it.ASRChars,
+			TTSChars:      it.TTSChars,
+			Requests:      it.Requests,
+			TMHits:        it.TMHits,
+			TMMisses:      it.TMMisses,
+			TMHitRatio:    hitRatio(it.TMHits, it.TMMisses),
+			SummaryTokens: it.SummaryTokens,
+			CreatedAt:     it.CreatedAt,
 		}
 	}
 
@@ -210,3 +227,29 @@ func (l *AccountLogic) Usage(ctx context.Context, identity string) ([]model.Usag
 	fmt.Printf("Returning %d total records (filled)\n", len(result))
 	return result, nil
 }
+
+// TranslationMemoryStats 翻译记忆命中统计
+type TranslationMemoryStats struct {
+	Hits     int     `json:"hits"`
+	Misses   int     `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"` // 命中率（0~1）
+}
+
+// TranslationMemory 汇总用量记录中的翻译记忆命中情况
+func (l *AccountLogic) TranslationMemory(items []model.UsageDaily) TranslationMemoryStats {
+	var stats TranslationMemoryStats
+	for _, it := range items {
+		stats.Hits += it.TMHits
+		stats.Misses += it.TMMisses
+	}
+	stats.HitRatio = hitRatio(stats.Hits, stats.Misses)
+	return stats
+}
+
+// hitRatio 命中率保留 4 位小数，没有分句时为 0
+func hitRatio(hits, misses int) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return math.Round(float64(hits)/float64(hits+misses)*10000) / 10000
+}
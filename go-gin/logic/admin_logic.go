@@ -10,8 +10,8 @@ type AdminLogic struct{}
 
 func NewAdminLogic() *AdminLogic { return &AdminLogic{} }
 
-// SeedQuota 将一批账号注册为正式账号并分配额度，有效期一个月（30天）
-func (l *AdminLogic) SeedQuota(ctx context.Context, identities []string, asrChars, ttsChars int) error {
+// SeedQuota 将一批账号注册为正式账号并分配额度（含摘要 token），有效期一个月（30天）
+func (l *AdminLogic) SeedQuota(ctx context.Context, identities []string, asrChars, ttsChars, summaryTokens int) error {
 	if len(identities) == 0 {
 		return nil
 	}
@@ -34,10 +34,10 @@ func (l *AdminLogic) SeedQuota(ctx context.Context, identities []string, asrChar
 	}
 	// 2) ensure package (fixed name)
 	if err := db.WithContext(ctx).Exec(`
-        INSERT INTO package (name, quota_asr_chars, quota_tts_chars, monthly_reset, created_at, updated_at)
-        VALUES('beta_seed', ?, ?, 0, NOW(), NOW())
-        ON DUPLICATE KEY UPDATE quota_asr_chars=VALUES(quota_asr_chars), quota_tts_chars=VALUES(quota_tts_chars), updated_at=NOW()
-    `, asrChars, ttsChars).Error(); err != nil {
+        INSERT INTO package (name, quota_asr_chars, quota_tts_chars, quota_summary_tokens, monthly_reset, created_at, updated_at)
+        VALUES('beta_seed', ?, ?, ?, 0, NOW(), NOW())
+        ON DUPLICATE KEY UPDATE quota_asr_chars=VALUES(quota_asr_chars), quota_tts_chars=VALUES(quota_tts_chars), quota_summary_tokens=VALUES(quota_summary_tokens), updated_at=NOW()
+    `, asrChars, ttsChars, summaryTokens).Error(); err != nil {
 		return err
 	}
 
@@ -52,9 +52,9 @@ func (l *AdminLogic) SeedQuota(ctx context.Context, identities []string, asrChar
 	for _, id := range identities {
 		_ = db.WithContext(ctx).Exec(`DELETE FROM user_package WHERE user_identity=?`, id).Error()
 		if err := db.WithContext(ctx).Exec(`
-            INSERT INTO user_package (user_identity, package_id, remain_asr_chars, remain_tts_chars, expire_at, created_at, updated_at)
-            VALUES(?, ?, ?, ?, ?, NOW(), NOW())
-        `, id, pkgId, asrChars, ttsChars, expireAt).Error(); err != nil {
+            INSERT INTO user_package (user_identity, package_id, remain_asr_chars, remain_tts_chars, remain_summary_tokens, expire_at, created_at, updated_at)
+            VALUES(?, ?, ?, ?, ?, ?, NOW(), NOW())
+        `, id, pkgId, asrChars, ttsChars, summaryTokens, expireAt).Error(); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,329 @@
+package logic
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-gin/const/errcode"
+	"go-gin/model"
+	"go-gin/rest/translate"
+	"go-gin/typing"
+)
+
+// 单次导入最多行数
+const glossaryImportMaxRows = 5000
+
+type GlossaryLogic struct {
+	model *model.GlossaryModel
+}
+
+func NewGlossaryLogic() *GlossaryLogic {
+	return &GlossaryLogic{model: model.NewGlossaryModel()}
+}
+
+// effectiveTerms 用户自己的术语加上共享给他的术语；同一原文以自己的译法为准
+func (l *GlossaryLogic) effectiveTerms(ctx context.Context, identity string) ([]model.GlossaryTerm, error) {
+	if identity == "" {
+		return nil, nil
+	}
+	owners, err := l.model.ListOwners(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	items, err := l.model.ListByOwners(ctx, append([]string{identity}, owners...))
+	if err != nil {
+		return nil, err
+	}
+	own := map[string]bool{}
+	for _, v := range items {
+		if v.UserIdentity == identity {
+			own[strings.ToLower(v.SourceTerm)] = true
+		}
+	}
+	terms := make([]model.GlossaryTerm, 0, len(items))
+	seen := map[string]bool{}
+	for _, v := range items {
+		key := strings.ToLower(v.SourceTerm)
+		if v.UserIdentity != identity && (own[key] || seen[key]) {
+			continue
+		}
+		seen[key] = true
+		terms = append(terms, v)
+	}
+	return terms, nil
+}
+
+// Effective 返回翻译时使用的术语及其版本，版本随术语内容变化
+func (l *GlossaryLogic) Effective(ctx context.Context, identity string) ([]translate.Term, string, error) {
+	items, err := l.effectiveTerms(ctx, identity)
+	if err != nil {
+		return nil, "", err
+	}
+	terms := make([]translate.Term, 0, len(items))
+	for _, v := range items {
+		terms = append(terms, translate.Term{Source: v.SourceTerm, Target: v.TargetTerm, CaseSensitive: v.CaseSensitive})
+	}
+	return terms, translate.GlossaryVersion(terms), nil
+}
+
+// List 获取生效的术语（含共享）
+func (l *GlossaryLogic) List(ctx context.Context, identity string) (*typing.GlossaryListReply, error) {
+	items, err := l.effectiveTerms(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	reply := &typing.GlossaryListReply{Items: make([]typing.GlossaryTermItem, 0, len(items))}
+	terms := make([]translate.Term, 0, len(items))
+	for _, v := range items {
+		reply.Items = append(reply.Items, convertGlossaryTerm(v, identity))
+		terms = append(terms, translate.Term{Source: v.SourceTerm, Target: v.TargetTerm, CaseSensitive: v.CaseSensitive})
+	}
+	reply.Version = translate.GlossaryVersion(terms)
+	return reply, nil
+}
+
+func convertGlossaryTerm(v model.GlossaryTerm, identity string) typing.GlossaryTermItem {
+	return typing.GlossaryTermItem{
+		Id:            v.Id,
+		SourceTerm:    v.SourceTerm,
+		TargetTerm:    v.TargetTerm,
+		CaseSensitive: v.CaseSensitive,
+		Owner:         v.UserIdentity,
+		Shared:        v.UserIdentity != identity,
+		UpdatedAt:     v.UpdatedAt.Format(time.DateTime),
+	}
+}
+
+// Create 新增术语
+func (l *GlossaryLogic) Create(ctx context.Context, identity string, req typing.GlossaryTermReq) (*typing.GlossaryTermItem, error) {
+	source, target := strings.TrimSpace(req.SourceTerm), strings.TrimSpace(req.TargetTerm)
+	if _, err := l.model.GetBySource(ctx, identity, source); err == nil {
+		return nil, errcode.ErrGlossaryTermExists
+	} else if !errcode.IsRecordNotFound(err) {
+		return nil, err
+	}
+	item := &model.GlossaryTerm{UserIdentity: identity, SourceTerm: source, TargetTerm: target, CaseSensitive: req.CaseSensitive}
+	if err := l.model.Add(ctx, item); err != nil {
+		return nil, err
+	}
+	log.Printf("[Glossary] 新增术语 - Identity: %s, Source: %s, Target: %s", identity, source, target)
+	v := convertGlossaryTerm(*item, identity)
+	return &v, nil
+}
+
+// Update 修改自己的术语
+func (l *GlossaryLogic) Update(ctx context.Context, identity string, req typing.GlossaryTermUpdateReq) (*typing.GlossaryTermItem, error) {
+	item, err := l.getOwn(ctx, identity, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	updates := map[string]any{}
+	if source := strings.TrimSpace(req.SourceTerm); source != "" && source != item.SourceTerm {
+		if _, err := l.model.GetBySource(ctx, identity, source); err == nil {
+			return nil, errcode.ErrGlossaryTermExists
+		}
+		updates["source_term"] = source
+	}
+	if target := strings.TrimSpace(req.TargetTerm); target != "" {
+		updates["target_term"] = target
+	}
+	if req.CaseSensitive != nil {
+		updates["case_sensitive"] = *req.CaseSensitive
+	}
+	if len(updates) > 0 {
+		if err := l.model.Update(ctx, identity, item.Id, updates); err != nil {
+			return nil, err
+		}
+	}
+	if item, err = l.getOwn(ctx, identity, req.Id); err != nil {
+		return nil, err
+	}
+	v := convertGlossaryTerm(*item, identity)
+	return &v, nil
+}
+
+// Delete 删除自己的术语
+func (l *GlossaryLogic) Delete(ctx context.Context, identity string, id int64) error {
+	if _, err := l.getOwn(ctx, identity, id); err != nil {
+		return err
+	}
+	return l.model.Delete(ctx, identity, id)
+}
+
+func (l *GlossaryLogic) getOwn(ctx context.Context, identity string, id int64) (*model.GlossaryTerm, error) {
+	item, err := l.model.Get(ctx, identity, id)
+	if err != nil {
+		if errcode.IsRecordNotFound(err) {
+			return nil, errcode.ErrGlossaryTermNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// Import 导入 CSV：source_term,target_term[,case_sensitive]，可带表头；已存在的原文覆盖译法
+func (l *GlossaryLogic) Import(ctx context.Context, identity string, r io.Reader) (*typing.GlossaryImportReply, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reply := &typing.GlossaryImportReply{Errors: []string{}}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Printf("[Glossary] 解析 CSV 失败 - Identity: %s, Line: %d, Error: %v", identity, line, err)
+			return nil, errcode.ErrGlossaryImportInvalid
+		}
+		if line > glossaryImportMaxRows {
+			reply.Errors = append(reply.Errors, fmt.Sprintf("超过 %d 行，其余行未导入", glossaryImportMaxRows))
+			break
+		}
+		if line == 1 && len(record) > 0 {
+			record[0] = strings.TrimPrefix(record[0], "\uFEFF") // Excel 导出的 UTF-8 BOM
+			if strings.EqualFold(strings.TrimSpace(record[0]), "source_term") {
+				continue // 表头
+			}
+		}
+		if len(record) < 2 {
+			reply.Skipped++
+			reply.Errors = append(reply.Errors, fmt.Sprintf("第 %d 行：至少需要原文与译文两列", line))
+			continue
+		}
+		source, target := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		if source == "" || target == "" || len([]rune(source)) > 255 || len([]rune(target)) > 255 {
+			reply.Skipped++
+			reply.Errors = append(reply.Errors, fmt.Sprintf("第 %d 行：原文或译文为空或超过 255 字符", line))
+			continue
+		}
+		caseSensitive := false
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			if caseSensitive, err = strconv.ParseBool(strings.TrimSpace(record[2])); err != nil {
+				reply.Skipped++
+				reply.Errors = append(reply.Errors, fmt.Sprintf("第 %d 行：case_sensitive 应为 true/false", line))
+				continue
+			}
+		}
+
+		_, getErr := l.model.GetBySource(ctx, identity, source)
+		item := &model.GlossaryTerm{UserIdentity: identity, SourceTerm: source, TargetTerm: target, CaseSensitive: caseSensitive}
+		if err := l.model.Upsert(ctx, item); err != nil {
+			return nil, err
+		}
+		if getErr == nil {
+			reply.Updated++
+		} else {
+			reply.Created++
+		}
+	}
+	log.Printf("[Glossary] 导入术语 - Identity: %s, Created: %d, Updated: %d, Skipped: %d", identity, reply.Created, reply.Updated, reply.Skipped)
+	return reply, nil
+}
+
+// Members 获取术语表的共享关系
+func (l *GlossaryLogic) Members(ctx context.Context, identity string) (*typing.GlossaryMembersReply, error) {
+	members, err := l.model.ListMembers(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	owners, err := l.model.ListOwners(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	inviters, err := l.model.ListInviters(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	reply := &typing.GlossaryMembersReply{Members: make([]typing.GlossaryMemberItem, 0, len(members)), SharedWithMe: owners, Invites: inviters}
+	for _, v := range members {
+		reply.Members = append(reply.Members, typing.GlossaryMemberItem{Identity: v.MemberIdentity, Status: v.Status, CreatedAt: v.CreatedAt.Format(time.DateTime)})
+	}
+	return reply, nil
+}
+
+// AddMember 邀请团队成员共享自己的术语表，对方接受后生效；
+// 不校验账号是否存在，无论是否存在都返回相同结果，避免被用来探测账号
+func (l *GlossaryLogic) AddMember(ctx context.Context, identity, member string) (*typing.GlossaryMembersReply, error) {
+	member = strings.TrimSpace(member)
+	if member == identity {
+		return nil, errcode.ErrGlossaryShareSelf
+	}
+	if err := l.model.AddShare(ctx, identity, member); err != nil {
+		return nil, err
+	}
+	log.Printf("[Glossary] 邀请共享术语表 - Owner: %s, Member: %s", identity, member)
+	return l.Members(ctx, identity)
+}
+
+// AcceptInvite 接受所有者的共享邀请
+func (l *GlossaryLogic) AcceptInvite(ctx context.Context, identity, owner string) (*typing.GlossaryMembersReply, error) {
+	ok, err := l.model.AcceptShare(ctx, owner, identity)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errcode.ErrGlossaryInviteNotFound
+	}
+	log.Printf("[Glossary] 接受术语表共享 - Owner: %s, Member: %s", owner, identity)
+	return l.Members(ctx, identity)
+}
+
+// LeaveShare 拒绝邀请或退出已接受的共享
+func (l *GlossaryLogic) LeaveShare(ctx context.Context, identity, owner string) (*typing.GlossaryMembersReply, error) {
+	if err := l.model.DeleteShare(ctx, owner, identity); err != nil {
+		return nil, err
+	}
+	return l.Members(ctx, identity)
+}
+
+// RemoveMember 取消共享
+func (l *GlossaryLogic) RemoveMember(ctx context.Context, identity, member string) (*typing.GlossaryMembersReply, error) {
+	if err := l.model.DeleteShare(ctx, identity, member); err != nil {
+		return nil, err
+	}
+	return l.Members(ctx, identity)
+}
+
+// CheckTranscript 用当前术语表校验转录译文，返回未遵守术语译法的分句
+func (l *GlossaryLogic) CheckTranscript(ctx context.Context, identity string, transcriptId int64) (*typing.GlossaryCheckReply, error) {
+	transcript, err := NewTranscriptLogic().Get(ctx, transcriptId)
+	if err != nil {
+		return nil, err
+	}
+	terms, version, err := l.Effective(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	reply := &typing.GlossaryCheckReply{
+		TranscriptId: transcript.Id,
+		Version:      version,
+		Terms:        len(translate.MatchTerms(terms, transcript.OriginalText)),
+		Violations:   []typing.GlossaryViolationItem{},
+	}
+	if reply.Terms == 0 {
+		return reply, nil
+	}
+	segments, err := model.NewTranscriptSegmentModel().ListByTranscript(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	var violations []translate.Violation
+	if len(segments) == 0 {
+		violations = translate.CheckGlossary(terms, -1, transcript.OriginalText, transcript.TranslatedText)
+	}
+	for _, seg := range segments {
+		violations = append(violations, translate.CheckGlossary(terms, seg.SegIndex, seg.OriginalText, seg.TranslatedText)...)
+	}
+	for _, v := range violations {
+		reply.Violations = append(reply.Violations, typing.GlossaryViolationItem{SegIndex: v.Segment, Source: v.Source, Target: v.Target})
+	}
+	return reply, nil
+}
@@ -2,8 +2,14 @@ package logic
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"go-gin/const/errcode"
 	"go-gin/internal/component/db"
 	"go-gin/model"
+	"go-gin/rest/tts"
+	"go-gin/typing"
+	"go-gin/util/subtitle"
 )
 
 type HistoryLogic struct{}
@@ -27,9 +33,57 @@ func (l *HistoryLogic) ListTTS(ctx context.Context, page, size int, identity str
 	// 获取总数
 	_ = baseQuery.Count(&total)
 
-	// 获取分页数据
-	q := baseQuery.Order("id desc").Limit(size).Offset((page - 1) * size)
+	// 获取分页数据（时间戳较大，列表不返回）
+	q := baseQuery.Omit("timings").Order("id desc").Limit(size).Offset((page - 1) * size)
 	_ = q.Find(&items)
 
 	return items, total, nil
 }
+
+// getTTS 获取用户自己的一条合成历史
+func (l *HistoryLogic) getTTS(ctx context.Context, identity string, id int64, item *model.TTSHistory) error {
+	return db.WithContext(ctx).Where("id = ? AND user_identity = ?", id, identity).First(item).Error()
+}
+
+// TTSTimings 获取合成记录的逐句、逐词时间戳
+func (l *HistoryLogic) TTSTimings(ctx context.Context, identity string, id int64) (*typing.TTSTimingsReply, error) {
+	var item model.TTSHistory
+	if err := l.getTTS(ctx, identity, id, &item); err != nil {
+		if errcode.IsRecordNotFound(err) {
+			return nil, errcode.ErrTTSNotFound
+		}
+		return nil, err
+	}
+	reply := &typing.TTSTimingsReply{Id: item.Id, DurationMs: item.DurationMs, Sentences: []tts.TTSSentence{}}
+	if item.Timings != "" {
+		if err := json.Unmarshal([]byte(item.Timings), &reply.Sentences); err != nil {
+			return nil, err
+		}
+	}
+	return reply, nil
+}
+
+// ExportTTSTimings 将合成记录的时间戳导出为 WebVTT 字幕；words 为 true 时每个词一条
+func (l *HistoryLogic) ExportTTSTimings(ctx context.Context, identity string, id int64, words bool) (*SubtitleFile, error) {
+	timings, err := l.TTSTimings(ctx, identity, id)
+	if err != nil {
+		return nil, err
+	}
+	cues := tts.TimingCues(timings.Sentences, words)
+	if len(cues) == 0 {
+		return nil, errcode.ErrTTSTimingsNone
+	}
+	data, err := subtitle.Render(subtitle.FormatVTT, cues, subtitle.Options{})
+	if err != nil {
+		return nil, err
+	}
+	level := "sentence"
+	if words {
+		level = "word"
+	}
+	return &SubtitleFile{
+		Name:        fmt.Sprintf("tts-%d.%s.%s", id, level, subtitle.FormatVTT.Ext()),
+		ContentType: subtitle.FormatVTT.ContentType(),
+		Data:        data,
+	}, nil
+}
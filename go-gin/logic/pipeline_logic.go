@@ -0,0 +1,241 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"go-gin/const/errcode"
+	"go-gin/internal/errorx"
+	"go-gin/model"
+	"go-gin/typing"
+)
+
+// PipelineStore 流水线与阶段的持久化，默认由 model.PipelineModel 实现
+type PipelineStore interface {
+	GetRun(ctx context.Context, id int64) (*model.PipelineRun, error)
+	ListRuns(ctx context.Context, status string, page, size int) ([]model.PipelineRun, int64, error)
+	UpdateRun(ctx context.Context, id int64, updates map[string]any) error
+	ListStages(ctx context.Context, runId int64) ([]model.PipelineStage, error)
+	SaveStage(ctx context.Context, stage *model.PipelineStage) error
+}
+
+var pipelineStore PipelineStore = model.NewPipelineModel()
+
+// SetPipelineStore 替换流水线持久化实现（测试中使用内存实现）
+func SetPipelineStore(s PipelineStore) {
+	pipelineStore = s
+}
+
+// PipelineRunner 执行流水线阶段并持久化每个阶段的输入输出；已完成的阶段直接复用输出
+type PipelineRunner struct {
+	model  PipelineStore
+	run    *model.PipelineRun
+	stages map[string]*model.PipelineStage
+}
+
+func NewPipelineRunner(ctx context.Context, run *model.PipelineRun) (*PipelineRunner, error) {
+	m := pipelineStore
+	stages, err := m.ListStages(ctx, run.Id)
+	if err != nil {
+		return nil, err
+	}
+	r := &PipelineRunner{model: m, run: run, stages: make(map[string]*model.PipelineStage, len(stages))}
+	for i := range stages {
+		r.stages[stages[i].Stage] = &stages[i]
+	}
+	return r, nil
+}
+
+// Finished 阶段是否已完成（或被跳过）
+func (r *PipelineRunner) Finished(name string) bool {
+	s, ok := r.stages[name]
+	return ok && s.Finished()
+}
+
+// Step 执行一个阶段：已完成时把保存的输出解码到 out 后返回；否则执行 fn（由 fn 填充 out），
+// 成功后保存 out 作为阶段输出，失败时记录错误并将流水线标记为失败
+func (r *PipelineRunner) Step(ctx context.Context, name string, input any, out any, fn func() error) error {
+	stage, ok := r.stages[name]
+	if ok && stage.Finished() {
+		log.Printf("[Pipeline] 复用已完成阶段 - RunId: %d, Stage: %s", r.run.Id, name)
+		if stage.Output == "" || out == nil {
+			return nil
+		}
+		return json.Unmarshal([]byte(stage.Output), out)
+	}
+	if !ok {
+		stage = &model.PipelineStage{RunId: r.run.Id, Stage: name}
+		r.stages[name] = stage
+	}
+
+	started := time.Now()
+	stage.Status = model.StageStatusRunning
+	stage.Input = encodeStageData(input)
+	stage.Output = ""
+	stage.ErrorMsg = ""
+	stage.Attempts++
+	stage.StartedAt = &started
+	stage.FinishedAt = nil
+	stage.DurationMs = 0
+	if err := r.model.SaveStage(ctx, stage); err != nil {
+		log.Printf("[Pipeline] 保存阶段失败 - RunId: %d, Stage: %s, Error: %v", r.run.Id, name, err)
+	}
+	_ = r.model.UpdateRun(ctx, r.run.Id, map[string]any{"current_stage": name})
+
+	err := fn()
+
+	finished := time.Now()
+	stage.FinishedAt = &finished
+	stage.DurationMs = finished.Sub(started).Milliseconds()
+	if err != nil {
+		stage.Status = model.StageStatusFailed
+		stage.ErrorMsg = truncateRunes(err.Error(), 500)
+	} else {
+		stage.Status = model.StageStatusDone
+		stage.Output = encodeStageData(out)
+	}
+	if saveErr := r.model.SaveStage(ctx, stage); saveErr != nil {
+		log.Printf("[Pipeline] 保存阶段结果失败 - RunId: %d, Stage: %s, Error: %v", r.run.Id, name, saveErr)
+	}
+	log.Printf("[Pipeline] 阶段结束 - RunId: %d, Stage: %s, Status: %s, Duration: %dms", r.run.Id, name, stage.Status, stage.DurationMs)
+	if err != nil {
+		r.fail(ctx, err)
+	}
+	return err
+}
+
+// Skip 将阶段标记为跳过（如命中已有转录时的音频、识别与翻译）
+func (r *PipelineRunner) Skip(ctx context.Context, name string, reason string) {
+	if r.Finished(name) {
+		return
+	}
+	stage, ok := r.stages[name]
+	if !ok {
+		stage = &model.PipelineStage{RunId: r.run.Id, Stage: name}
+		r.stages[name] = stage
+	}
+	now := time.Now()
+	stage.Status = model.StageStatusSkipped
+	stage.ErrorMsg = reason
+	stage.StartedAt = &now
+	stage.FinishedAt = &now
+	if err := r.model.SaveStage(ctx, stage); err != nil {
+		log.Printf("[Pipeline] 保存阶段失败 - RunId: %d, Stage: %s, Error: %v", r.run.Id, name, err)
+	}
+}
+
+// done 将流水线标记为完成
+func (r *PipelineRunner) done(ctx context.Context, transcriptId int64) {
+	r.run.Status = model.PipelineStatusDone
+	r.run.TranscriptId = transcriptId
+	_ = r.model.UpdateRun(ctx, r.run.Id, map[string]any{
+		"status":        model.PipelineStatusDone,
+		"transcript_id": transcriptId,
+		"error_code":    0,
+		"error_msg":     "",
+	})
+}
+
+// fail 将流水线标记为失败，业务错误保留其错误码
+func (r *PipelineRunner) fail(ctx context.Context, cause error) {
+	code := errorx.ErrCodeDefault
+	var bizErr errorx.BizError
+	if errors.As(cause, &bizErr) {
+		code = bizErr.Code
+	}
+	r.run.Status = model.PipelineStatusFailed
+	_ = r.model.UpdateRun(ctx, r.run.Id, map[string]any{
+		"status":     model.PipelineStatusFailed,
+		"error_code": code,
+		"error_msg":  truncateRunes(cause.Error(), 500),
+	})
+}
+
+func encodeStageData(v any) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func truncateRunes(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+// PipelineLogic 流水线查询与重新驱动（管理接口）
+type PipelineLogic struct {
+	model PipelineStore
+}
+
+func NewPipelineLogic() *PipelineLogic {
+	return &PipelineLogic{model: pipelineStore}
+}
+
+// List 分页查询流水线
+func (l *PipelineLogic) List(ctx context.Context, status string, page, size int) ([]model.PipelineRun, int64, error) {
+	return l.model.ListRuns(ctx, status, page, size)
+}
+
+// Detail 查询流水线及其全部阶段
+func (l *PipelineLogic) Detail(ctx context.Context, runId int64) (*typing.PipelineDetailReply, error) {
+	run, err := l.get(ctx, runId)
+	if err != nil {
+		return nil, err
+	}
+	stages, err := l.model.ListStages(ctx, run.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &typing.PipelineDetailReply{Run: run, Stages: stages}, nil
+}
+
+// PrepareRedrive 校验流水线可重新驱动（仅失败的流水线），投递到队列由调用方负责
+func (l *PipelineLogic) PrepareRedrive(ctx context.Context, runId int64) (*model.PipelineRun, error) {
+	run, err := l.get(ctx, runId)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status != model.PipelineStatusFailed {
+		return nil, errcode.ErrPipelineNotFailed
+	}
+	return run, nil
+}
+
+// Redrive 从第一个未完成的阶段继续执行，由队列 worker 调用；关联异步任务时同步更新任务状态
+func (l *PipelineLogic) Redrive(ctx context.Context, runId int64) error {
+	run, err := l.get(ctx, runId)
+	if err != nil {
+		return err
+	}
+	if run.Status == model.PipelineStatusDone {
+		log.Printf("[Pipeline] 流水线已完成，跳过 - RunId: %d", runId)
+		return nil
+	}
+	log.Printf("[Pipeline] 重新驱动 - RunId: %d, JobId: %s, Stage: %s", run.Id, run.JobId, run.CurrentStage)
+	if run.JobId != "" {
+		return NewTranscriptJobLogic().Retry(ctx, run.JobId)
+	}
+	_, err = NewTranscriptLogic().Resume(ctx, run)
+	return err
+}
+
+func (l *PipelineLogic) get(ctx context.Context, runId int64) (*model.PipelineRun, error) {
+	run, err := l.model.GetRun(ctx, runId)
+	if err != nil {
+		if errcode.IsRecordNotFound(err) {
+			return nil, errcode.ErrPipelineNotFound
+		}
+		return nil, err
+	}
+	return run, nil
+}
@@ -0,0 +1,263 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"go-gin/const/errcode"
+	"go-gin/model"
+	"go-gin/typing"
+	"go-gin/util/retrieval"
+	"go-gin/util/subtitle"
+)
+
+const (
+	searchMaxTerms        = 5
+	searchMinTermRunes    = 2   // ngram 全文索引按二字切分，更短的词无法命中
+	searchSegmentScan     = 200 // 参与聚合的分句命中上限
+	searchSegmentsPerHit  = 3   // 每个转录展示的分句数
+	searchSnippetRadius   = 40
+	searchTitleWeight     = 2.0 // 标题命中的加权
+	searchDefaultLimit    = 20
+	searchOperatorCutset  = `+-<>()~*"@'\`
+	searchScopeAll        = "all"
+	searchScopeTranscript = "transcript"
+	searchScopeTTS        = "tts"
+)
+
+// SearchStore 搜索的全文检索查询，默认由 model.SearchModel 实现；除 Cards 外都按 identity 限定范围
+type SearchStore interface {
+	Segments(ctx context.Context, identity, against string, limit int) ([]model.SegmentMatch, error)
+	Transcripts(ctx context.Context, identity, against string, limit int) ([]model.TranscriptMatch, error)
+	Titles(ctx context.Context, identity, against string, limit int) ([]model.TranscriptMatch, error)
+	Cards(ctx context.Context, transcriptIds []int64) ([]model.TranscriptCard, error)
+	TTS(ctx context.Context, identity, against string, limit int) ([]model.TTSMatch, error)
+}
+
+var searchStore SearchStore = model.NewSearchModel()
+
+// SetSearchStore 替换搜索实现（测试中使用内存实现）
+func SetSearchStore(s SearchStore) {
+	searchStore = s
+}
+
+type SearchLogic struct{}
+
+func NewSearchLogic() *SearchLogic { return &SearchLogic{} }
+
+// Search 搜索当前用户的转录（原文与译文）、视频标题与合成历史；
+// 转录按分句、全文与标题的相关度合并排序，并给出可跳转到时间点的分句
+func (l *SearchLogic) Search(ctx context.Context, identity string, req typing.SearchReq) (*typing.SearchReply, error) {
+	terms := searchTerms(req.Q)
+	if len(terms) == 0 {
+		return nil, errcode.ErrSearchQueryTooShort
+	}
+	reply := &typing.SearchReply{
+		Query:       req.Q,
+		Terms:       terms,
+		Transcripts: []typing.SearchTranscriptHit{},
+		TTS:         []typing.SearchTTSHit{},
+	}
+	// 未登录/无身份：直接返回空，避免泄露任何数据
+	if identity == "" {
+		return reply, nil
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = searchDefaultLimit
+	}
+	scope := req.Type
+	if scope == "" {
+		scope = searchScopeAll
+	}
+	against := searchAgainst(terms)
+
+	if scope == searchScopeAll || scope == searchScopeTranscript {
+		hits, err := l.searchTranscripts(ctx, identity, against, terms, limit)
+		if err != nil {
+			return nil, err
+		}
+		reply.Transcripts = hits
+	}
+	if scope == searchScopeAll || scope == searchScopeTTS {
+		items, err := searchStore.TTS(ctx, identity, against, limit)
+		if err != nil {
+			log.Printf("[Search] 搜索合成历史失败 - Identity: %s, Error: %v", identity, err)
+			return nil, err
+		}
+		for _, it := range items {
+			reply.TTS = append(reply.TTS, typing.SearchTTSHit{
+				Id:        it.Id,
+				Speaker:   it.Speaker,
+				Snippet:   retrieval.Snippet(it.TextPreview, terms, searchSnippetRadius),
+				AudioUrl:  it.AudioUrl,
+				CreatedAt: it.CreatedAt.Format(time.DateTime),
+				Score:     it.Score,
+			})
+		}
+	}
+	log.Printf("[Search] 搜索完成 - Identity: %s, Terms: %v, Transcripts: %d, TTS: %d", identity, terms, len(reply.Transcripts), len(reply.TTS))
+	return reply, nil
+}
+
+func (l *SearchLogic) searchTranscripts(ctx context.Context, identity, against string, terms []string, limit int) ([]typing.SearchTranscriptHit, error) {
+	segments, err := searchStore.Segments(ctx, identity, against, searchSegmentScan)
+	if err != nil {
+		log.Printf("[Search] 搜索分句失败 - Identity: %s, Error: %v", identity, err)
+		return nil, err
+	}
+	fulltext, err := searchStore.Transcripts(ctx, identity, against, limit)
+	if err != nil {
+		log.Printf("[Search] 搜索转录失败 - Identity: %s, Error: %v", identity, err)
+		return nil, err
+	}
+	titles, err := searchStore.Titles(ctx, identity, against, limit)
+	if err != nil {
+		log.Printf("[Search] 搜索标题失败 - Identity: %s, Error: %v", identity, err)
+		return nil, err
+	}
+
+	scores := map[int64]float64{}
+	bySegment := map[int64][]model.SegmentMatch{}
+	for _, s := range segments {
+		// 分句已按相关度降序，每个转录只保留最相关的几条
+		if len(bySegment[s.TranscriptId]) < searchSegmentsPerHit {
+			bySegment[s.TranscriptId] = append(bySegment[s.TranscriptId], s)
+			scores[s.TranscriptId] += s.Score
+		}
+	}
+	for _, t := range fulltext {
+		scores[t.TranscriptId] += t.Score
+	}
+	for _, t := range titles {
+		scores[t.TranscriptId] += t.Score * searchTitleWeight
+	}
+	ids := make([]int64, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] > ids[j]
+	})
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	cards, err := searchStore.Cards(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	cardMap := make(map[int64]model.TranscriptCard, len(cards))
+	for _, c := range cards {
+		cardMap[c.TranscriptId] = c
+	}
+	hits := make([]typing.SearchTranscriptHit, 0, len(ids))
+	for _, id := range ids {
+		card, ok := cardMap[id]
+		if !ok {
+			continue
+		}
+		hit := typing.SearchTranscriptHit{
+			TranscriptId: id,
+			Language:     card.Language,
+			SourceSite:   card.SourceSite,
+			VideoId:      card.VideoId,
+			Title:        retrieval.Snippet(card.Title, terms, len([]rune(card.Title))),
+			Url:          videoURL(card.SourceSite, card.VideoId, 0),
+			Segments:     []typing.SearchSegmentHit{},
+			Score:        scores[id],
+		}
+		segs := bySegment[id]
+		sort.Slice(segs, func(i, j int) bool { return segs[i].StartMs < segs[j].StartMs })
+		for _, s := range segs {
+			hit.Segments = append(hit.Segments, typing.SearchSegmentHit{
+				Index:   s.SegIndex,
+				StartMs: s.StartMs,
+				EndMs:   s.EndMs,
+				Start:   subtitle.FormatShortClock(s.StartMs),
+				Snippet: retrieval.Snippet(matchedText(terms, s.OriginalText, s.TranslatedText), terms, searchSnippetRadius),
+				Url:     videoURL(card.SourceSite, card.VideoId, s.StartMs),
+			})
+		}
+		hit.Snippet = retrieval.Snippet(matchedText(terms, card.OriginalText, card.TranslatedText), terms, searchSnippetRadius)
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// searchTerms 按空白切分搜索词，去掉全文检索的操作符，过短的词丢弃
+func searchTerms(q string) []string {
+	var terms []string
+	seen := map[string]bool{}
+	for _, f := range strings.Fields(q) {
+		f = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(searchOperatorCutset, r) {
+				return -1
+			}
+			return r
+		}, f)
+		key := strings.ToLower(f)
+		if len([]rune(f)) < searchMinTermRunes || seen[key] {
+			continue
+		}
+		seen[key] = true
+		terms = append(terms, f)
+		if len(terms) == searchMaxTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// searchAgainst 构造 BOOLEAN MODE 表达式：每个词按短语匹配且必须全部出现
+func searchAgainst(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = `+"` + t + `"`
+	}
+	return strings.Join(parts, " ")
+}
+
+// matchedText 返回包含搜索词的文本，原文没有命中时取译文
+func matchedText(terms []string, original, translated string) string {
+	lower := strings.ToLower(original)
+	for _, t := range terms {
+		if strings.Contains(lower, strings.ToLower(t)) {
+			return original
+		}
+	}
+	if translated != "" {
+		return translated
+	}
+	return original
+}
+
+// videoURL 原视频链接，ms 大于 0 时带上跳转时间点
+func videoURL(site, videoId string, ms int64) string {
+	if videoId == "" {
+		return ""
+	}
+	sec := ms / 1000
+	switch site {
+	case "bilibili":
+		u := "https://www.bilibili.com/video/" + videoId
+		if sec > 0 {
+			u += fmt.Sprintf("?t=%d", sec)
+		}
+		return u
+	case "youtube", "":
+		u := "https://www.youtube.com/watch?v=" + videoId
+		if sec > 0 {
+			u += fmt.Sprintf("&t=%ds", sec)
+		}
+		return u
+	}
+	return ""
+}
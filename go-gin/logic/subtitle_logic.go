@@ -0,0 +1,98 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-gin/const/errcode"
+	"go-gin/model"
+	"go-gin/util/subtitle"
+)
+
+// 字幕轨道
+const (
+	SubtitleTrackOriginal   = "original"
+	SubtitleTrackTranslated = "translated"
+	SubtitleTrackBilingual  = "bilingual"
+)
+
+type SubtitleLogic struct {
+	segmentModel *model.TranscriptSegmentModel
+	speakerModel *model.TranscriptSpeakerModel
+}
+
+func NewSubtitleLogic() *SubtitleLogic {
+	return &SubtitleLogic{
+		segmentModel: model.NewTranscriptSegmentModel(),
+		speakerModel: model.NewTranscriptSpeakerModel(),
+	}
+}
+
+// SubtitleFile 导出的字幕文件
+type SubtitleFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Export 根据转录分句生成字幕文件
+func (l *SubtitleLogic) Export(ctx context.Context, transcriptId int64, format subtitle.Format, track string) (*SubtitleFile, error) {
+	transcript, err := NewTranscriptLogic().Get(ctx, transcriptId)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := l.segmentModel.ListByTranscript(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	names, err := l.speakerModel.Names(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	if track == "" {
+		track = SubtitleTrackTranslated
+	}
+
+	cues := make([]subtitle.Cue, 0, len(segments))
+	speaker := ""
+	for _, seg := range segments {
+		text := cueText(seg, track)
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		// 说话人切换时在该条字幕前标注 "Speaker A: "
+		if seg.Speaker != "" && seg.Speaker != speaker {
+			text = model.SpeakerDisplayName(seg.Speaker, names) + ": " + text
+		}
+		speaker = seg.Speaker
+		cues = append(cues, subtitle.Cue{StartMs: seg.StartMs, EndMs: seg.EndMs, Text: text})
+	}
+	if len(cues) == 0 {
+		return nil, errcode.ErrSubtitleEmpty
+	}
+
+	data, err := subtitle.Render(format, cues, subtitle.Options{Title: fmt.Sprintf("transcript-%d", transcript.Id)})
+	if err != nil {
+		return nil, err
+	}
+	return &SubtitleFile{
+		Name:        fmt.Sprintf("transcript-%d.%s.%s", transcript.Id, track, format.Ext()),
+		ContentType: format.ContentType(),
+		Data:        data,
+	}, nil
+}
+
+func cueText(seg model.YoutubeTranscriptSegment, track string) string {
+	switch track {
+	case SubtitleTrackOriginal:
+		return seg.OriginalText
+	case SubtitleTrackBilingual:
+		if strings.TrimSpace(seg.TranslatedText) == "" || seg.TranslatedText == seg.OriginalText {
+			return seg.OriginalText
+		}
+		return seg.OriginalText + "\n" + seg.TranslatedText
+	default:
+		return seg.TranslatedText
+	}
+}
@@ -0,0 +1,359 @@
+package logic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"go-gin/const/errcode"
+	"go-gin/internal/component/redisx"
+	"go-gin/internal/llm"
+	"go-gin/internal/metrics"
+	"go-gin/model"
+	"go-gin/typing"
+	"go-gin/util/retrieval"
+	"go-gin/util/subtitle"
+)
+
+// 检索方式
+const (
+	AskRetrievalFull      = "full"      // 全文较短，直接作为上下文
+	AskRetrievalBM25      = "bm25"      // 关键词检索
+	AskRetrievalEmbedding = "embedding" // 向量检索
+)
+
+const (
+	askContextRunes  = 8000 // 上下文原文字符上限，全文不超过时不做检索
+	askChunkRunes    = 600  // 检索片段大小
+	askTopK          = 8    // 最多取用的片段数
+	askHistoryTurns  = 3    // 带入的最近问答轮数，支持追问
+	askEmbedBatch    = 64   // 单次向量请求的片段数
+	askCiteTolerance = 2000 // 引用时间戳与分句起点的最大偏差（毫秒）
+	askEmbedCacheTTL = 7 * 24 * time.Hour
+)
+
+// AskOptions 问答配置
+type AskOptions struct {
+	// Provider 回答使用的 llm 提供方名称，为空时使用默认提供方
+	Provider string
+	// Retrieval 长转录的片段检索方式：bm25 | embedding（默认 bm25）
+	Retrieval string
+	// EmbeddingProvider 向量检索使用的 llm 提供方（需配置 embedding_model），为空时使用默认提供方
+	EmbeddingProvider string
+}
+
+var askOptions = AskOptions{Retrieval: AskRetrievalBM25}
+
+// SetAskOptions 注入问答配置
+func SetAskOptions(opt AskOptions) {
+	if opt.Retrieval != AskRetrievalEmbedding {
+		opt.Retrieval = AskRetrievalBM25
+	}
+	askOptions = opt
+}
+
+// askOutput 模型输出的 JSON 结构
+type askOutput struct {
+	Answer    string   `json:"answer"`
+	Citations []string `json:"citations"`
+}
+
+// Ask 仅依据转录内容回答问题并引用分句时间戳；长转录先检索相关片段，
+// 每轮问答按用户保存为该转录下的会话历史，token 与摘要共用额度
+func (l *TranscriptLogic) Ask(ctx context.Context, identity string, req typing.YtAskReq) (*typing.YtAskReply, error) {
+	question := strings.TrimSpace(req.Question)
+	if question == "" {
+		return nil, errcode.ErrQuestionEmpty
+	}
+	transcript, err := l.Get(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := l.summaryLines(ctx, transcript)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errcode.ErrSummaryEmpty
+	}
+	if ok, _ := hasSummaryBalance(ctx, identity); !ok {
+		return nil, errcode.ErrQuotaNotEnough
+	}
+	client, err := llm.Get(askOptions.Provider)
+	if err != nil {
+		log.Printf("[Ask] 问答提供方未配置 - Provider: %s, Error: %v", askOptions.Provider, err)
+		return nil, errcode.ErrLLMUpstream
+	}
+
+	qaModel := model.NewTranscriptQaModel()
+	history, err := qaModel.Recent(ctx, transcript.Id, identity, askHistoryTurns)
+	if err != nil {
+		log.Printf("[Ask] 读取问答历史失败 - TranscriptId: %d, Error: %v", transcript.Id, err)
+		history = nil
+	}
+	// 追问常省略主语，检索时带上上一轮的问题
+	query := question
+	if len(history) > 0 {
+		query = history[len(history)-1].Question + "\n" + question
+	}
+	contextLines, method, embedTokens := retrieveAskContext(ctx, transcript.Id, lines, query)
+
+	messages := []llm.Message{llm.System(buildAskPrompt())}
+	for _, h := range history {
+		messages = append(messages, llm.User(h.Question), llm.Message{Role: llm.RoleAssistant, Content: h.Answer})
+	}
+	messages = append(messages, llm.User("转录片段：\n"+renderSummaryLines(contextLines)+"\n问题："+question))
+	resp, err := client.Chat(ctx, llm.Request{Messages: messages})
+	if err != nil {
+		log.Printf("[Ask] 回答失败 - TranscriptId: %d, Error: %v", transcript.Id, err)
+		return nil, errcode.ErrLLMUpstream
+	}
+	tokens := resp.Usage.TotalTokens + embedTokens
+	out := parseAskOutput(resp.Content)
+
+	item := &model.YoutubeTranscriptQa{
+		TranscriptId: transcript.Id,
+		UserIdentity: identity,
+		Question:     question,
+		Answer:       out.Answer,
+		Retrieval:    method,
+		Provider:     client.Name(),
+		Model:        client.Model(),
+		Tokens:       tokens,
+	}
+	item.SetCitations(resolveCitations(out.Citations, contextLines))
+	if err := qaModel.Create(ctx, item); err != nil {
+		log.Printf("[Ask] 保存问答失败 - TranscriptId: %d, Error: %v", transcript.Id, err)
+		item.CreatedAt = time.Now()
+	}
+
+	_ = metrics.AddSummaryUsage(ctx, identity, tokens)
+	if err := deductSummaryBalance(ctx, identity, tokens); err != nil {
+		log.Printf("[Ask] summary balance deduction failed: identity=%s, tokens=%d, error=%v", identity, tokens, err)
+	}
+	log.Printf("[Ask] 回答完成 - TranscriptId: %d, Retrieval: %s, ContextLines: %d, Citations: %d, Tokens: %d",
+		transcript.Id, method, len(contextLines), len(item.CitationList()), tokens)
+	return convertQa(item), nil
+}
+
+// Questions 用户在该转录下的问答历史，按时间倒序分页
+func (l *TranscriptLogic) Questions(ctx context.Context, identity string, req typing.YtQuestionsReq) (*typing.YtQuestionsReply, error) {
+	transcript, err := l.Get(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	items, err := model.NewTranscriptQaModel().List(ctx, transcript.Id, identity, req.BeforeId, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	reply := &typing.YtQuestionsReply{TranscriptId: transcript.Id, Items: []typing.YtAskReply{}}
+	if len(items) > limit {
+		items, reply.HasMore = items[:limit], true
+	}
+	for i := range items {
+		reply.Items = append(reply.Items, *convertQa(&items[i]))
+	}
+	return reply, nil
+}
+
+// ClearQuestions 清空用户在该转录下的问答历史
+func (l *TranscriptLogic) ClearQuestions(ctx context.Context, identity string, transcriptId int64) (int64, error) {
+	transcript, err := l.Get(ctx, transcriptId)
+	if err != nil {
+		return 0, err
+	}
+	return model.NewTranscriptQaModel().DeleteByUser(ctx, transcript.Id, identity)
+}
+
+// retrieveAskContext 选取回答所需的分句：全文较短时整篇使用，否则按配置的方式检索相关片段，
+// 向量检索失败时回退到关键词检索；返回按时间排序的分句、检索方式与向量消耗的 token
+func retrieveAskContext(ctx context.Context, transcriptId int64, lines []summaryLine, query string) ([]summaryLine, string, int) {
+	total := 0
+	for _, line := range lines {
+		total += len([]rune(line.Text))
+	}
+	if total <= askContextRunes {
+		return lines, AskRetrievalFull, 0
+	}
+
+	chunks := splitSummaryChunks(lines, askChunkRunes)
+	docs := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		docs[i] = askChunkText(chunk)
+	}
+
+	var hits []retrieval.Hit
+	method := AskRetrievalBM25
+	tokens := 0
+	if askOptions.Retrieval == AskRetrievalEmbedding {
+		var err error
+		if hits, tokens, err = embeddingSearch(ctx, transcriptId, docs, query); err != nil {
+			log.Printf("[Ask] 向量检索失败，回退关键词检索 - TranscriptId: %d, Error: %v", transcriptId, err)
+			hits = nil
+		} else {
+			method = AskRetrievalEmbedding
+		}
+	}
+	if method == AskRetrievalBM25 {
+		hits = retrieval.NewIndex(docs).Search(query, askTopK)
+	}
+	if len(hits) == 0 {
+		// 没有任何关键词命中（如问题与转录语言不同且无译文）时，取开头部分兜底
+		for i := range chunks {
+			hits = append(hits, retrieval.Hit{Index: i})
+		}
+	}
+
+	var picked []int
+	size := 0
+	for _, h := range hits {
+		n := len([]rune(docs[h.Index]))
+		if len(picked) >= askTopK || (len(picked) > 0 && size+n > askContextRunes) {
+			break
+		}
+		picked = append(picked, h.Index)
+		size += n
+	}
+	sort.Ints(picked)
+	var out []summaryLine
+	for _, i := range picked {
+		out = append(out, chunks[i]...)
+	}
+	return out, method, tokens
+}
+
+// askChunkText 检索用文本：原文与译文一起索引，问题与转录语言不同时也能命中
+func askChunkText(chunk []summaryLine) string {
+	var sb strings.Builder
+	for _, line := range chunk {
+		sb.WriteString(line.Text)
+		sb.WriteString("\n")
+		if line.Translated != "" {
+			sb.WriteString(line.Translated)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// embeddingSearch 片段向量按 (转录, 提供方, 内容) 缓存在 Redis，问题向量每次计算
+func embeddingSearch(ctx context.Context, transcriptId int64, docs []string, query string) ([]retrieval.Hit, int, error) {
+	client, err := llm.Get(askOptions.EmbeddingProvider)
+	if err != nil {
+		return nil, 0, err
+	}
+	sum := sha256.Sum256([]byte(strings.Join(docs, "\x00")))
+	key := fmt.Sprintf("yt:ask:emb:%d:%s:%s:%s", transcriptId, client.Name(), client.EmbeddingModel(), hex.EncodeToString(sum[:8]))
+	tokens := 0
+
+	var vectors [][]float64
+	if raw, err := redisx.Client().Get(ctx, key).Bytes(); err == nil {
+		if json.Unmarshal(raw, &vectors) != nil || len(vectors) != len(docs) {
+			vectors = nil
+		}
+	}
+	if vectors == nil {
+		for start := 0; start < len(docs); start += askEmbedBatch {
+			end := min(start+askEmbedBatch, len(docs))
+			batch, usage, err := client.Embed(ctx, docs[start:end])
+			if err != nil {
+				return nil, tokens, err
+			}
+			vectors = append(vectors, batch...)
+			tokens += usage.TotalTokens
+		}
+		if b, err := json.Marshal(vectors); err == nil {
+			if err := redisx.Client().Set(ctx, key, b, askEmbedCacheTTL).Err(); err != nil {
+				log.Printf("[Ask] 缓存片段向量失败 - TranscriptId: %d, Error: %v", transcriptId, err)
+			}
+		}
+	}
+
+	qv, usage, err := client.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, tokens, err
+	}
+	tokens += usage.TotalTokens
+	return retrieval.RankByVector(qv[0], vectors, askTopK), tokens, nil
+}
+
+func buildAskPrompt() string {
+	return "你是视频问答助手。每次提问会附上视频转录的相关片段，每行以 [时间戳] 开头。只能依据这些片段回答，不要使用片段以外的知识；" +
+		"片段中没有答案时如实说明无法从视频内容中找到。用提问所用的语言回答，输出严格的 JSON，不要输出 JSON 以外的任何内容，格式为：" +
+		`{"answer":"回答","citations":["MM:SS 或 H:MM:SS"]}` + "。citations 列出回答所依据的分句时间戳，必须取自片段中出现的时间戳，没有依据时为空数组。"
+}
+
+// parseAskOutput 解析模型输出，不是 JSON 时整段作为回答
+func parseAskOutput(content string) askOutput {
+	raw := content
+	if i, j := strings.Index(raw, "{"), strings.LastIndex(raw, "}"); i >= 0 && j > i {
+		raw = raw[i : j+1]
+	}
+	var out askOutput
+	if err := json.Unmarshal([]byte(raw), &out); err != nil || strings.TrimSpace(out.Answer) == "" {
+		log.Printf("[Ask] 回答不是预期的 JSON，按纯文本处理 - Content: %.200s", content)
+		return askOutput{Answer: strings.TrimSpace(content)}
+	}
+	out.Answer = strings.TrimSpace(out.Answer)
+	return out
+}
+
+// resolveCitations 将引用的时间戳对齐到上下文中的分句，丢弃对不上的引用；没有时间戳的转录不输出引用
+func resolveCitations(stamps []string, lines []summaryLine) []model.QACitation {
+	if len(lines) == 0 || lines[len(lines)-1].EndMs == 0 {
+		return nil
+	}
+	seen := map[int64]bool{}
+	var out []model.QACitation
+	for _, s := range stamps {
+		ms, ok := subtitle.ParseClock(s)
+		if !ok {
+			continue
+		}
+		best := -1
+		for i, line := range lines {
+			if abs64(line.StartMs-ms) <= askCiteTolerance && (best < 0 || abs64(line.StartMs-ms) < abs64(lines[best].StartMs-ms)) {
+				best = i
+			}
+		}
+		if best < 0 || seen[lines[best].StartMs] {
+			continue
+		}
+		seen[lines[best].StartMs] = true
+		out = append(out, model.QACitation{StartMs: lines[best].StartMs, EndMs: lines[best].EndMs, Text: lines[best].Text})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartMs < out[j].StartMs })
+	return out
+}
+
+func convertQa(item *model.YoutubeTranscriptQa) *typing.YtAskReply {
+	reply := &typing.YtAskReply{
+		Id:           item.Id,
+		TranscriptId: item.TranscriptId,
+		Question:     item.Question,
+		Answer:       item.Answer,
+		Citations:    []typing.YtAskCitation{},
+		Retrieval:    item.Retrieval,
+		Tokens:       item.Tokens,
+		CreatedAt:    item.CreatedAt.Format(time.DateTime),
+	}
+	for _, c := range item.CitationList() {
+		reply.Citations = append(reply.Citations, typing.YtAskCitation{
+			StartMs: c.StartMs,
+			EndMs:   c.EndMs,
+			Start:   subtitle.FormatShortClock(c.StartMs),
+			Text:    c.Text,
+		})
+	}
+	return reply
+}
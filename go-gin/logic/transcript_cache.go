@@ -0,0 +1,96 @@
+package logic
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"go-gin/internal/component/db"
+	"go-gin/model"
+	"go-gin/rest/translate"
+)
+
+// 转录缓存命中时的计费策略
+const (
+	TranscriptCacheBillingFull = "full" // 与重新生成一致：按最终文本字符数扣费（同 TTS 历史命中）
+	TranscriptCacheBillingFree = "free" // 命中不扣费，仅记录日志
+)
+
+// TranscriptOptions 转录运行选项，由上层（config.InitSvc）在启动时注入
+type TranscriptOptions struct {
+	// CacheTTL 已有转录的新鲜期，超过后重新识别；<=0 表示永不过期
+	CacheTTL time.Duration
+	// CacheBilling 缓存命中的计费策略：full | free（默认 full）
+	CacheBilling string
+	// CaptionMode 平台字幕使用策略：off | manual | auto（默认 manual）
+	CaptionMode string
+	// ReviewThreshold 复核接口的默认置信度阈值（1~100），低于该值的词需要复核
+	ReviewThreshold int
+}
+
+var transcriptOptions = TranscriptOptions{
+	CacheBilling:    TranscriptCacheBillingFull,
+	CaptionMode:     CaptionModeManual,
+	ReviewThreshold: 60,
+}
+
+// SetTranscriptOptions 仅当有值时覆盖默认值
+func SetTranscriptOptions(opt TranscriptOptions) {
+	if opt.CacheTTL > 0 {
+		transcriptOptions.CacheTTL = opt.CacheTTL
+	}
+	switch opt.CacheBilling {
+	case TranscriptCacheBillingFull, TranscriptCacheBillingFree:
+		transcriptOptions.CacheBilling = opt.CacheBilling
+	}
+	switch opt.CaptionMode {
+	case CaptionModeOff, CaptionModeManual, CaptionModeAuto:
+		transcriptOptions.CaptionMode = opt.CaptionMode
+	}
+	if opt.ReviewThreshold > 0 && opt.ReviewThreshold <= 100 {
+		transcriptOptions.ReviewThreshold = opt.ReviewThreshold
+	}
+}
+
+// findReusable 查找可复用的转录：用户已有编辑副本时直接复用副本（不过期）；
+// 否则查找共享转录，要求术语表版本一致、文本非空、在新鲜期内，且指定的识别语言与已识别语言一致；
+// 使用术语表的用户没有自己的结果时，只复用未经翻译（不受术语影响）的共享缓存
+func (l *TranscriptLogic) findReusable(ctx context.Context, identity string, videoId int64, targetLang, sourceLang, glossaryVersion string) (*model.YoutubeTranscript, bool) {
+	if fork, err := model.NewTranscriptModel().FindFork(ctx, identity, videoId, targetLang, glossaryVersion); err == nil {
+		return fork, true
+	}
+	var transcript model.YoutubeTranscript
+	if err := db.WithContext(ctx).Where("video_id = ? AND language = ? AND glossary_version = ? AND owner_identity = ''", videoId, targetLang, glossaryVersion).First(&transcript).Error(); err != nil {
+		if glossaryVersion == "" {
+			return nil, false
+		}
+		if err := db.WithContext(ctx).Where("video_id = ? AND language = ? AND glossary_version = '' AND owner_identity = ''", videoId, targetLang).First(&transcript).Error(); err != nil {
+			return nil, false
+		}
+		if !translate.SameLang(transcript.DetectedLang, targetLang) {
+			return nil, false
+		}
+	}
+	if strings.TrimSpace(transcript.TranslatedText) == "" {
+		return nil, false
+	}
+	if ttl := transcriptOptions.CacheTTL; ttl > 0 && time.Since(transcript.UpdatedAt) > ttl {
+		log.Printf("[Transcript] 缓存已过期 - TranscriptId: %d, UpdatedAt: %s, TTL: %s", transcript.Id, transcript.UpdatedAt.Format(time.DateTime), ttl)
+		return nil, false
+	}
+	if sourceLang != "" && sourceLang != translate.LangAuto && transcript.DetectedLang != "" && !translate.SameLang(sourceLang, transcript.DetectedLang) {
+		log.Printf("[Transcript] 缓存识别语言不一致 - TranscriptId: %d, Cached: %s, Requested: %s", transcript.Id, transcript.DetectedLang, sourceLang)
+		return nil, false
+	}
+	return &transcript, true
+}
+
+// billCacheHit 按缓存命中计费策略记录使用量并扣费
+func (l *TranscriptLogic) billCacheHit(ctx context.Context, identity string, transcript *model.YoutubeTranscript) transcriptBill {
+	if transcriptOptions.CacheBilling == TranscriptCacheBillingFree {
+		log.Printf("[Transcript] 缓存命中免计费 - Identity: %s, TranscriptId: %d", identity, transcript.Id)
+		return transcriptBill{Policy: TranscriptCacheBillingFree}
+	}
+	return l.bill(ctx, identity, transcript.TextSource, transcript.AsrCharCount, transcript.TranslateCharCount, transcript.TranslatedText)
+}
@@ -0,0 +1,61 @@
+package logic
+
+import (
+	"context"
+	"log"
+
+	"go-gin/internal/ytdl"
+	"go-gin/model"
+	"go-gin/rest/asr"
+	"go-gin/rest/dlyt"
+	"go-gin/rest/translate"
+)
+
+// 平台字幕使用策略
+const (
+	CaptionModeOff    = "off"    // 不使用平台字幕，始终语音识别
+	CaptionModeManual = "manual" // 仅使用人工字幕
+	CaptionModeAuto   = "auto"   // 人工字幕优先，其次平台自动字幕
+)
+
+// transcriptCaptions captions 阶段输出；Source 为空表示没有可用字幕，需要语音识别
+type transcriptCaptions struct {
+	Source string      `json:"source"`
+	Result asr.ASRResp `json:"result"`
+}
+
+// fetchCaptions 按配置获取平台字幕并转换为与 ASR 一致的结构；获取失败不影响流程
+func (l *TranscriptLogic) fetchCaptions(ctx context.Context, idOrUrl, platform, sourceLang string) transcriptCaptions {
+	mode := transcriptOptions.CaptionMode
+	if mode == CaptionModeOff {
+		return transcriptCaptions{}
+	}
+	req := dlyt.SubtitlesReq{IncludeAuto: mode == CaptionModeAuto}
+	if sourceLang != "" && sourceLang != translate.LangAuto {
+		req.Langs = []string{sourceLang}
+	}
+	subs, err := dlyt.Svc.SubtitlesWithPlatform(ctx, idOrUrl, platform, req)
+	if err != nil {
+		log.Printf("[Transcript] 无可用平台字幕，使用语音识别 - Mode: %s, Error: %v", mode, err)
+		return transcriptCaptions{}
+	}
+
+	lang := translate.NormalizeLang(subs.Lang)
+	result := asr.ASRResp{Language: lang, Segments: make([]asr.Segment, 0, len(subs.Cues))}
+	texts := make([]string, 0, len(subs.Cues))
+	for _, c := range subs.Cues {
+		result.Segments = append(result.Segments, asr.Segment{StartMs: c.StartMs, EndMs: c.EndMs, Text: c.Text})
+		texts = append(texts, c.Text)
+	}
+	result.Text = translate.JoinTexts(texts, lang)
+	result.CharCount = len([]rune(result.Text))
+	if result.Text == "" {
+		return transcriptCaptions{}
+	}
+
+	source := model.TranscriptSourceManualCaption
+	if subs.Source == ytdl.SubtitleSourceAuto {
+		source = model.TranscriptSourceAutoCaption
+	}
+	return transcriptCaptions{Source: source, Result: result}
+}
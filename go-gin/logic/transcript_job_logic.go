@@ -0,0 +1,146 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go-gin/const/errcode"
+	"go-gin/internal/component/db"
+	"go-gin/internal/errorx"
+	"go-gin/model"
+	"go-gin/typing"
+
+	"github.com/google/uuid"
+)
+
+type TranscriptJobLogic struct {
+	model *model.TranscriptJobModel
+}
+
+func NewTranscriptJobLogic() *TranscriptJobLogic {
+	return &TranscriptJobLogic{
+		model: model.NewTranscriptJobModel(),
+	}
+}
+
+// Submit 创建转录任务记录（投递到队列由调用方负责）
+func (l *TranscriptJobLogic) Submit(ctx context.Context, identity string, req typing.YtTextReq) (*model.TranscriptJob, error) {
+	// 与同步接口一致：无余额的请求不进入队列
+	if ok, _ := NewTranscriptLogic().hasEnoughASRBalance(ctx, identity); !ok {
+		return nil, errcode.ErrQuotaNotEnough
+	}
+
+	targetLang, sourceLang, err := normalizeTranscriptLangs(req.TargetLan, req.SourceLang)
+	if err != nil {
+		return nil, err
+	}
+	job := &model.TranscriptJob{
+		JobId:        uuid.New().String(),
+		UserIdentity: identity,
+		IdOrUrl:      req.IdOrUrl,
+		Platform:     req.Platform,
+		TargetLang:   targetLang,
+		SourceLang:   sourceLang,
+		ForceRefresh: req.ForceRefresh,
+		Stage:        model.TranscriptJobStageQueued,
+	}
+	if err := l.model.Add(ctx, job); err != nil {
+		return nil, err
+	}
+	log.Printf("[TranscriptJob] 任务已创建 - JobId: %s, Identity: %s, IdOrUrl: %s", job.JobId, identity, req.IdOrUrl)
+	return job, nil
+}
+
+// Get 查询任务状态，仅提交人可见
+func (l *TranscriptJobLogic) Get(ctx context.Context, identity string, jobId string) (*typing.YtJobReply, error) {
+	job, err := l.model.GetByJobId(ctx, jobId)
+	if err != nil {
+		if errcode.IsRecordNotFound(err) {
+			return nil, errcode.ErrTranscriptJobNotFound
+		}
+		return nil, err
+	}
+	if identity == "" || job.UserIdentity != identity {
+		return nil, errcode.ErrTranscriptJobNotFound
+	}
+
+	reply := &typing.YtJobReply{
+		JobId:        job.JobId,
+		Stage:        job.Stage,
+		ErrorCode:    job.ErrorCode,
+		ErrorMsg:     job.ErrorMsg,
+		TranscriptId: job.TranscriptId,
+	}
+	if job.Stage == model.TranscriptJobStageDone && job.TranscriptId != 0 {
+		var transcript model.YoutubeTranscript
+		if err := db.WithContext(ctx).Where("id = ?", job.TranscriptId).First(&transcript).Error(); err == nil {
+			reply.TranslatedText = transcript.TranslatedText
+		}
+	}
+	return reply, nil
+}
+
+// Run 执行转录任务，由队列 worker 调用
+func (l *TranscriptJobLogic) Run(ctx context.Context, jobId string) error {
+	job, err := l.model.GetByJobId(ctx, jobId)
+	if err != nil {
+		return err
+	}
+	// 已结束的任务不重复执行
+	if job.Stage == model.TranscriptJobStageDone || job.Stage == model.TranscriptJobStageFailed {
+		log.Printf("[TranscriptJob] 任务已结束，跳过 - JobId: %s, Stage: %s", jobId, job.Stage)
+		return nil
+	}
+
+	tl := NewTranscriptLogic().WithStageHook(func(ctx context.Context, stage string) {
+		if err := l.model.UpdateStage(ctx, jobId, stage); err != nil {
+			log.Printf("[TranscriptJob] 更新阶段失败 - JobId: %s, Stage: %s, Error: %v", jobId, stage, err)
+		}
+	})
+
+	// 已有流水线时从未完成的阶段继续，否则新建
+	run, err := model.NewPipelineModel().GetRunByJobId(ctx, jobId)
+	if err != nil {
+		if !errcode.IsRecordNotFound(err) {
+			return err
+		}
+		run, err = tl.Start(ctx, job.UserIdentity, jobId, TranscriptParams{
+			IdOrUrl:      job.IdOrUrl,
+			Platform:     job.Platform,
+			TargetLang:   job.TargetLang,
+			SourceLang:   job.SourceLang,
+			ForceRefresh: job.ForceRefresh,
+		})
+		if err != nil {
+			_ = l.Fail(ctx, jobId, err)
+			return err
+		}
+	}
+	tr, err := tl.Resume(ctx, run)
+	if err != nil {
+		_ = l.Fail(ctx, jobId, err)
+		return err
+	}
+	log.Printf("[TranscriptJob] 任务完成 - JobId: %s, TranscriptId: %d", jobId, tr.Id)
+	return l.model.MarkDone(ctx, jobId, tr.Id)
+}
+
+// Retry 将失败的任务重置为排队状态后重新执行，流水线从第一个未完成的阶段继续
+func (l *TranscriptJobLogic) Retry(ctx context.Context, jobId string) error {
+	if err := l.model.UpdateStage(ctx, jobId, model.TranscriptJobStageQueued); err != nil {
+		return err
+	}
+	return l.Run(ctx, jobId)
+}
+
+// Fail 将任务标记为失败，业务错误保留其错误码
+func (l *TranscriptJobLogic) Fail(ctx context.Context, jobId string, cause error) error {
+	code := errorx.ErrCodeDefault
+	var bizErr errorx.BizError
+	if errors.As(cause, &bizErr) {
+		code = bizErr.Code
+	}
+	log.Printf("[TranscriptJob] 任务失败 - JobId: %s, Code: %d, Error: %v", jobId, code, cause)
+	return l.model.MarkFailed(ctx, jobId, code, cause.Error())
+}
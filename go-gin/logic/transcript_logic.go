@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"log"
 	"net/url"
 	"strings"
@@ -15,12 +16,29 @@ import (
 	"go-gin/rest/asr"
 	"go-gin/rest/dlyt"
 	"go-gin/rest/translate"
+	"go-gin/transformer"
+	"go-gin/typing"
 )
 
-type TranscriptLogic struct{}
+type TranscriptLogic struct {
+	// onStage 阶段回调，异步任务用它上报进度；同步调用时为空
+	onStage func(ctx context.Context, stage string)
+}
 
 func NewTranscriptLogic() *TranscriptLogic { return &TranscriptLogic{} }
 
+// WithStageHook 设置阶段回调（downloading/recognizing/translating）
+func (l *TranscriptLogic) WithStageHook(fn func(ctx context.Context, stage string)) *TranscriptLogic {
+	l.onStage = fn
+	return l
+}
+
+func (l *TranscriptLogic) reportStage(ctx context.Context, stage string) {
+	if l.onStage != nil {
+		l.onStage(ctx, stage)
+	}
+}
+
 // GetOrCreate 流程：dlyt.Info -> upsert youtube_video -> dlyt.Audio -> ASR -> Translate -> upsert youtube_transcript
 func (l *TranscriptLogic) GetOrCreate(ctx context.Context, idOrUrl string, targetLang string, identity string) (*model.YoutubeTranscript, error) {
 	return l.GetOrCreateWithPlatform(ctx, idOrUrl, targetLang, identity, "")
@@ -28,11 +46,56 @@ func (l *TranscriptLogic) GetOrCreate(ctx context.Context, idOrUrl string, targe
 
 // GetOrCreateWithPlatform 带平台参数的转录处理方法
 func (l *TranscriptLogic) GetOrCreateWithPlatform(ctx context.Context, idOrUrl string, targetLang string, identity string, platform string) (*model.YoutubeTranscript, error) {
-	log.Printf("[Transcript] 开始处理转录请求 - IdOrUrl: %s, TargetLang: %s, Identity: %s, Platform: %s", idOrUrl, targetLang, identity, platform)
+	return l.Process(ctx, identity, TranscriptParams{IdOrUrl: idOrUrl, Platform: platform, TargetLang: targetLang})
+}
+
+// TranscriptParams 转录请求参数
+type TranscriptParams struct {
+	IdOrUrl    string
+	Platform   string
+	TargetLang string // 目标语言，默认 zh
+	SourceLang string // 识别语言：auto 或语言代码，为空时使用配置的默认值
+	// ForceRefresh 忽略已有转录，重新识别与翻译
+	ForceRefresh bool
+}
 
+// normalizeTranscriptLangs 规范化并校验目标语言与识别语言
+func normalizeTranscriptLangs(targetLang, sourceLang string) (string, string, error) {
 	if targetLang == "" {
 		targetLang = "zh"
 	}
+	targetLang = translate.NormalizeLang(targetLang)
+	if !translate.IsSupported(targetLang) {
+		return "", "", errcode.ErrLangNotSupported
+	}
+	if sourceLang != "" {
+		sourceLang = translate.NormalizeLang(sourceLang)
+		if !asr.IsSupportedLang(sourceLang) {
+			return "", "", errcode.ErrLangNotSupported
+		}
+	}
+	return targetLang, sourceLang, nil
+}
+
+// Process 执行完整的转录流程：创建流水线并依次执行各阶段
+func (l *TranscriptLogic) Process(ctx context.Context, identity string, p TranscriptParams) (*model.YoutubeTranscript, error) {
+	run, err := l.Start(ctx, identity, "", p)
+	if err != nil {
+		return nil, err
+	}
+	return l.Resume(ctx, run)
+}
+
+// Start 校验参数与余额后创建转录流水线，jobId 为关联的异步任务（同步请求为空）
+func (l *TranscriptLogic) Start(ctx context.Context, identity string, jobId string, p TranscriptParams) (*model.PipelineRun, error) {
+	log.Printf("[Transcript] 开始处理转录请求 - IdOrUrl: %s, TargetLang: %s, SourceLang: %s, Identity: %s, Platform: %s",
+		p.IdOrUrl, p.TargetLang, p.SourceLang, identity, p.Platform)
+
+	targetLang, sourceLang, err := normalizeTranscriptLangs(p.TargetLang, p.SourceLang)
+	if err != nil {
+		return nil, err
+	}
+	p.TargetLang, p.SourceLang = targetLang, sourceLang
 
 	// 预检：若当前 ASR 余额<=0，则直接拒绝（允许上一轮用完为0或被扣至0后，下一次不再允许）
 	ok, _ := l.hasEnoughASRBalance(ctx, identity)
@@ -40,135 +103,489 @@ func (l *TranscriptLogic) GetOrCreateWithPlatform(ctx context.Context, idOrUrl s
 		return nil, errcode.ErrQuotaNotEnough
 	}
 
-	log.Printf("[Transcript] Step 1: 获取视频信息")
-	info, err := dlyt.Svc.InfoWithPlatform(ctx, idOrUrl, platform)
-	if err != nil {
-		log.Printf("[Transcript] 获取视频信息失败 - Error: %v", err)
-		return nil, err
+	run := &model.PipelineRun{
+		Kind:         model.PipelineKindTranscript,
+		UserIdentity: identity,
+		JobId:        jobId,
+		Params:       encodeStageData(p),
+		Status:       model.PipelineStatusRunning,
+		Attempts:     1,
 	}
-	log.Printf("[Transcript] 视频信息获取成功 - VideoId: %s, Title: %s, Duration: %d", info.Id, info.Title, info.DurationSec)
-
-	// 根据平台确定source_site
-	sourceSite := platform
-	if sourceSite == "" {
-		sourceSite = "youtube" // 向后兼容
-	}
-
-	log.Printf("[Transcript] Step 2: 检查/创建视频记录")
-	var video model.YoutubeVideo
-	if err := db.WithContext(ctx).Where("source_site = ? AND video_id = ?", sourceSite, info.Id).First(&video).Error(); err != nil {
-		log.Printf("[Transcript] 创建新视频记录 - VideoId: %s, SourceSite: %s", info.Id, sourceSite)
-		video = model.YoutubeVideo{
-			SourceSite:   sourceSite,
-			VideoId:      info.Id,
-			Title:        info.Title,
-			ChannelTitle: info.Author,
-			DurationSec:  info.DurationSec,
-			ThumbnailUrl: info.ThumbnailUrl,
-		}
-		_ = db.WithContext(ctx).Create(&video)
-	} else {
-		log.Printf("[Transcript] 使用已存在的视频记录 - VideoId: %s, DBId: %d", info.Id, video.Id)
+	if err := model.NewPipelineModel().AddRun(ctx, run); err != nil {
+		return nil, err
 	}
+	log.Printf("[Transcript] 流水线已创建 - RunId: %d", run.Id)
+	return run, nil
+}
+
+// transcriptInfo info 阶段输出
+type transcriptInfo struct {
+	VideoDbId int64  `json:"video_db_id"`
+	VideoId   string `json:"video_id"`
+	Title     string `json:"title"`
+}
+
+// transcriptTranslation translate 阶段输出
+type transcriptTranslation struct {
+	DetectedLang       string   `json:"detected_lang"`
+	FinalText          string   `json:"final_text"`
+	TranslateCharCount int      `json:"translate_char_count"`
+	SegmentTexts       []string `json:"segment_texts"` // 与 ASR 分句一一对应的译文，nil 表示未翻译
+	GlossaryVersion    string   `json:"glossary_version,omitempty"`
+	GlossaryViolations int      `json:"glossary_violations,omitempty"` // 未遵守术语表的分句数
+	MemoryHits         int      `json:"memory_hits,omitempty"`         // 命中翻译记忆的分句数
+	MemoryMisses       int      `json:"memory_misses,omitempty"`
+}
+
+// transcriptPersist persist 阶段输出
+type transcriptPersist struct {
+	TranscriptId int64 `json:"transcript_id"`
+	CacheHit     bool  `json:"cache_hit"`
+}
 
-	log.Printf("[Transcript] Step 3: 获取音频URL")
-	audio, err := dlyt.Svc.AudioWithPlatform(ctx, idOrUrl, platform)
+// transcriptBill bill 阶段输出
+type transcriptBill struct {
+	Chars  int    `json:"chars"`
+	Policy string `json:"policy"`
+}
+
+// Resume 从第一个未完成的阶段开始执行流水线，已完成阶段的输出直接复用
+func (l *TranscriptLogic) Resume(ctx context.Context, run *model.PipelineRun) (*model.YoutubeTranscript, error) {
+	var p TranscriptParams
+	if err := json.Unmarshal([]byte(run.Params), &p); err != nil {
+		return nil, err
+	}
+	r, err := NewPipelineRunner(ctx, run)
 	if err != nil {
-		log.Printf("[Transcript] 获取音频失败 - Error: %v", err)
 		return nil, err
 	}
-	log.Printf("[Transcript] 音频获取成功 - AudioUrl: %s", audio.AudioUrl)
+	if len(r.stages) > 0 {
+		run.Attempts++
+		_ = r.model.UpdateRun(ctx, run.Id, map[string]any{"status": model.PipelineStatusRunning, "attempts": run.Attempts})
+		log.Printf("[Transcript] 续跑流水线 - RunId: %d, Attempts: %d, LastStage: %s", run.Id, run.Attempts, run.CurrentStage)
+	}
+	identity, idOrUrl, platform := run.UserIdentity, p.IdOrUrl, p.Platform
+	targetLang, sourceLang := p.TargetLang, p.SourceLang
 
-	// 验证音频URL有效性
-	if err := validateAudioUrl(audio.AudioUrl); err != nil {
-		log.Printf("[Transcript] 音频URL无效 - Error: %v", err)
+	log.Printf("[Transcript] Step 1: 获取视频信息")
+	var info transcriptInfo
+	if err := r.Step(ctx, model.PipelineStageInfo, p, &info, func() error {
+		l.reportStage(ctx, model.TranscriptJobStageDownloading)
+		resp, err := dlyt.Svc.InfoWithPlatform(ctx, idOrUrl, platform)
+		if err != nil {
+			log.Printf("[Transcript] 获取视频信息失败 - Error: %v", err)
+			return err
+		}
+		log.Printf("[Transcript] 视频信息获取成功 - VideoId: %s, Title: %s, Duration: %d", resp.Id, resp.Title, resp.DurationSec)
+
+		// 根据平台确定source_site
+		sourceSite := platform
+		if sourceSite == "" {
+			sourceSite = "youtube" // 向后兼容
+		}
+
+		log.Printf("[Transcript] Step 2: 检查/创建视频记录")
+		var video model.YoutubeVideo
+		if err := db.WithContext(ctx).Where("source_site = ? AND video_id = ?", sourceSite, resp.Id).First(&video).Error(); err != nil {
+			log.Printf("[Transcript] 创建新视频记录 - VideoId: %s, SourceSite: %s", resp.Id, sourceSite)
+			video = model.YoutubeVideo{
+				SourceSite:   sourceSite,
+				VideoId:      resp.Id,
+				Title:        resp.Title,
+				ChannelTitle: resp.Author,
+				DurationSec:  resp.DurationSec,
+				ThumbnailUrl: resp.ThumbnailUrl,
+			}
+			_ = db.WithContext(ctx).Create(&video)
+		} else {
+			log.Printf("[Transcript] 使用已存在的视频记录 - VideoId: %s, DBId: %d", resp.Id, video.Id)
+		}
+		info = transcriptInfo{VideoDbId: video.Id, VideoId: resp.Id, Title: resp.Title}
+		return nil
+	}); err != nil {
 		return nil, err
 	}
 
-	// 检查音频来源类型
-	if strings.HasPrefix(audio.AudioUrl, "/static/") {
-		log.Printf("[Transcript] 使用本地静态音频文件 - Path: %s", audio.AudioUrl)
-	} else if isQiniuUrl(audio.AudioUrl) {
-		log.Printf("[Transcript] 使用七牛存储音频文件 - URL: %s", audio.AudioUrl)
-	} else {
-		log.Printf("[Transcript] 使用外部音频URL - URL: %s", audio.AudioUrl)
+	// 按发起用户的术语表（含共享）约束译法；使用术语的结果按术语表版本单独缓存
+	terms, glossaryVersion, err := NewGlossaryLogic().Effective(ctx, identity)
+	if err != nil {
+		log.Printf("[Transcript] 获取术语表失败，按无术语翻译 - Identity: %s, Error: %v", identity, err)
+		terms, glossaryVersion = nil, ""
 	}
 
-	log.Printf("[Transcript] Step 4: 执行语音识别")
-	asrResp, err := asr.Svc.Recognize(ctx, audio.AudioUrl)
-	if err != nil {
-		log.Printf("[Transcript] 语音识别失败 - Error: %v", err)
-		return nil, err
+	// 已有可复用的转录时直接返回，不再下载音频、识别与翻译；已完成识别的续跑不再走缓存
+	if !p.ForceRefresh && info.VideoDbId != 0 && !r.Finished(model.PipelineStageASR) {
+		if cached, ok := l.findReusable(ctx, identity, info.VideoDbId, targetLang, sourceLang, glossaryVersion); ok {
+			log.Printf("[Transcript] 命中已有转录 - TranscriptId: %d, Billing: %s", cached.Id, transcriptOptions.CacheBilling)
+			for _, name := range []string{model.PipelineStageAudio, model.PipelineStageASR, model.PipelineStageTranslate} {
+				r.Skip(ctx, name, "cache hit")
+			}
+			if err := r.Step(ctx, model.PipelineStagePersist, nil, &transcriptPersist{TranscriptId: cached.Id, CacheHit: true}, func() error { return nil }); err != nil {
+				return nil, err
+			}
+			var bill transcriptBill
+			if err := r.Step(ctx, model.PipelineStageBill, nil, &bill, func() error {
+				bill = l.billCacheHit(ctx, identity, cached)
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+			r.done(ctx, cached.Id)
+			return cached, nil
+		}
 	}
-	log.Printf("[Transcript] 语音识别成功 - CharCount: %d, TextPreview: %.100s...",
-		asrResp.CharCount, asrResp.Text)
 
-	// 根据平台决定是否翻译
-	var finalText string
-	var translateCharCount int
+	// 平台已有字幕时直接使用，省去下载音频与语音识别
+	var captions transcriptCaptions
+	if err := r.Step(ctx, model.PipelineStageCaptions, map[string]string{"mode": transcriptOptions.CaptionMode, "language": sourceLang}, &captions, func() error {
+		captions = l.fetchCaptions(ctx, idOrUrl, platform, sourceLang)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
 
-	if sourceSite == "bilibili" {
-		// Bilibili视频直接使用ASR结果，不翻译
-		log.Printf("[Transcript] Step 5: Bilibili视频跳过翻译，直接使用ASR结果")
-		finalText = asrResp.Text
-		translateCharCount = 0
+	var asrResp asr.ASRResp
+	source := model.TranscriptSourceASR
+	if captions.Source != "" {
+		log.Printf("[Transcript] 使用平台字幕 - Source: %s, Language: %s, Segments: %d", captions.Source, captions.Result.Language, len(captions.Result.Segments))
+		r.Skip(ctx, model.PipelineStageAudio, "captions")
+		r.Skip(ctx, model.PipelineStageASR, "captions")
+		asrResp = captions.Result
+		source = captions.Source
 	} else {
-		// YouTube视频需要翻译
-		log.Printf("[Transcript] Step 5: 执行翻译")
-		trResp, err := translate.Svc.TranslateToZh(ctx, asrResp.Text)
+		log.Printf("[Transcript] Step 3: 获取音频URL")
+		var audio dlyt.AudioResp
+		if err := r.Step(ctx, model.PipelineStageAudio, info, &audio, func() error {
+			resp, err := dlyt.Svc.AudioWithPlatform(ctx, idOrUrl, platform)
+			if err != nil {
+				log.Printf("[Transcript] 获取音频失败 - Error: %v", err)
+				return err
+			}
+			log.Printf("[Transcript] 音频获取成功 - AudioUrl: %s", resp.AudioUrl)
+
+			// 验证音频URL有效性
+			if err := validateAudioUrl(resp.AudioUrl); err != nil {
+				log.Printf("[Transcript] 音频URL无效 - Error: %v", err)
+				return err
+			}
+
+			// 检查音频来源类型
+			if strings.HasPrefix(resp.AudioUrl, "/static/") {
+				log.Printf("[Transcript] 使用本地静态音频文件 - Path: %s", resp.AudioUrl)
+			} else if isQiniuUrl(resp.AudioUrl) {
+				log.Printf("[Transcript] 使用七牛存储音频文件 - URL: %s", resp.AudioUrl)
+			} else {
+				log.Printf("[Transcript] 使用外部音频URL - URL: %s", resp.AudioUrl)
+			}
+			audio = *resp
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		log.Printf("[Transcript] Step 4: 执行语音识别")
+		if err := r.Step(ctx, model.PipelineStageASR, map[string]string{"audio_url": audio.AudioUrl, "language": sourceLang}, &asrResp, func() error {
+			l.reportStage(ctx, model.TranscriptJobStageRecognizing)
+			resp, err := asr.Svc.Recognize(ctx, audio.AudioUrl, sourceLang)
+			if err != nil {
+				log.Printf("[Transcript] 语音识别失败 - Error: %v", err)
+				return err
+			}
+			log.Printf("[Transcript] 语音识别成功 - CharCount: %d, Language: %s, TextPreview: %.100s...",
+				resp.CharCount, resp.Language, resp.Text)
+			asrResp = *resp
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// 根据识别出的语言决定是否翻译
+	var tr transcriptTranslation
+	if err := r.Step(ctx, model.PipelineStageTranslate, map[string]string{"source_lang": asrResp.Language, "target_lang": targetLang, "glossary_version": glossaryVersion}, &tr, func() error {
+		tr = transcriptTranslation{DetectedLang: asrResp.Language}
+		if translate.SameLang(tr.DetectedLang, targetLang) {
+			// 识别语言与目标语言一致时直接使用ASR结果，不翻译
+			log.Printf("[Transcript] Step 5: 跳过翻译，直接使用ASR结果 - DetectedLang: %s, TargetLang: %s", tr.DetectedLang, targetLang)
+			tr.FinalText = asrResp.Text
+			return nil
+		}
+		// 按分句分批翻译，保证长视频不超出模型上下文
+		log.Printf("[Transcript] Step 5: 执行翻译 - %s -> %s", tr.DetectedLang, targetLang)
+		l.reportStage(ctx, model.TranscriptJobStageTranslating)
+		sources := make([]string, 0, len(asrResp.Segments))
+		for _, seg := range asrResp.Segments {
+			sources = append(sources, seg.Text)
+		}
+		if len(sources) == 0 {
+			// 无分句信息时按句末标点切分
+			sources = translate.SplitSentences(asrResp.Text)
+		}
+		trResp, err := translate.Svc.TranslateSegments(translate.WithGlossary(ctx, terms), sources, tr.DetectedLang, targetLang)
 		if err != nil {
 			log.Printf("[Transcript] 翻译失败 - Error: %v", err)
-			return nil, err
+			return err
+		}
+		tr.GlossaryVersion, tr.GlossaryViolations = glossaryVersion, len(trResp.Violations)
+		for _, v := range trResp.Violations {
+			log.Printf("[Transcript] 译文未遵守术语表 - Segment: %d, Source: %s, Target: %s", v.Segment, v.Source, v.Target)
+		}
+		tr.FinalText = translate.JoinTexts(trResp.Texts, targetLang)
+		log.Printf("[Transcript] 翻译成功 - Segments: %d, CharCount: %d, TextPreview: %.100s...",
+			len(trResp.Texts), trResp.CharCount, tr.FinalText)
+		tr.TranslateCharCount = trResp.CharCount
+		tr.MemoryHits, tr.MemoryMisses = trResp.MemoryHits, trResp.MemoryMisses
+		if len(asrResp.Segments) > 0 {
+			tr.SegmentTexts = trResp.Texts
 		}
-		log.Printf("[Transcript] 翻译成功 - CharCount: %d, TextPreview: %.100s...",
-			trResp.CharCount, trResp.Text)
-		finalText = trResp.Text
-		translateCharCount = trResp.CharCount
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
 	log.Printf("[Transcript] Step 6: 保存转录结果")
+	var persisted transcriptPersist
+	if err := r.Step(ctx, model.PipelineStagePersist, map[string]any{"video_db_id": info.VideoDbId, "language": targetLang}, &persisted, func() error {
+		id, err := l.persist(ctx, info.VideoDbId, targetLang, source, &asrResp, &tr)
+		if err != nil {
+			return err
+		}
+		persisted.TranscriptId = l.keepForkedEdits(ctx, identity, info.VideoDbId, targetLang, id, &asrResp, &tr)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var bill transcriptBill
+	if err := r.Step(ctx, model.PipelineStageBill, nil, &bill, func() error {
+		bill = l.bill(ctx, identity, source, asrResp.CharCount, tr.TranslateCharCount, tr.FinalText)
+		_ = metrics.AddTranslationMemory(ctx, identity, tr.MemoryHits, tr.MemoryMisses)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Transcript] 转录处理完成 - TranscriptId: %d, ASR字符数: %d, 翻译字符数: %d",
+		persisted.TranscriptId, asrResp.CharCount, tr.TranslateCharCount)
+
+	r.done(ctx, persisted.TranscriptId)
+	return l.Get(ctx, persisted.TranscriptId)
+}
+
+// persist 按 (video_id, language, glossary_version) 新增或更新共享转录并保存分句，返回转录ID；
+// 使用术语表翻译的结果单独保存，不写入共享缓存；用户的编辑副本不在这里更新
+func (l *TranscriptLogic) persist(ctx context.Context, videoDbId int64, targetLang, source string, asrResp *asr.ASRResp, tr *transcriptTranslation) (int64, error) {
 	var transcript model.YoutubeTranscript
-	if err := db.WithContext(ctx).Where("video_id = ? AND language = ?", video.Id, targetLang).First(&transcript).Error(); err != nil {
-		log.Printf("[Transcript] 创建新转录记录 - VideoId: %d, Language: %s", video.Id, targetLang)
+	if err := db.WithContext(ctx).Where("video_id = ? AND language = ? AND glossary_version = ? AND owner_identity = ''", videoDbId, targetLang, tr.GlossaryVersion).First(&transcript).Error(); err != nil {
+		log.Printf("[Transcript] 创建新转录记录 - VideoId: %d, Language: %s, GlossaryVersion: %s", videoDbId, targetLang, tr.GlossaryVersion)
 		transcript = model.YoutubeTranscript{
-			VideoId:            video.Id,
+			VideoId:            videoDbId,
 			Language:           targetLang,
+			GlossaryVersion:    tr.GlossaryVersion,
+			DetectedLang:       tr.DetectedLang,
+			TextSource:         source,
 			OriginalText:       asrResp.Text,
-			TranslatedText:     finalText,
+			TranslatedText:     tr.FinalText,
 			AsrCharCount:       asrResp.CharCount,
-			TranslateCharCount: translateCharCount,
+			TranslateCharCount: tr.TranslateCharCount,
+		}
+		if err := db.WithContext(ctx).Create(&transcript).Error(); err != nil {
+			return 0, err
 		}
-		_ = db.WithContext(ctx).Create(&transcript)
 	} else {
 		log.Printf("[Transcript] 更新已存在的转录记录 - TranscriptId: %d", transcript.Id)
 		updates := map[string]any{
+			"detected_lang":        tr.DetectedLang,
+			"text_source":          source,
 			"original_text":        asrResp.Text,
-			"translated_text":      finalText,
+			"translated_text":      tr.FinalText,
 			"asr_char_count":       asrResp.CharCount,
-			"translate_char_count": translateCharCount,
+			"translate_char_count": tr.TranslateCharCount,
+		}
+		if err := db.WithContext(ctx).Model(&model.YoutubeTranscript{}).Where("id = ?", transcript.Id).Updates(updates).Error; err != nil {
+			return 0, err
 		}
-		_ = db.WithContext(ctx).Model(&model.YoutubeTranscript{}).Where("id = ?", transcript.Id).Updates(updates)
 	}
 
+	if err := l.saveSegments(ctx, transcript.Id, asrResp.Segments, tr.SegmentTexts); err != nil {
+		log.Printf("[Transcript] 保存分句失败 - TranscriptId: %d, Error: %v", transcript.Id, err)
+		return transcript.Id, nil
+	}
+	transcript.DetectedLang, transcript.Language = tr.DetectedLang, targetLang
+	if err := l.renderSpeakerText(ctx, &transcript); err != nil {
+		log.Printf("[Transcript] 按说话人重写文本失败 - TranscriptId: %d, Error: %v", transcript.Id, err)
+	}
+	return transcript.Id, nil
+}
+
+// keepForkedEdits 用户已有编辑副本时，刷新结果只记为副本的未应用修订（编辑不被覆盖），返回副本ID；否则返回共享转录ID
+func (l *TranscriptLogic) keepForkedEdits(ctx context.Context, identity string, videoDbId int64, targetLang string, sharedId int64, asrResp *asr.ASRResp, tr *transcriptTranslation) int64 {
+	fork, err := model.NewTranscriptModel().FindFork(ctx, identity, videoDbId, targetLang, tr.GlossaryVersion)
+	if err != nil {
+		return sharedId
+	}
+	log.Printf("[Transcript] 用户已有编辑副本，刷新结果仅保存为修订 - TranscriptId: %d, ForkId: %d", sharedId, fork.Id)
+	items := buildSegmentItems(asrResp.Segments, tr.SegmentTexts)
+	if err := l.keepRefreshAsRevision(ctx, fork, asrResp.Text, tr.FinalText, snapshotSegments(items)); err != nil {
+		log.Printf("[Transcript] 保存刷新修订失败 - ForkId: %d, Error: %v", fork.Id, err)
+	}
+	return fork.Id
+}
+
+// bill 记录使用统计并按最终返回给前端的文本字符数扣减套餐余额；原文来自字幕时没有经过 ASR，不计 ASR 用量
+func (l *TranscriptLogic) bill(ctx context.Context, identity, source string, asrCharCount, translateCharCount int, finalText string) transcriptBill {
 	log.Printf("[Transcript] Step 7: 更新使用统计")
-	_ = metrics.AddUsage(ctx, identity, asrResp.CharCount, 0, 1)
+	if source != model.TranscriptSourceASR {
+		asrCharCount = 0
+	}
+	_ = metrics.AddUsage(ctx, identity, asrCharCount, 0, 1)
 	if translateCharCount > 0 {
 		_ = metrics.AddUsage(ctx, identity, 0, translateCharCount, 0)
 	}
 
 	log.Printf("[Transcript] Step 8: 扣减套餐余额")
-	// 按最终返回给前端的中文字符数扣费（finalText）
 	finalCharCount := len([]rune(finalText))
 	if err := l.deductASRBalance(ctx, identity, finalCharCount); err != nil {
 		log.Printf("[Transcript] ASR balance deduction failed: identity=%s, chars=%d, error=%v", identity, finalCharCount, err)
 	} else {
 		log.Printf("[Transcript] ASR balance deducted: identity=%s, chars=%d (final text)", identity, finalCharCount)
 	}
+	return transcriptBill{Chars: finalCharCount, Policy: TranscriptCacheBillingFull}
+}
 
-	log.Printf("[Transcript] 转录处理完成 - TranscriptId: %d, ASR字符数: %d, 翻译字符数: %d",
-		transcript.Id, asrResp.CharCount, translateCharCount)
+// TranscriptStore 转录归属与修订查看所需的读取操作，默认由 model 层实现
+type TranscriptStore interface {
+	Get(ctx context.Context, transcriptId int64) (*model.YoutubeTranscript, error)
+	Owns(ctx context.Context, identity string, transcriptId int64) (bool, error)
+	ForkOf(ctx context.Context, identity string, sourceId int64) (*model.YoutubeTranscript, error)
+	GetRevision(ctx context.Context, transcriptId int64, revNo int) (*model.YoutubeTranscriptRevision, error)
+	ListSegments(ctx context.Context, transcriptId int64) ([]model.YoutubeTranscriptSegment, error)
+}
+
+// transcriptModelStore 由转录、修订与分句模型组合实现 TranscriptStore
+type transcriptModelStore struct {
+	*model.TranscriptModel
+}
 
-	return &transcript, nil
+func (s transcriptModelStore) GetRevision(ctx context.Context, transcriptId int64, revNo int) (*model.YoutubeTranscriptRevision, error) {
+	return model.NewTranscriptRevisionModel().Get(ctx, transcriptId, revNo)
+}
+
+func (s transcriptModelStore) ListSegments(ctx context.Context, transcriptId int64) ([]model.YoutubeTranscriptSegment, error) {
+	return model.NewTranscriptSegmentModel().ListByTranscript(ctx, transcriptId)
+}
+
+var transcriptStore TranscriptStore = transcriptModelStore{model.NewTranscriptModel()}
+
+// SetTranscriptStore 替换转录读取实现（测试中使用内存实现）
+func SetTranscriptStore(s TranscriptStore) {
+	transcriptStore = s
+}
+
+// Get 获取转录记录
+func (l *TranscriptLogic) Get(ctx context.Context, transcriptId int64) (*model.YoutubeTranscript, error) {
+	transcript, err := transcriptStore.Get(ctx, transcriptId)
+	if err != nil {
+		if errcode.IsRecordNotFound(err) {
+			return nil, errcode.ErrTranscriptNotFound
+		}
+		return nil, err
+	}
+	return transcript, nil
+}
+
+// getOwned 获取用户拥有的转录，用于修改类操作；不属于用户的转录按不存在处理
+func (l *TranscriptLogic) getOwned(ctx context.Context, identity string, transcriptId int64) (*model.YoutubeTranscript, error) {
+	owned, err := transcriptStore.Owns(ctx, identity, transcriptId)
+	if err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, errcode.ErrTranscriptNotFound
+	}
+	return l.Get(ctx, transcriptId)
+}
+
+// ownedView 获取用户看到的转录：用户已由该转录复制出编辑副本时返回副本，否则要求转录属于用户
+func (l *TranscriptLogic) ownedView(ctx context.Context, identity string, transcriptId int64) (*model.YoutubeTranscript, error) {
+	fork, err := transcriptStore.ForkOf(ctx, identity, transcriptId)
+	if err == nil {
+		return fork, nil
+	}
+	if !errcode.IsRecordNotFound(err) {
+		return nil, err
+	}
+	return l.getOwned(ctx, identity, transcriptId)
+}
+
+// editableCopy 获取用户可修改的转录：共享转录在首次修改时复制为用户自己的副本，修改不影响其他用户看到的文本
+func (l *TranscriptLogic) editableCopy(ctx context.Context, identity string, transcriptId int64) (*model.YoutubeTranscript, error) {
+	transcript, err := l.ownedView(ctx, identity, transcriptId)
+	if err != nil {
+		return nil, err
+	}
+	if transcript.OwnerIdentity == identity {
+		return transcript, nil
+	}
+	fork, err := model.NewTranscriptModel().Fork(ctx, transcript, identity)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[Transcript] 复制编辑副本 - TranscriptId: %d, ForkId: %d, Identity: %s", transcript.Id, fork.Id, identity)
+	return fork, nil
+}
+
+// Segments 获取转录的带时间戳分句
+func (l *TranscriptLogic) Segments(ctx context.Context, transcriptId int64) (*typing.YtSegmentsReply, error) {
+	transcript, err := l.Get(ctx, transcriptId)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := model.NewTranscriptSegmentModel().ListByTranscript(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	names, err := model.NewTranscriptSpeakerModel().Names(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &typing.YtSegmentsReply{
+		TranscriptId: transcript.Id,
+		Items:        transformer.ConvertSegmentsToItems(segments, names),
+	}, nil
+}
+
+// saveSegments 持久化带时间戳的分句；translations 为按下标对齐的译文，为 nil 时译文即原文
+func (l *TranscriptLogic) saveSegments(ctx context.Context, transcriptId int64, segments []asr.Segment, translations []string) error {
+	items := buildSegmentItems(segments, translations)
+	log.Printf("[Transcript] 保存分句 - TranscriptId: %d, Count: %d", transcriptId, len(items))
+	return model.NewTranscriptSegmentModel().Replace(ctx, transcriptId, items)
+}
+
+func buildSegmentItems(segments []asr.Segment, translations []string) []model.YoutubeTranscriptSegment {
+	items := make([]model.YoutubeTranscriptSegment, 0, len(segments))
+	for i, seg := range segments {
+		item := model.YoutubeTranscriptSegment{
+			SegIndex:     i,
+			StartMs:      seg.StartMs,
+			EndMs:        seg.EndMs,
+			OriginalText: seg.Text,
+			Confidence:   seg.Confidence,
+			Speaker:      seg.Speaker,
+		}
+		words := make([]model.SegmentWord, 0, len(seg.Words))
+		for _, w := range seg.Words {
+			words = append(words, model.SegmentWord{Text: w.Text, StartMs: w.StartMs, EndMs: w.EndMs, Confidence: w.Confidence})
+		}
+		item.SetWords(words)
+		if translations == nil {
+			item.TranslatedText = seg.Text
+		} else if i < len(translations) {
+			item.TranslatedText = translations[i]
+		}
+		items = append(items, item)
+	}
+	return items
 }
 
 func validateAudioUrl(audioUrl string) error {
@@ -0,0 +1,83 @@
+package logic
+
+import (
+	"context"
+
+	"go-gin/model"
+	"go-gin/rest/translate"
+	"go-gin/typing"
+)
+
+// Review 返回置信度低于阈值的词及合并后的片段，便于编辑直接跳转到需要复核的音频位置
+func (l *TranscriptLogic) Review(ctx context.Context, transcriptId int64, threshold int) (*typing.YtReviewReply, error) {
+	transcript, err := l.Get(ctx, transcriptId)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := model.NewTranscriptSegmentModel().ListByTranscript(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	if threshold <= 0 {
+		threshold = transcriptOptions.ReviewThreshold
+	}
+	reply := &typing.YtReviewReply{
+		TranscriptId: transcript.Id,
+		Threshold:    threshold,
+		Words:        []typing.YtReviewWord{},
+		Spans:        []typing.YtReviewSpan{},
+	}
+	for _, seg := range segments {
+		words := seg.WordList()
+		scored := false
+		var span *typing.YtReviewSpan
+		var spanTexts []string
+		flush := func() {
+			if span != nil {
+				span.Text = translate.JoinTexts(spanTexts, transcript.DetectedLang)
+				reply.Spans = append(reply.Spans, *span)
+				span, spanTexts = nil, nil
+			}
+		}
+		for _, w := range words {
+			// 置信度为 0 表示上游未提供，不参与复核
+			if w.Confidence <= 0 {
+				flush()
+				continue
+			}
+			scored = true
+			reply.ScoredWords++
+			if w.Confidence >= threshold {
+				flush()
+				continue
+			}
+			reply.Words = append(reply.Words, typing.YtReviewWord{
+				SegIndex:   seg.SegIndex,
+				Text:       w.Text,
+				StartMs:    w.StartMs,
+				EndMs:      w.EndMs,
+				Confidence: w.Confidence,
+			})
+			if span == nil {
+				span = &typing.YtReviewSpan{SegIndex: seg.SegIndex, StartMs: w.StartMs, MinConfidence: w.Confidence}
+			}
+			span.EndMs = w.EndMs
+			span.MinConfidence = min(span.MinConfidence, w.Confidence)
+			span.Words++
+			spanTexts = append(spanTexts, w.Text)
+		}
+		flush()
+
+		// 没有词级置信度时按分句置信度整句复核
+		if !scored && seg.Confidence > 0 && seg.Confidence < float64(threshold) {
+			reply.Spans = append(reply.Spans, typing.YtReviewSpan{
+				SegIndex:      seg.SegIndex,
+				StartMs:       seg.StartMs,
+				EndMs:         seg.EndMs,
+				Text:          seg.OriginalText,
+				MinConfidence: int(seg.Confidence),
+			})
+		}
+	}
+	return reply, nil
+}
@@ -0,0 +1,414 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-gin/const/errcode"
+	"go-gin/internal/component/db"
+	"go-gin/model"
+	"go-gin/typing"
+	"go-gin/util/textdiff"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// snapshotSegments 当前分句的快照
+func snapshotSegments(segments []model.YoutubeTranscriptSegment) []model.RevisionSegment {
+	items := make([]model.RevisionSegment, 0, len(segments))
+	for _, seg := range segments {
+		items = append(items, model.RevisionSegment{
+			Index:          seg.SegIndex,
+			StartMs:        seg.StartMs,
+			EndMs:          seg.EndMs,
+			Speaker:        seg.Speaker,
+			OriginalText:   seg.OriginalText,
+			TranslatedText: seg.TranslatedText,
+		})
+	}
+	return items
+}
+
+// ensureBaseline 首次修改前把自动生成的结果记为修订 1，保证随时可以恢复
+func (l *TranscriptLogic) ensureBaseline(tx *gorm.DB, transcript *model.YoutubeTranscript, segments []model.YoutubeTranscriptSegment) error {
+	revModel := model.NewTranscriptRevisionModel()
+	total, err := revModel.CountTx(tx, transcript.Id)
+	if err != nil || total > 0 {
+		return err
+	}
+	rev := &model.YoutubeTranscriptRevision{
+		TranscriptId:   transcript.Id,
+		Source:         model.RevisionSourceAuto,
+		OriginalText:   transcript.OriginalText,
+		TranslatedText: transcript.TranslatedText,
+		Applied:        true,
+	}
+	rev.SetSegments(snapshotSegments(segments))
+	return revModel.AddTx(tx, rev)
+}
+
+// lockedEdit 在事务中锁定转录行、补齐基线修订后执行 fn；同一转录的修改与修订号分配串行进行，任一步失败整体回滚
+func (l *TranscriptLogic) lockedEdit(ctx context.Context, transcriptId int64, fn func(tx *gorm.DB, transcript *model.YoutubeTranscript, segments []model.YoutubeTranscriptSegment) error) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var transcript model.YoutubeTranscript
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", transcriptId).First(&transcript).Error; err != nil {
+			if errcode.IsRecordNotFound(err) {
+				return errcode.ErrTranscriptNotFound
+			}
+			return err
+		}
+		segments, err := model.NewTranscriptSegmentModel().ListByTranscriptTx(tx, transcript.Id)
+		if err != nil {
+			return err
+		}
+		if err := l.ensureBaseline(tx, &transcript, segments); err != nil {
+			return err
+		}
+		return fn(tx, &transcript, segments)
+	})
+}
+
+// Edit 修改转录文本（按分句，没有分句时整段修改），每次修改保存为一条修订；仅限用户自己的转录，
+// 修改写入用户的编辑副本（首次修改时复制），不影响共享转录，副本不被自动刷新覆盖
+func (l *TranscriptLogic) Edit(ctx context.Context, identity string, req typing.YtTranscriptEditReq) (*typing.YtTranscriptEditReply, error) {
+	owned, err := l.editableCopy(ctx, identity, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	var reply *typing.YtTranscriptEditReply
+	err = l.lockedEdit(ctx, owned.Id, func(tx *gorm.DB, transcript *model.YoutubeTranscript, segments []model.YoutubeTranscriptSegment) error {
+		original, translated := transcript.OriginalText, transcript.TranslatedText
+		if len(req.Segments) > 0 {
+			// 按分句修改后由分句重新生成整段文本；先校验全部序号，再写入
+			byIndex := make(map[int]int, len(segments))
+			for i, seg := range segments {
+				byIndex[seg.SegIndex] = i
+			}
+			for _, edit := range req.Segments {
+				if _, ok := byIndex[edit.Index]; !ok {
+					return errcode.ErrSegmentNotFound
+				}
+			}
+			segModel := model.NewTranscriptSegmentModel()
+			changed := false
+			for _, edit := range req.Segments {
+				i := byIndex[edit.Index]
+				updates := map[string]any{}
+				if edit.OriginalText != nil && *edit.OriginalText != segments[i].OriginalText {
+					segments[i].OriginalText = *edit.OriginalText
+					updates["original_text"] = *edit.OriginalText
+				}
+				if edit.TranslatedText != nil && *edit.TranslatedText != segments[i].TranslatedText {
+					segments[i].TranslatedText = *edit.TranslatedText
+					updates["translated_text"] = *edit.TranslatedText
+				}
+				if len(updates) == 0 {
+					continue
+				}
+				if err := segModel.UpdateByIndexTx(tx, transcript.Id, edit.Index, updates); err != nil {
+					return err
+				}
+				changed = true
+			}
+			if !changed {
+				return errcode.ErrTranscriptEditEmpty
+			}
+			var err error
+			if original, translated, err = l.joinSegmentTexts(ctx, transcript, segments); err != nil {
+				return err
+			}
+		} else {
+			// 有分句时整段修改无法同步到分句（字幕、搜索、摘要与问答都基于分句），只能按分句修改
+			if len(segments) > 0 {
+				return errcode.ErrTranscriptEditWhole
+			}
+			if req.OriginalText != nil {
+				original = *req.OriginalText
+			}
+			if req.TranslatedText != nil {
+				translated = *req.TranslatedText
+			}
+			if original == transcript.OriginalText && translated == transcript.TranslatedText {
+				return errcode.ErrTranscriptEditEmpty
+			}
+		}
+
+		revNo, err := l.applyRevision(tx, identity, transcript, original, translated, segments, model.RevisionSourceUser, strings.TrimSpace(req.Note))
+		if err != nil {
+			return err
+		}
+		reply = &typing.YtTranscriptEditReply{
+			TranscriptId:   transcript.Id,
+			RevNo:          revNo,
+			OriginalText:   original,
+			TranslatedText: translated,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[Transcript] 用户修改转录 - TranscriptId: %d, Identity: %s, RevNo: %d, Segments: %d", reply.TranscriptId, identity, reply.RevNo, len(req.Segments))
+	return reply, nil
+}
+
+// joinSegmentTexts 由分句拼接整段原文与译文，有说话人时按轮次输出
+func (l *TranscriptLogic) joinSegmentTexts(ctx context.Context, transcript *model.YoutubeTranscript, segments []model.YoutubeTranscriptSegment) (string, string, error) {
+	names, err := model.NewTranscriptSpeakerModel().Names(ctx, transcript.Id)
+	if err != nil {
+		return "", "", err
+	}
+	original := renderTurns(segments, names, transcript.DetectedLang, func(seg model.YoutubeTranscriptSegment) string { return seg.OriginalText })
+	translated := renderTurns(segments, names, transcript.Language, func(seg model.YoutubeTranscriptSegment) string { return seg.TranslatedText })
+	return original, translated, nil
+}
+
+// applyRevision 写入转录文本、标记为用户编辑，并记录修订
+func (l *TranscriptLogic) applyRevision(tx *gorm.DB, identity string, transcript *model.YoutubeTranscript, original, translated string, segments []model.YoutubeTranscriptSegment, source, note string) (int, error) {
+	now := time.Now()
+	updates := map[string]any{
+		"original_text":   original,
+		"translated_text": translated,
+		"edited_at":       now,
+		"edited_by":       identity,
+	}
+	if err := tx.Model(&model.YoutubeTranscript{}).Where("id = ?", transcript.Id).Updates(updates).Error; err != nil {
+		return 0, err
+	}
+	rev := &model.YoutubeTranscriptRevision{
+		TranscriptId:   transcript.Id,
+		Source:         source,
+		Author:         identity,
+		Note:           note,
+		OriginalText:   original,
+		TranslatedText: translated,
+		Applied:        true,
+	}
+	rev.SetSegments(snapshotSegments(segments))
+	if err := model.NewTranscriptRevisionModel().AddTx(tx, rev); err != nil {
+		return 0, err
+	}
+	return rev.RevNo, nil
+}
+
+// keepRefreshAsRevision 用户已有编辑副本时，自动刷新的结果只记为副本的未应用修订，供对比与手动恢复
+func (l *TranscriptLogic) keepRefreshAsRevision(ctx context.Context, transcript *model.YoutubeTranscript, original, translated string, segments []model.RevisionSegment) error {
+	return l.lockedEdit(ctx, transcript.Id, func(tx *gorm.DB, _ *model.YoutubeTranscript, _ []model.YoutubeTranscriptSegment) error {
+		rev := &model.YoutubeTranscriptRevision{
+			TranscriptId:   transcript.Id,
+			Source:         model.RevisionSourceAuto,
+			Note:           "自动刷新结果（转录已被编辑，未覆盖）",
+			OriginalText:   original,
+			TranslatedText: translated,
+			Applied:        false,
+		}
+		rev.SetSegments(segments)
+		return model.NewTranscriptRevisionModel().AddTx(tx, rev)
+	})
+}
+
+// revisionAuthorOther 其他用户修订的作者标识，不暴露其身份
+const revisionAuthorOther = "other"
+
+// Revisions 获取转录的修订历史；仅限用户自己的转录（已有编辑副本时为副本的历史）
+func (l *TranscriptLogic) Revisions(ctx context.Context, identity string, transcriptId int64) (*typing.YtRevisionsReply, error) {
+	transcript, err := l.ownedView(ctx, identity, transcriptId)
+	if err != nil {
+		return nil, err
+	}
+	revModel := model.NewTranscriptRevisionModel()
+	items, err := revModel.List(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	current, err := revModel.LatestApplied(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	reply := &typing.YtRevisionsReply{
+		TranscriptId: transcript.Id,
+		Edited:       transcript.Edited(),
+		Items:        make([]typing.YtRevisionItem, 0, len(items)),
+	}
+	for _, v := range items {
+		author := v.Author
+		if author != "" && author != identity {
+			author = revisionAuthorOther
+		}
+		reply.Items = append(reply.Items, typing.YtRevisionItem{
+			RevNo:     v.RevNo,
+			Source:    v.Source,
+			Author:    author,
+			Note:      v.Note,
+			CreatedAt: v.CreatedAt.Format(time.DateTime),
+			Current:   v.RevNo == current,
+		})
+	}
+	return reply, nil
+}
+
+func (l *TranscriptLogic) getRevision(tx *gorm.DB, transcriptId int64, revNo int) (*model.YoutubeTranscriptRevision, error) {
+	rev, err := model.NewTranscriptRevisionModel().GetTx(tx, transcriptId, revNo)
+	if err != nil {
+		if errcode.IsRecordNotFound(err) {
+			return nil, errcode.ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return rev, nil
+}
+
+// viewRevision 事务外读取修订
+func (l *TranscriptLogic) viewRevision(ctx context.Context, transcriptId int64, revNo int) (*model.YoutubeTranscriptRevision, error) {
+	rev, err := transcriptStore.GetRevision(ctx, transcriptId, revNo)
+	if err != nil {
+		if errcode.IsRecordNotFound(err) {
+			return nil, errcode.ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return rev, nil
+}
+
+// DiffRevisions 对比两个修订；to 为 0 时与当前文本对比；仅限用户自己的转录
+func (l *TranscriptLogic) DiffRevisions(ctx context.Context, identity string, transcriptId int64, from, to int) (*typing.YtRevisionDiffReply, error) {
+	// from 与 uri 共用结构体绑定，必填在这里校验
+	if from < 1 {
+		return nil, errcode.ErrRevisionNotFound
+	}
+	transcript, err := l.ownedView(ctx, identity, transcriptId)
+	if err != nil {
+		return nil, err
+	}
+	a, err := l.viewRevision(ctx, transcript.Id, from)
+	if err != nil {
+		return nil, err
+	}
+	var b *model.YoutubeTranscriptRevision
+	if to > 0 {
+		if b, err = l.viewRevision(ctx, transcript.Id, to); err != nil {
+			return nil, err
+		}
+	} else {
+		segments, err := transcriptStore.ListSegments(ctx, transcript.Id)
+		if err != nil {
+			return nil, err
+		}
+		b = &model.YoutubeTranscriptRevision{OriginalText: transcript.OriginalText, TranslatedText: transcript.TranslatedText}
+		b.SetSegments(snapshotSegments(segments))
+	}
+
+	reply := &typing.YtRevisionDiffReply{
+		TranscriptId:   transcript.Id,
+		From:           from,
+		To:             to,
+		OriginalText:   textdiff.Diff(a.OriginalText, b.OriginalText),
+		TranslatedText: textdiff.Diff(a.TranslatedText, b.TranslatedText),
+		Segments:       []typing.YtSegmentDiff{},
+	}
+	before := map[int]model.RevisionSegment{}
+	for _, seg := range a.SegmentList() {
+		before[seg.Index] = seg
+	}
+	seen := map[int]bool{}
+	addSegmentDiff := func(index int, old, cur model.RevisionSegment) {
+		d := typing.YtSegmentDiff{Index: index}
+		if old.OriginalText != cur.OriginalText {
+			d.OriginalText = textdiff.Diff(old.OriginalText, cur.OriginalText)
+		}
+		if old.TranslatedText != cur.TranslatedText {
+			d.TranslatedText = textdiff.Diff(old.TranslatedText, cur.TranslatedText)
+		}
+		if d.OriginalText != nil || d.TranslatedText != nil {
+			reply.Segments = append(reply.Segments, d)
+		}
+	}
+	for _, seg := range b.SegmentList() {
+		seen[seg.Index] = true
+		addSegmentDiff(seg.Index, before[seg.Index], seg)
+	}
+	for _, seg := range a.SegmentList() {
+		if !seen[seg.Index] {
+			addSegmentDiff(seg.Index, seg, model.RevisionSegment{})
+		}
+	}
+	return reply, nil
+}
+
+// RestoreRevision 恢复到指定修订，恢复本身也记为一条新修订；仅限用户自己的转录，写入用户的编辑副本
+func (l *TranscriptLogic) RestoreRevision(ctx context.Context, identity string, transcriptId int64, revNo int) (*typing.YtTranscriptEditReply, error) {
+	owned, err := l.editableCopy(ctx, identity, transcriptId)
+	if err != nil {
+		return nil, err
+	}
+	var rev *model.YoutubeTranscriptRevision
+	var applied int
+	err = l.lockedEdit(ctx, owned.Id, func(tx *gorm.DB, transcript *model.YoutubeTranscript, segments []model.YoutubeTranscriptSegment) error {
+		var err error
+		if rev, err = l.getRevision(tx, transcript.Id, revNo); err != nil {
+			return err
+		}
+		if snapshot := rev.SegmentList(); len(snapshot) > 0 {
+			if segments, err = l.restoreSegments(tx, transcript.Id, segments, snapshot); err != nil {
+				return err
+			}
+		}
+		applied, err = l.applyRevision(tx, identity, transcript, rev.OriginalText, rev.TranslatedText, segments,
+			model.RevisionSourceRestore, fmt.Sprintf("恢复到修订 #%d", rev.RevNo))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[Transcript] 恢复转录修订 - TranscriptId: %d, Identity: %s, From: %d, RevNo: %d", owned.Id, identity, rev.RevNo, applied)
+	return &typing.YtTranscriptEditReply{
+		TranscriptId:   owned.Id,
+		RevNo:          applied,
+		OriginalText:   rev.OriginalText,
+		TranslatedText: rev.TranslatedText,
+	}, nil
+}
+
+// restoreSegments 分句结构一致时只回写文本（保留词级信息），否则按快照整体替换
+func (l *TranscriptLogic) restoreSegments(tx *gorm.DB, transcriptId int64, current []model.YoutubeTranscriptSegment, snapshot []model.RevisionSegment) ([]model.YoutubeTranscriptSegment, error) {
+	segModel := model.NewTranscriptSegmentModel()
+	sameShape := len(current) == len(snapshot)
+	for i := 0; sameShape && i < len(current); i++ {
+		sameShape = current[i].SegIndex == snapshot[i].Index && current[i].StartMs == snapshot[i].StartMs
+	}
+	if sameShape {
+		for i, seg := range snapshot {
+			if current[i].OriginalText == seg.OriginalText && current[i].TranslatedText == seg.TranslatedText {
+				continue
+			}
+			current[i].OriginalText, current[i].TranslatedText = seg.OriginalText, seg.TranslatedText
+			if err := segModel.UpdateByIndexTx(tx, transcriptId, seg.Index, map[string]any{
+				"original_text":   seg.OriginalText,
+				"translated_text": seg.TranslatedText,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		return current, nil
+	}
+
+	items := make([]model.YoutubeTranscriptSegment, 0, len(snapshot))
+	for _, seg := range snapshot {
+		items = append(items, model.YoutubeTranscriptSegment{
+			SegIndex:       seg.Index,
+			StartMs:        seg.StartMs,
+			EndMs:          seg.EndMs,
+			Speaker:        seg.Speaker,
+			OriginalText:   seg.OriginalText,
+			TranslatedText: seg.TranslatedText,
+		})
+	}
+	if err := segModel.ReplaceTx(tx, transcriptId, items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,183 @@
+package logic
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"go-gin/const/errcode"
+	"go-gin/internal/component/db"
+	"go-gin/model"
+	"go-gin/rest/translate"
+	"go-gin/typing"
+)
+
+// 说话人显示名最大长度（字符）
+const speakerNameMaxLen = 64
+
+// hasSpeakers 分句中是否带有说话人标识
+func hasSpeakers(segments []model.YoutubeTranscriptSegment) bool {
+	for _, seg := range segments {
+		if seg.Speaker != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// renderTurns 将相邻同一说话人的分句合并为一轮，按 "Speaker A: …" 逐行输出
+func renderTurns(segments []model.YoutubeTranscriptSegment, names map[string]string, lang string, pick func(model.YoutubeTranscriptSegment) string) string {
+	var lines []string
+	var turn []string
+	speaker := ""
+	flush := func() {
+		if len(turn) == 0 {
+			return
+		}
+		text := translate.JoinTexts(turn, lang)
+		if name := model.SpeakerDisplayName(speaker, names); name != "" {
+			text = name + ": " + text
+		}
+		lines = append(lines, text)
+		turn = nil
+	}
+	for _, seg := range segments {
+		text := strings.TrimSpace(pick(seg))
+		if text == "" {
+			continue
+		}
+		if seg.Speaker != speaker {
+			flush()
+			speaker = seg.Speaker
+		}
+		turn = append(turn, text)
+	}
+	flush()
+	return strings.Join(lines, "\n")
+}
+
+// renderSpeakerText 有说话人信息时，用分句按说话人轮次重写转录原文与译文
+func (l *TranscriptLogic) renderSpeakerText(ctx context.Context, transcript *model.YoutubeTranscript) error {
+	segments, err := model.NewTranscriptSegmentModel().ListByTranscript(ctx, transcript.Id)
+	if err != nil {
+		return err
+	}
+	if !hasSpeakers(segments) {
+		return nil
+	}
+	names, err := model.NewTranscriptSpeakerModel().Names(ctx, transcript.Id)
+	if err != nil {
+		return err
+	}
+	original := renderTurns(segments, names, transcript.DetectedLang, func(seg model.YoutubeTranscriptSegment) string { return seg.OriginalText })
+	translated := renderTurns(segments, names, transcript.Language, func(seg model.YoutubeTranscriptSegment) string { return seg.TranslatedText })
+	log.Printf("[Transcript] 按说话人重写转录文本 - TranscriptId: %d, Speakers: %d", transcript.Id, len(speakerCounts(segments)))
+	return db.WithContext(ctx).Model(&model.YoutubeTranscript{}).Where("id = ?", transcript.Id).
+		Updates(map[string]any{"original_text": original, "translated_text": translated}).Error
+}
+
+// renameInText 将文本中行首的 "旧名称: " 替换为新名称
+func (l *TranscriptLogic) renameInText(ctx context.Context, transcript *model.YoutubeTranscript, oldNames, renamed map[string]string) error {
+	replace := func(text string) string {
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			for speaker, name := range renamed {
+				prefix := model.SpeakerDisplayName(speaker, oldNames) + ": "
+				if strings.HasPrefix(line, prefix) {
+					lines[i] = model.SpeakerDisplayName(speaker, map[string]string{speaker: name}) + ": " + strings.TrimPrefix(line, prefix)
+					break
+				}
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	return db.WithContext(ctx).Model(&model.YoutubeTranscript{}).Where("id = ?", transcript.Id).
+		Updates(map[string]any{"original_text": replace(transcript.OriginalText), "translated_text": replace(transcript.TranslatedText)}).Error
+}
+
+// speakerCounts 按首次出现顺序返回说话人及其分句数
+func speakerCounts(segments []model.YoutubeTranscriptSegment) []typing.YtSpeakerItem {
+	var items []typing.YtSpeakerItem
+	index := map[string]int{}
+	for _, seg := range segments {
+		if seg.Speaker == "" {
+			continue
+		}
+		i, ok := index[seg.Speaker]
+		if !ok {
+			i = len(items)
+			index[seg.Speaker] = i
+			items = append(items, typing.YtSpeakerItem{Speaker: seg.Speaker})
+		}
+		items[i].Segments++
+	}
+	return items
+}
+
+// Speakers 获取转录中的说话人及显示名
+func (l *TranscriptLogic) Speakers(ctx context.Context, transcriptId int64) (*typing.YtSpeakersReply, error) {
+	transcript, err := l.Get(ctx, transcriptId)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := model.NewTranscriptSegmentModel().ListByTranscript(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	names, err := model.NewTranscriptSpeakerModel().Names(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	items := speakerCounts(segments)
+	for i := range items {
+		items[i].Name = model.SpeakerDisplayName(items[i].Speaker, names)
+		items[i].Renamed = names[items[i].Speaker] != ""
+	}
+	return &typing.YtSpeakersReply{TranscriptId: transcript.Id, Items: items}, nil
+}
+
+// RenameSpeakers 修改说话人显示名，并同步重写转录文本；仅限用户自己的转录，写入用户的编辑副本
+func (l *TranscriptLogic) RenameSpeakers(ctx context.Context, identity string, transcriptId int64, names map[string]string) (*typing.YtSpeakersReply, error) {
+	transcript, err := l.editableCopy(ctx, identity, transcriptId)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := model.NewTranscriptSegmentModel().ListByTranscript(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, item := range speakerCounts(segments) {
+		known[item.Speaker] = true
+	}
+	cleaned := make(map[string]string, len(names))
+	for speaker, name := range names {
+		speaker = strings.TrimSpace(speaker)
+		if !known[speaker] {
+			return nil, errcode.ErrSpeakerNotFound
+		}
+		name = strings.TrimSpace(name)
+		if r := []rune(name); len(r) > speakerNameMaxLen {
+			name = string(r[:speakerNameMaxLen])
+		}
+		cleaned[speaker] = name
+	}
+	speakerModel := model.NewTranscriptSpeakerModel()
+	oldNames, err := speakerModel.Names(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := speakerModel.SetNames(ctx, transcript.Id, cleaned); err != nil {
+		return nil, err
+	}
+	if transcript.Edited() {
+		// 用户编辑过的整段文本不能由分句重写，只替换每轮开头的说话人名称
+		if err := l.renameInText(ctx, transcript, oldNames, cleaned); err != nil {
+			return nil, err
+		}
+	} else if err := l.renderSpeakerText(ctx, transcript); err != nil {
+		return nil, err
+	}
+	log.Printf("[Transcript] 修改说话人名称 - TranscriptId: %d, Identity: %s, Names: %v", transcript.Id, identity, cleaned)
+	return l.Speakers(ctx, transcript.Id)
+}
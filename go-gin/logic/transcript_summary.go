@@ -0,0 +1,415 @@
+package logic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-gin/const/errcode"
+	"go-gin/internal/component/db"
+	"go-gin/internal/llm"
+	"go-gin/internal/metrics"
+	"go-gin/model"
+	"go-gin/rest/translate"
+	"go-gin/typing"
+	"go-gin/util/subtitle"
+)
+
+const (
+	// DefaultSummaryTokens 开通套餐时默认分配的摘要 token
+	DefaultSummaryTokens = 200000
+
+	summaryChunkRunes  = 6000 // 单次调用的原文字符上限，超过后先分段摘要再合并
+	summaryConcurrency = 3    // 分段摘要并发数
+)
+
+// SummaryOptions 摘要配置
+type SummaryOptions struct {
+	// Provider 摘要使用的 llm 提供方名称，为空时使用默认提供方
+	Provider string
+}
+
+var summaryOptions SummaryOptions
+
+// SetSummaryOptions 注入摘要配置
+func SetSummaryOptions(opt SummaryOptions) { summaryOptions = opt }
+
+// summaryLine 带时间戳的原文分句
+type summaryLine struct {
+	StartMs    int64
+	EndMs      int64
+	Text       string
+	Translated string // 译文，仅用于问答检索
+}
+
+// summaryResult 模型输出的 JSON 结构，分段与合并阶段共用
+type summaryResult struct {
+	Summary   string   `json:"summary"`
+	KeyPoints []string `json:"key_points"`
+	Chapters  []struct {
+		Start   string `json:"start"`
+		Title   string `json:"title"`
+		Summary string `json:"summary"`
+	} `json:"chapters"`
+}
+
+// Summarize 生成转录的摘要、要点与章节；按 (转录, 语言) 缓存，分句文本变化后重新生成，命中缓存不计费
+func (l *TranscriptLogic) Summarize(ctx context.Context, identity string, req typing.YtSummarizeReq) (*typing.YtSummaryReply, error) {
+	transcript, err := l.Get(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	lang := transcript.Language
+	if req.Language != "" {
+		lang = translate.NormalizeLang(req.Language)
+	}
+	if !translate.IsSupported(lang) {
+		return nil, errcode.ErrLangNotSupported
+	}
+	lines, err := l.summaryLines(ctx, transcript)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errcode.ErrSummaryEmpty
+	}
+
+	hash := summarySourceHash(lines)
+	summaryModel := model.NewTranscriptSummaryModel()
+	if !req.Force {
+		if cached, err := summaryModel.Get(ctx, transcript.Id, lang); err == nil && cached.SourceHash == hash {
+			log.Printf("[Summary] 命中缓存 - TranscriptId: %d, Language: %s", transcript.Id, lang)
+			return convertSummary(cached, true, 0), nil
+		}
+	}
+
+	if ok, _ := hasSummaryBalance(ctx, identity); !ok {
+		return nil, errcode.ErrQuotaNotEnough
+	}
+	client, err := llm.Get(summaryOptions.Provider)
+	if err != nil {
+		log.Printf("[Summary] 摘要提供方未配置 - Provider: %s, Error: %v", summaryOptions.Provider, err)
+		return nil, errcode.ErrLLMUpstream
+	}
+
+	log.Printf("[Summary] 开始生成摘要 - TranscriptId: %d, Language: %s, Lines: %d, Provider: %s", transcript.Id, lang, len(lines), client.Name())
+	result, tokens, err := summarizeLines(ctx, client, lines, lang)
+	if err != nil {
+		log.Printf("[Summary] 生成摘要失败 - TranscriptId: %d, Error: %v", transcript.Id, err)
+		return nil, errcode.ErrLLMUpstream
+	}
+
+	item := &model.YoutubeTranscriptSummary{
+		TranscriptId: transcript.Id,
+		Language:     lang,
+		SourceHash:   hash,
+		Summary:      strings.TrimSpace(result.Summary),
+		Provider:     client.Name(),
+		Model:        client.Model(),
+		Tokens:       tokens,
+	}
+	item.SetKeyPoints(cleanKeyPoints(result.KeyPoints))
+	item.SetChapters(buildChapters(result, lines))
+	if err := summaryModel.Save(ctx, item); err != nil {
+		log.Printf("[Summary] 保存摘要失败 - TranscriptId: %d, Error: %v", transcript.Id, err)
+	}
+
+	_ = metrics.AddSummaryUsage(ctx, identity, tokens)
+	if err := deductSummaryBalance(ctx, identity, tokens); err != nil {
+		log.Printf("[Summary] summary balance deduction failed: identity=%s, tokens=%d, error=%v", identity, tokens, err)
+	}
+	log.Printf("[Summary] 摘要完成 - TranscriptId: %d, Language: %s, Tokens: %d", transcript.Id, lang, tokens)
+	return convertSummary(item, false, tokens), nil
+}
+
+// summaryLines 取分句原文与时间戳；没有分句时按句切分全文，时间戳为 0
+func (l *TranscriptLogic) summaryLines(ctx context.Context, transcript *model.YoutubeTranscript) ([]summaryLine, error) {
+	segments, err := model.NewTranscriptSegmentModel().ListByTranscript(ctx, transcript.Id)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]summaryLine, 0, len(segments))
+	for _, seg := range segments {
+		if text := strings.TrimSpace(seg.OriginalText); text != "" {
+			lines = append(lines, summaryLine{StartMs: seg.StartMs, EndMs: seg.EndMs, Text: text, Translated: strings.TrimSpace(seg.TranslatedText)})
+		}
+	}
+	if len(lines) == 0 {
+		for _, s := range translate.SplitSentences(transcript.OriginalText) {
+			lines = append(lines, summaryLine{Text: s})
+		}
+	}
+	return lines, nil
+}
+
+func summarySourceHash(lines []summaryLine) string {
+	h := sha256.New()
+	for _, line := range lines {
+		fmt.Fprintf(h, "%d|%s\n", line.StartMs, line.Text)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// summarizeLines 短文本直接生成；长文本按字符数分段并发生成分段摘要（map），再合并为全片结果（reduce）
+func summarizeLines(ctx context.Context, client *llm.Client, lines []summaryLine, lang string) (*summaryResult, int, error) {
+	langName := summaryLangName(lang)
+	chunks := splitSummaryChunks(lines, summaryChunkRunes)
+	if len(chunks) == 1 {
+		return callSummary(ctx, client, buildSummaryPrompt(langName), renderSummaryLines(chunks[0]))
+	}
+
+	results := make([]*summaryResult, len(chunks))
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		tokens   int
+		firstErr error
+		sem      = make(chan struct{}, summaryConcurrency)
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk []summaryLine) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			prompt := buildSummaryPartPrompt(langName, i+1, len(chunks), chunk[0].StartMs, chunk[len(chunk)-1].EndMs)
+			res, used, err := callSummary(ctx, client, prompt, renderSummaryLines(chunk))
+			mu.Lock()
+			defer mu.Unlock()
+			tokens += used
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			results[i] = res
+		}(i, chunk)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, tokens, firstErr
+	}
+	log.Printf("[Summary] 分段摘要完成 - Parts: %d, Tokens: %d", len(chunks), tokens)
+
+	var sb strings.Builder
+	for i, res := range results {
+		fmt.Fprintf(&sb, "## 第 %d 部分（%s - %s）\n摘要：%s\n要点：\n", i+1,
+			subtitle.FormatShortClock(chunks[i][0].StartMs), subtitle.FormatShortClock(chunks[i][len(chunks[i])-1].EndMs), res.Summary)
+		for _, p := range res.KeyPoints {
+			fmt.Fprintf(&sb, "- %s\n", p)
+		}
+		sb.WriteString("候选章节：\n")
+		for _, c := range res.Chapters {
+			fmt.Fprintf(&sb, "- [%s] %s：%s\n", c.Start, c.Title, c.Summary)
+		}
+		sb.WriteString("\n")
+	}
+	final, used, err := callSummary(ctx, client, buildSummaryReducePrompt(langName), sb.String())
+	return final, tokens + used, err
+}
+
+// callSummary 调用模型并解析 JSON 输出
+func callSummary(ctx context.Context, client *llm.Client, systemPrompt, content string) (*summaryResult, int, error) {
+	resp, err := client.Chat(ctx, llm.Request{Messages: []llm.Message{llm.System(systemPrompt), llm.User(content)}})
+	if err != nil {
+		return nil, 0, err
+	}
+	var res summaryResult
+	raw := resp.Content
+	if i, j := strings.Index(raw, "{"), strings.LastIndex(raw, "}"); i >= 0 && j > i {
+		raw = raw[i : j+1]
+	}
+	if err := json.Unmarshal([]byte(raw), &res); err != nil {
+		return nil, resp.Usage.TotalTokens, fmt.Errorf("parse summary output failed: %w: %.200s", err, resp.Content)
+	}
+	return &res, resp.Usage.TotalTokens, nil
+}
+
+func summaryLangName(lang string) string {
+	if l, ok := translate.LookupLanguage(lang); ok {
+		return l.ZhName
+	}
+	return lang
+}
+
+const summaryJSONFormat = `{"summary":"摘要","key_points":["要点"],"chapters":[{"start":"MM:SS 或 H:MM:SS","title":"章节标题","summary":"一句话概要"}]}`
+
+func buildSummaryPrompt(langName string) string {
+	return "你是视频内容分析助手。输入是一段视频的转录文本，每行以 [时间戳] 开头。请用" + langName +
+		"输出严格的 JSON，不要输出 JSON 以外的任何内容，格式为：" + summaryJSONFormat +
+		"。summary 为 3~5 句的整体摘要；key_points 为 3~8 条要点；chapters 按时间顺序覆盖全片，3~10 个，start 必须取自输入中出现的时间戳，第一个章节从最早的时间戳开始。"
+}
+
+func buildSummaryPartPrompt(langName string, part, total int, startMs, endMs int64) string {
+	return fmt.Sprintf("你是视频内容分析助手。输入是一段长视频转录的第 %d/%d 部分（%s - %s），每行以 [时间戳] 开头。请用%s输出严格的 JSON，不要输出 JSON 以外的任何内容，格式为：%s。summary 为本部分的摘要；key_points 为本部分的要点；chapters 为本部分的章节划分，start 必须取自输入中出现的时间戳。",
+		part, total, subtitle.FormatShortClock(startMs), subtitle.FormatShortClock(endMs), langName, summaryJSONFormat)
+}
+
+func buildSummaryReducePrompt(langName string) string {
+	return "你是视频内容分析助手。输入是一段长视频按时间顺序分成的各部分摘要、要点与候选章节。请合并为全片结果，用" + langName +
+		"输出严格的 JSON，不要输出 JSON 以外的任何内容，格式为：" + summaryJSONFormat +
+		"。summary 为 3~5 句的全片摘要；key_points 为去重后最重要的 3~8 条要点；chapters 从候选章节中合并相邻的相近主题，保持时间顺序，共 3~12 个，start 必须取自候选章节的时间。"
+}
+
+// splitSummaryChunks 按字符数切分分句，保持顺序
+func splitSummaryChunks(lines []summaryLine, maxRunes int) [][]summaryLine {
+	var chunks [][]summaryLine
+	var cur []summaryLine
+	size := 0
+	for _, line := range lines {
+		n := len([]rune(line.Text)) + 10
+		if len(cur) > 0 && size+n > maxRunes {
+			chunks = append(chunks, cur)
+			cur, size = nil, 0
+		}
+		cur = append(cur, line)
+		size += n
+	}
+	if len(cur) > 0 {
+		chunks = append(chunks, cur)
+	}
+	return chunks
+}
+
+func renderSummaryLines(lines []summaryLine) string {
+	var sb strings.Builder
+	for _, line := range lines {
+		fmt.Fprintf(&sb, "[%s] %s\n", subtitle.FormatShortClock(line.StartMs), line.Text)
+	}
+	return sb.String()
+}
+
+func cleanKeyPoints(points []string) []string {
+	out := make([]string, 0, len(points))
+	for _, p := range points {
+		if p = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(p), "-•*")); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// buildChapters 将模型给出的章节时间对齐到最近的分句起点，按时间排序去重，结束时间取下一章节起点
+func buildChapters(res *summaryResult, lines []summaryLine) []model.SummaryChapter {
+	var chapters []model.SummaryChapter
+	for _, c := range res.Chapters {
+		title := strings.TrimSpace(c.Title)
+		ms, ok := subtitle.ParseClock(c.Start)
+		if title == "" || !ok {
+			continue
+		}
+		chapters = append(chapters, model.SummaryChapter{StartMs: snapToLine(ms, lines), Title: title, Summary: strings.TrimSpace(c.Summary)})
+	}
+	sort.SliceStable(chapters, func(i, j int) bool { return chapters[i].StartMs < chapters[j].StartMs })
+	out := make([]model.SummaryChapter, 0, len(chapters))
+	for _, c := range chapters {
+		if len(out) > 0 && out[len(out)-1].StartMs == c.StartMs {
+			continue
+		}
+		out = append(out, c)
+	}
+	end := lines[len(lines)-1].EndMs
+	for i := range out {
+		out[i].EndMs = end
+		if i+1 < len(out) {
+			out[i].EndMs = out[i+1].StartMs
+		}
+	}
+	return out
+}
+
+// snapToLine 返回距离 ms 最近的分句起点
+func snapToLine(ms int64, lines []summaryLine) int64 {
+	best := lines[0].StartMs
+	for _, line := range lines {
+		if abs64(line.StartMs-ms) < abs64(best-ms) {
+			best = line.StartMs
+		}
+	}
+	return best
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func convertSummary(item *model.YoutubeTranscriptSummary, cached bool, tokens int) *typing.YtSummaryReply {
+	reply := &typing.YtSummaryReply{
+		TranscriptId: item.TranscriptId,
+		Language:     item.Language,
+		Summary:      item.Summary,
+		KeyPoints:    item.KeyPointList(),
+		Chapters:     []typing.YtChapterItem{},
+		Cached:       cached,
+		Tokens:       tokens,
+		UpdatedAt:    item.UpdatedAt.Format(time.DateTime),
+	}
+	if reply.KeyPoints == nil {
+		reply.KeyPoints = []string{}
+	}
+	for _, c := range item.ChapterList() {
+		reply.Chapters = append(reply.Chapters, typing.YtChapterItem{
+			StartMs: c.StartMs,
+			EndMs:   c.EndMs,
+			Start:   subtitle.FormatShortClock(c.StartMs),
+			Title:   c.Title,
+			Summary: c.Summary,
+		})
+	}
+	return reply
+}
+
+// deductSummaryBalance 扣减用户摘要 token 余额（先到期的先扣，允许透支）
+func deductSummaryBalance(ctx context.Context, identity string, tokens int) error {
+	if identity == "" || tokens <= 0 {
+		return nil
+	}
+	sql := `UPDATE user_package
+			SET remain_summary_tokens = GREATEST(0, remain_summary_tokens - ?),
+				updated_at = NOW()
+			WHERE user_identity = ?
+			AND remain_summary_tokens > 0
+			AND (expire_at IS NULL OR expire_at > NOW())
+			ORDER BY expire_at ASC
+			LIMIT 1`
+	result := db.WithContext(ctx).Exec(sql, tokens, identity)
+	if result.Error() != nil {
+		return result.Error()
+	}
+	if result.RowsAffected == 0 {
+		log.Printf("[Summary] summary balance deduction: no available balance for identity=%s, tokens=%d", identity, tokens)
+	}
+	return nil
+}
+
+// hasSummaryBalance 检查用户是否还有可用的摘要 token（>0）
+func hasSummaryBalance(ctx context.Context, identity string) (bool, error) {
+	if identity == "" {
+		return false, nil
+	}
+	var remain int
+	row := db.WithContext(ctx).Raw("SELECT COALESCE(SUM(remain_summary_tokens),0) FROM user_package WHERE user_identity = ? AND (expire_at IS NULL OR expire_at > NOW())", identity).Row()
+	if err := row.Err(); err != nil {
+		return true, err
+	}
+	_ = row.Scan(&remain)
+	return remain > 0, nil
+}
@@ -0,0 +1,150 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"go-gin/const/errcode"
+	"go-gin/internal/errorx"
+	"go-gin/model"
+	"go-gin/rest/tts"
+	"go-gin/typing"
+
+	"github.com/google/uuid"
+)
+
+type TTSJobLogic struct {
+	model *model.TTSJobModel
+}
+
+func NewTTSJobLogic() *TTSJobLogic {
+	return &TTSJobLogic{
+		model: model.NewTTSJobModel(),
+	}
+}
+
+// Submit 创建长文本合成任务（投递到队列由调用方负责）
+func (l *TTSJobLogic) Submit(ctx context.Context, identity string, req typing.TTSSynthesizeReq) (*model.TTSJob, error) {
+	chars := len([]rune(req.Text))
+	// 余额耗尽的请求不进入队列；命中分句缓存的部分不计费，按字数的精确预检在任务执行时进行
+	if ok, err := NewTTSLogic().hasEnoughTTSBalance(ctx, identity, 1); err == nil && !ok {
+		return nil, errcode.ErrQuotaNotEnough
+	}
+	prosody := ""
+	if p := req.Prosody(); p != (tts.Prosody{}) {
+		b, _ := json.Marshal(p)
+		prosody = string(b)
+	}
+	job := &model.TTSJob{
+		JobId:        uuid.New().String(),
+		UserIdentity: identity,
+		Text:         req.Text,
+		Speaker:      req.Speaker,
+		UseMyVoice:   req.UseMyVoice,
+		Prosody:      prosody,
+		CharCount:    chars,
+		Status:       model.TTSJobStatusQueued,
+	}
+	if err := l.model.Add(ctx, job); err != nil {
+		return nil, err
+	}
+	log.Printf("[TTSJob] 任务已创建 - JobId: %s, Identity: %s, Chars: %d", job.JobId, identity, chars)
+	return job, nil
+}
+
+// Get 查询任务进度，仅提交人可见
+func (l *TTSJobLogic) Get(ctx context.Context, identity string, jobId string) (*typing.TTSJobReply, error) {
+	job, err := l.model.GetByJobId(ctx, jobId)
+	if err != nil {
+		if errcode.IsRecordNotFound(err) {
+			return nil, errcode.ErrTTSJobNotFound
+		}
+		return nil, err
+	}
+	if identity == "" || job.UserIdentity != identity {
+		return nil, errcode.ErrTTSJobNotFound
+	}
+
+	reply := &typing.TTSJobReply{
+		JobId:       job.JobId,
+		Status:      job.Status,
+		CharCount:   job.CharCount,
+		TotalChunks: job.TotalChunks,
+		DoneChunks:  job.DoneChunks,
+		ErrorCode:   job.ErrorCode,
+		ErrorMsg:    job.ErrorMsg,
+	}
+	if job.TotalChunks > 0 {
+		reply.Progress = job.DoneChunks * 100 / job.TotalChunks
+	}
+	if job.Status == model.TTSJobStatusDone {
+		reply.Progress = 100
+		reply.HistoryId = job.HistoryId
+		var item model.TTSHistory
+		if err := NewHistoryLogic().getTTS(ctx, identity, job.HistoryId, &item); err == nil {
+			reply.AudioUrl = item.AudioUrl
+		}
+	}
+	return reply, nil
+}
+
+// Run 执行合成任务，由队列 worker 调用
+func (l *TTSJobLogic) Run(ctx context.Context, jobId string) error {
+	job, err := l.model.GetByJobId(ctx, jobId)
+	if err != nil {
+		return err
+	}
+	// 已结束的任务不重复执行
+	if job.Status == model.TTSJobStatusDone || job.Status == model.TTSJobStatusFailed {
+		log.Printf("[TTSJob] 任务已结束，跳过 - JobId: %s, Status: %s", jobId, job.Status)
+		return nil
+	}
+
+	var prosody tts.Prosody
+	if job.Prosody != "" {
+		if err := json.Unmarshal([]byte(job.Prosody), &prosody); err != nil {
+			_ = l.Fail(ctx, jobId, err)
+			return err
+		}
+	}
+	progress := &ttsJobProgress{ctx: ctx, model: l.model, jobId: jobId}
+	item, err := NewTTSLogic().SynthesizeWithProgress(ctx, job.UserIdentity, job.Text, job.Speaker, job.UseMyVoice, prosody, progress)
+	if err != nil {
+		_ = l.Fail(ctx, jobId, err)
+		return err
+	}
+	log.Printf("[TTSJob] 任务完成 - JobId: %s, HistoryId: %d", jobId, item.Id)
+	return l.model.MarkDone(ctx, jobId, item.Id)
+}
+
+// ttsJobProgress 把合成进度写入任务：开始时标记运行中并记录片段总数与命中缓存数，之后更新已完成数
+type ttsJobProgress struct {
+	ctx   context.Context
+	model *model.TTSJobModel
+	jobId string
+}
+
+func (p *ttsJobProgress) Start(total, done int) {
+	if err := p.model.MarkRunning(p.ctx, p.jobId, total, done); err != nil {
+		log.Printf("[TTSJob] 标记任务开始失败 - JobId: %s, Done: %d/%d, Error: %v", p.jobId, done, total, err)
+	}
+}
+
+func (p *ttsJobProgress) Update(done int) {
+	if err := p.model.UpdateProgress(p.ctx, p.jobId, done); err != nil {
+		log.Printf("[TTSJob] 更新进度失败 - JobId: %s, Done: %d, Error: %v", p.jobId, done, err)
+	}
+}
+
+// Fail 将任务标记为失败，业务错误保留其错误码
+func (l *TTSJobLogic) Fail(ctx context.Context, jobId string, cause error) error {
+	code := errorx.ErrCodeDefault
+	var bizErr errorx.BizError
+	if errors.As(cause, &bizErr) {
+		code = bizErr.Code
+	}
+	log.Printf("[TTSJob] 任务失败 - JobId: %s, Code: %d, Error: %v", jobId, code, cause)
+	return l.model.MarkFailed(ctx, jobId, code, cause.Error())
+}
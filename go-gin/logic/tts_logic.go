@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"go-gin/const/errcode"
 	"go-gin/internal/component/db"
@@ -21,16 +22,31 @@ type TTSLogic struct{}
 
 func NewTTSLogic() *TTSLogic { return &TTSLogic{} }
 
-func (l *TTSLogic) Synthesize(ctx context.Context, identity, text, speaker string, useMyVoice bool) (*model.TTSHistory, error) {
+func (l *TTSLogic) Synthesize(ctx context.Context, identity, text, speaker string, useMyVoice bool, prosody tts.Prosody) (*model.TTSHistory, error) {
+	return l.SynthesizeWithProgress(ctx, identity, text, speaker, useMyVoice, prosody, nil)
+}
+
+// SynthesizeWithProgress 同 Synthesize；长文本分段合成时回调 progress（命中历史时不回调）
+func (l *TTSLogic) SynthesizeWithProgress(ctx context.Context, identity, text, speaker string, useMyVoice bool, prosody tts.Prosody, progress TTSProgress) (*model.TTSHistory, error) {
+	item, _, err := l.synthesize(ctx, identity, text, speaker, useMyVoice, prosody, func(plan *TTSPlan) (*tts.TTSResp, error) {
+		return l.SynthesizePlan(ctx, plan, progress)
+	})
+	return item, err
+}
+
+// SynthesizeStream 流式合成：按原文顺序逐段合成，音频到达即回调 onAudio；全部完成后与同步合成一样上传、入库并计费。
+// 命中历史时不回调，返回 hit=true，由调用方直接返回历史音频
+func (l *TTSLogic) SynthesizeStream(ctx context.Context, identity, text, speaker string, useMyVoice bool, prosody tts.Prosody, onAudio TTSStreamFunc) (*model.TTSHistory, bool, error) {
+	return l.synthesize(ctx, identity, text, speaker, useMyVoice, prosody, func(plan *TTSPlan) (*tts.TTSResp, error) {
+		return l.streamPlan(ctx, plan, onAudio)
+	})
+}
+
+// synthesize 合成主流程：确定音色与资源 → 命中历史直接返回 → 查分句缓存并预检余额 → run 合成 → 上传、入库与计费
+func (l *TTSLogic) synthesize(ctx context.Context, identity, text, speaker string, useMyVoice bool, prosody tts.Prosody, run func(plan *TTSPlan) (*tts.TTSResp, error)) (*model.TTSHistory, bool, error) {
 	// 简洁日志记录
 	fmt.Printf("TTS START: identity=%s useMyVoice=%v textLen=%d\n", identity, useMyVoice, len(text))
 
-	// 预检余额（严格：不足直接拒绝）
-	need := len([]rune(text))
-	if ok, err := l.hasEnoughTTSBalance(ctx, identity, need); err == nil && !ok {
-		return nil, errcode.ErrQuotaNotEnough
-	}
-
 	// 决定有效 speaker 与资源（不回退）
 	effectiveSpeaker := speaker
 	resourceId := "volc.service_type.10029" // 大模型语音合成（字符版）
@@ -38,15 +54,20 @@ func (l *TTSLogic) Synthesize(ctx context.Context, identity, text, speaker strin
 		uv, err := model.NewUserVoiceModel().GetByMobile(ctx, identity)
 		if err != nil || uv == nil || uv.VoiceId == "" {
 			logx.WithContext(ctx).Warn("user_voice_not_configured", map[string]any{"identity": identity})
-			return nil, errcode.ErrUserVoiceNotConfigured
+			return nil, false, errcode.ErrUserVoiceNotConfigured
 		}
 		effectiveSpeaker = uv.VoiceId
 		resourceId = "volc.megatts.default" // 声音复刻2.0（字符版）
 		fmt.Printf("TTS using my voice: identity=%s, voice_id=%s\n", identity, uv.VoiceId)
 	}
 
-	// 幂等：sha256(identity|text|effectiveSpeaker)
-	h := sha256.Sum256([]byte(identity + "|" + text + "|" + effectiveSpeaker))
+	// 幂等：sha256(identity|text|effectiveSpeaker)，指定了韵律时追加 |韵律摘要（默认韵律与旧记录的键一致）
+	prosody = prosody.Normalize()
+	hashInput := identity + "|" + text + "|" + effectiveSpeaker
+	if key := prosody.Key(); key != "" {
+		hashInput += "|" + key
+	}
+	h := sha256.Sum256([]byte(hashInput))
 	textHash := hex.EncodeToString(h[:])
 
 	var item model.TTSHistory
@@ -56,36 +77,31 @@ func (l *TTSLogic) Synthesize(ctx context.Context, identity, text, speaker strin
 		fmt.Printf("TTS cache hit: id=%d\n", item.Id)
 		logx.WithContext(ctx).Info("tts_history_hit", map[string]any{"id": item.Id, "identity": identity, "speaker": effectiveSpeaker})
 
-		// 缓存命中时也要进行余额预检（按历史记录的字符数）
-		if ok, err := l.hasEnoughTTSBalance(ctx, identity, item.CharCount); err == nil && !ok {
-			return nil, errcode.ErrQuotaNotEnough
+		// 命中历史：同一文本的音频已计过费，本次不再预检与扣减余额，只记录请求次数；
+		// 返回的 billed_chars 为本次计费字数（0），与分句缓存只按新合成字数计费一致
+		if err := metrics.AddUsage(ctx, identity, 0, 0, 1); err != nil {
+			logx.WithContext(ctx).Error("tts_cache_usage_record_failed", map[string]any{"identity": identity, "error": err.Error()})
 		}
+		item.BilledChars = 0
 
-		// 缓存命中时也需要记录使用统计
-		if err := metrics.AddUsage(ctx, identity, 0, item.CharCount, 1); err != nil {
-			fmt.Printf("TTS cache hit AddUsage failed: identity=%s, chars=%d, error=%v\n", identity, item.CharCount, err)
-			logx.WithContext(ctx).Error("tts_cache_usage_record_failed", map[string]any{"identity": identity, "chars": item.CharCount, "error": err.Error()})
-		} else {
-			fmt.Printf("TTS cache hit AddUsage success: identity=%s, chars=%d\n", identity, item.CharCount)
-		}
+		return &item, true, nil
+	}
 
-		// 缓存命中时也需要扣减套餐余额
-		if err := l.deductTTSBalance(ctx, identity, item.CharCount); err != nil {
-			fmt.Printf("TTS cache hit balance deduction failed: identity=%s, chars=%d, error=%v\n", identity, item.CharCount, err)
-			logx.WithContext(ctx).Error("tts_cache_balance_deduction_failed", map[string]any{"identity": identity, "chars": item.CharCount, "error": err.Error()})
-		} else {
-			fmt.Printf("TTS cache hit balance deducted: identity=%s, chars=%d\n", identity, item.CharCount)
-		}
+	// 命中分句缓存的部分不再调用上游，也不计费
+	plan := l.PlanTTS(ctx, text, effectiveSpeaker, resourceId, prosody)
+	billed := plan.NewChars()
 
-		return &item, nil
+	// 预检余额（严格：不足直接拒绝，按需要新合成的字数）
+	if ok, err := l.hasEnoughTTSBalance(ctx, identity, billed); err == nil && !ok {
+		return nil, false, errcode.ErrQuotaNotEnough
 	}
 
 	// 外部 TTS（按指定资源调用）
-	fmt.Printf("TTS calling external service: resource=%s speaker=%s\n", resourceId, effectiveSpeaker)
-	resp, err := tts.Svc.SynthesizeWithResource(ctx, text, effectiveSpeaker, resourceId)
+	fmt.Printf("TTS calling external service: resource=%s speaker=%s billed=%d\n", resourceId, effectiveSpeaker, billed)
+	resp, err := run(plan)
 	if err != nil {
 		fmt.Printf("TTS failed: %v\n", err)
-		return nil, err
+		return nil, false, err
 	}
 
 	// 将音频保存到七牛云，数据库仅存公网链接
@@ -123,6 +139,14 @@ func (l *TTSLogic) Synthesize(ctx context.Context, identity, text, speaker strin
 		}
 	}
 
+	// 时间戳随记录保存，供逐词高亮与字幕导出
+	timings := ""
+	if len(resp.Sentences) > 0 {
+		if b, err := json.Marshal(resp.Sentences); err == nil {
+			timings = string(b)
+		}
+	}
+
 	// 入库
 	preview := text
 	item = model.TTSHistory{
@@ -130,38 +154,56 @@ func (l *TTSLogic) Synthesize(ctx context.Context, identity, text, speaker strin
 		TextHash:     textHash,
 		TextPreview:  preview,
 		CharCount:    len([]rune(text)),
+		BilledChars:  billed,
 		Speaker:      effectiveSpeaker,
 		AudioUrl:     audioURL,
+		DurationMs:   resp.DurationMs,
+		Timings:      timings,
 		RequestId:    resp.RequestId,
 		Status:       0,
+		// 记录实际使用的韵律，未指定的倍率按 1 保存
+		SpeedRatio:    ttsRatio(prosody.SpeedRatio),
+		LoudnessRatio: ttsRatio(prosody.LoudnessRatio),
+		Pitch:         prosody.Pitch,
+		Emotion:       prosody.Emotion,
+		EmotionScale:  prosody.EmotionScale,
 	}
 
 	if err := db.WithContext(ctx).Create(&item).Error(); err != nil {
 		logx.WithContext(ctx).Error("tts_history_create_failed", map[string]any{"identity": identity, "speaker": effectiveSpeaker, "err": err.Error()})
 		// 不中断主流程，仍返回音频结果，但提示日志排查 DB/Migration
-		return &item, nil
+		return &item, false, nil
 	}
 
 	fmt.Printf("TTS success: saved id=%d\n", item.Id)
 	logx.WithContext(ctx).Info("tts_history_created", map[string]any{"id": item.Id, "identity": identity, "speaker": effectiveSpeaker})
 
 	// 记录使用统计 - 确保即使统计失败也不影响主流程
-	if err := metrics.AddUsage(ctx, identity, 0, item.CharCount, 1); err != nil {
-		fmt.Printf("TTS AddUsage failed: identity=%s, chars=%d, error=%v\n", identity, item.CharCount, err)
-		logx.WithContext(ctx).Error("tts_usage_record_failed", map[string]any{"identity": identity, "chars": item.CharCount, "error": err.Error()})
+	if err := metrics.AddUsage(ctx, identity, 0, item.BilledChars, 1); err != nil {
+		fmt.Printf("TTS AddUsage failed: identity=%s, chars=%d, error=%v\n", identity, item.BilledChars, err)
+		logx.WithContext(ctx).Error("tts_usage_record_failed", map[string]any{"identity": identity, "chars": item.BilledChars, "error": err.Error()})
 	} else {
-		fmt.Printf("TTS AddUsage success: identity=%s, chars=%d\n", identity, item.CharCount)
+		fmt.Printf("TTS AddUsage success: identity=%s, chars=%d\n", identity, item.BilledChars)
 	}
 
-	// 扣减套餐余额 - 即使扣减失败也不影响主流程
-	if err := l.deductTTSBalance(ctx, identity, item.CharCount); err != nil {
-		fmt.Printf("TTS balance deduction failed: identity=%s, chars=%d, error=%v\n", identity, item.CharCount, err)
-		logx.WithContext(ctx).Error("tts_balance_deduction_failed", map[string]any{"identity": identity, "chars": item.CharCount, "error": err.Error()})
+	// 扣减套餐余额 - 即使扣减失败也不影响主流程；扣减成功后才把新合成的分句写回缓存
+	if err := l.deductTTSBalance(ctx, identity, item.BilledChars); err != nil {
+		fmt.Printf("TTS balance deduction failed: identity=%s, chars=%d, error=%v\n", identity, item.BilledChars, err)
+		logx.WithContext(ctx).Error("tts_balance_deduction_failed", map[string]any{"identity": identity, "chars": item.BilledChars, "error": err.Error()})
 	} else {
-		fmt.Printf("TTS balance deducted: identity=%s, chars=%d\n", identity, item.CharCount)
+		fmt.Printf("TTS balance deducted: identity=%s, chars=%d\n", identity, item.BilledChars)
+		l.saveFresh(ctx, plan)
 	}
 
-	return &item, nil
+	return &item, false, nil
+}
+
+// ttsRatio 未指定的倍率按 1 返回
+func ttsRatio(r float64) float64 {
+	if r == 0 {
+		return 1
+	}
+	return r
 }
 
 // deductTTSBalance 扣减用户TTS套餐余额
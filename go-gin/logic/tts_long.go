@@ -0,0 +1,416 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"go-gin/internal/audiochunk"
+	"go-gin/model"
+	"go-gin/rest/tts"
+)
+
+const (
+	// TTSMaxRunes 单次合成的文本上限（tts_history.text_preview 为 TEXT，按 3 字节/字计不超过 64KB）
+	TTSMaxRunes = 20000
+	// ttsSingleMaxRunes 上游单次请求的文本上限，不超过时直接合成
+	ttsSingleMaxRunes = 1000
+	// ttsChunkAttempts 每个片段的最多尝试次数
+	ttsChunkAttempts = 2
+	// ttsCacheBatch 单次查询/清理分句缓存的最大条数（音频为 BLOB，批次不宜过大）
+	ttsCacheBatch = 100
+)
+
+// TTSOptions 长文本合成配置
+type TTSOptions struct {
+	// AsyncThreshold 超过该字数的文本走异步任务（默认 3000）
+	AsyncThreshold int
+	// ChunkRunes 长文本切分的片段字数上限（默认 500，不超过 1000）
+	ChunkRunes int
+	// Concurrency 片段合成并发数（默认 3）
+	Concurrency int
+	// DisableSentenceCache 关闭分句音频缓存（默认开启）
+	DisableSentenceCache bool
+	// SentenceCacheTTL 分句音频缓存未被使用超过该时长后清理（默认 720h）
+	SentenceCacheTTL time.Duration
+}
+
+var ttsOptions = TTSOptions{AsyncThreshold: 3000, ChunkRunes: 500, Concurrency: 3, SentenceCacheTTL: 30 * 24 * time.Hour}
+
+// SetTTSOptions 注入长文本合成配置，未设置的项使用默认值
+func SetTTSOptions(opt TTSOptions) {
+	if opt.AsyncThreshold > 0 {
+		ttsOptions.AsyncThreshold = opt.AsyncThreshold
+	}
+	if opt.ChunkRunes > 0 {
+		ttsOptions.ChunkRunes = min(opt.ChunkRunes, ttsSingleMaxRunes)
+	}
+	if opt.Concurrency > 0 {
+		ttsOptions.Concurrency = opt.Concurrency
+	}
+	ttsOptions.DisableSentenceCache = opt.DisableSentenceCache
+	if opt.SentenceCacheTTL > 0 {
+		ttsOptions.SentenceCacheTTL = opt.SentenceCacheTTL
+	}
+}
+
+// TTSSentenceStore 分句音频缓存的持久化，默认由 model.TTSSentenceCacheModel 实现
+type TTSSentenceStore interface {
+	FindByHashes(ctx context.Context, hashes []string) ([]model.TTSSentenceCache, error)
+	Save(ctx context.Context, item *model.TTSSentenceCache) error
+	Touch(ctx context.Context, hashes []string) error
+	PurgeUnused(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+var ttsSentenceStore TTSSentenceStore = model.NewTTSSentenceCacheModel()
+
+// SetTTSSentenceStore 替换分句缓存实现（测试中使用内存实现）
+func SetTTSSentenceStore(s TTSSentenceStore) {
+	ttsSentenceStore = s
+}
+
+// TTSAsync 该字数的文本是否走异步任务
+func TTSAsync(chars int) bool { return chars > ttsOptions.AsyncThreshold }
+
+// TTSProgress 长文本合成进度回调
+type TTSProgress interface {
+	// Start 开始合成前调用一次：total 为片段总数，done 为命中分句缓存、无需合成的片段数
+	Start(total, done int)
+	// Update 每合成完一个片段调用一次，done 为累计完成的片段数
+	Update(done int)
+}
+
+// TTSStreamFunc 流式合成的音频回调，按原文顺序收到 mp3 数据；返回错误时中止合成
+type TTSStreamFunc func(chunk []byte) error
+
+// ttsPiece 一个合成片段；开启分句缓存时每句一个片段，Hash 为分句缓存键
+type ttsPiece struct {
+	Text      string
+	Hash      string
+	Audio     []byte
+	Sentences []tts.TTSSentence // 相对片段音频起点的时间戳
+	Fresh     bool              // 本次新合成，计费后才写回分句缓存
+}
+
+// TTSPlan 一次合成的片段计划：命中分句缓存的片段已带有音频，其余片段需要调用上游
+type TTSPlan struct {
+	speaker    string
+	resourceId string
+	prosody    tts.Prosody
+	cached     bool // 是否使用分句缓存（新合成的片段写回缓存）
+	pieces     []ttsPiece
+	hits       int
+}
+
+// pending 需要调用上游的片段下标，同一分句只合成一次
+func (p *TTSPlan) pending() []int {
+	var out []int
+	seen := map[string]bool{}
+	for i, piece := range p.pieces {
+		if piece.Audio != nil || (piece.Hash != "" && seen[piece.Hash]) {
+			continue
+		}
+		seen[piece.Hash] = true
+		out = append(out, i)
+	}
+	return out
+}
+
+// NewChars 需要新合成（计费）的字数
+func (p *TTSPlan) NewChars() int {
+	n := 0
+	for _, i := range p.pending() {
+		n += len([]rune(p.pieces[i].Text))
+	}
+	return n
+}
+
+// Hits 命中分句缓存的片段数
+func (p *TTSPlan) Hits() int { return p.hits }
+
+// PlanTTS 切分文本并查找分句缓存；关闭缓存时短文本整段合成，长文本按片段上限合并整句
+func (l *TTSLogic) PlanTTS(ctx context.Context, text, speaker, resourceId string, prosody tts.Prosody) *TTSPlan {
+	plan := &TTSPlan{speaker: speaker, resourceId: resourceId, prosody: prosody, cached: !ttsOptions.DisableSentenceCache}
+	if !plan.cached {
+		chunks := []string{text}
+		if len([]rune(text)) > ttsSingleMaxRunes {
+			chunks = tts.SplitText(text, ttsOptions.ChunkRunes)
+		}
+		for _, chunk := range chunks {
+			plan.pieces = append(plan.pieces, ttsPiece{Text: chunk})
+		}
+		return plan
+	}
+
+	hashes := make([]string, 0)
+	seen := map[string]bool{}
+	for _, sentence := range tts.SplitSentences(text, ttsOptions.ChunkRunes) {
+		h := tts.SentenceKey(sentence, speaker, resourceId, prosody)
+		plan.pieces = append(plan.pieces, ttsPiece{Text: sentence, Hash: h})
+		if !seen[h] {
+			seen[h] = true
+			hashes = append(hashes, h)
+		}
+	}
+	cacheModel := ttsSentenceStore
+	found := map[string]ttsPiece{}
+	for start := 0; start < len(hashes); start += ttsCacheBatch {
+		rows, err := cacheModel.FindByHashes(ctx, hashes[start:min(start+ttsCacheBatch, len(hashes))])
+		if err != nil {
+			log.Printf("[TTS] 查询分句缓存失败 - Error: %v", err)
+			break
+		}
+		for _, row := range rows {
+			piece := ttsPiece{Text: row.Text, Hash: row.Hash, Audio: row.Audio}
+			if row.Timings != "" {
+				if err := json.Unmarshal([]byte(row.Timings), &piece.Sentences); err != nil {
+					log.Printf("[TTS] 解析分句缓存时间戳失败 - Hash: %s, Error: %v", row.Hash, err)
+				}
+			}
+			found[row.Hash] = piece
+		}
+	}
+	if len(found) == 0 {
+		return plan
+	}
+	hit := make([]string, 0, len(found))
+	for h := range found {
+		hit = append(hit, h)
+	}
+	if err := cacheModel.Touch(ctx, hit); err != nil {
+		log.Printf("[TTS] 更新分句缓存命中失败 - Error: %v", err)
+	}
+	for i := range plan.pieces {
+		if piece, ok := found[plan.pieces[i].Hash]; ok {
+			plan.pieces[i] = piece
+			plan.hits++
+		}
+	}
+	return plan
+}
+
+// saveSentence 写入新合成的分句音频，失败只记录日志
+func (l *TTSLogic) saveSentence(ctx context.Context, plan *TTSPlan, piece ttsPiece) {
+	var durationMs int64
+	if info, err := audiochunk.ProbeMP3(piece.Audio); err == nil {
+		durationMs = info.DurationMs
+	}
+	timings := ""
+	if len(piece.Sentences) > 0 {
+		if b, err := json.Marshal(piece.Sentences); err == nil {
+			timings = string(b)
+		}
+	}
+	err := ttsSentenceStore.Save(ctx, &model.TTSSentenceCache{
+		Hash:       piece.Hash,
+		Speaker:    plan.speaker,
+		ResourceId: plan.resourceId,
+		Text:       piece.Text,
+		Audio:      piece.Audio,
+		DurationMs: durationMs,
+		Timings:    timings,
+	})
+	if err != nil {
+		log.Printf("[TTS] 写入分句缓存失败 - Hash: %s, Error: %v", piece.Hash, err)
+	}
+}
+
+// saveFresh 计费完成后把本次新合成的分句写回缓存；失败或中断的请求不计费，其合成结果也不入缓存，
+// 避免后续请求免费复用未计费的音频
+func (l *TTSLogic) saveFresh(ctx context.Context, plan *TTSPlan) {
+	if !plan.cached {
+		return
+	}
+	saved := map[string]bool{}
+	for _, piece := range plan.pieces {
+		if !piece.Fresh || saved[piece.Hash] {
+			continue
+		}
+		saved[piece.Hash] = true
+		l.saveSentence(ctx, plan, piece)
+	}
+}
+
+// SynthesizePlan 按并发上限合成计划中缺少音频的片段，再按原文顺序拼接为一个 mp3；
+// progress 不为空时先回调 Start（命中缓存的片段计入已完成），之后每完成一个片段回调 Update
+func (l *TTSLogic) SynthesizePlan(ctx context.Context, plan *TTSPlan, progress TTSProgress) (*tts.TTSResp, error) {
+	todo := plan.pending()
+	total := len(plan.pieces)
+	log.Printf("[TTS] 分段合成 - Pieces: %d, CacheHits: %d, Pending: %d, Concurrency: %d", total, plan.hits, len(todo), ttsOptions.Concurrency)
+	done := total - len(todo)
+	if progress != nil {
+		progress.Start(total, done)
+	}
+
+	requestIds := make([]string, len(plan.pieces))
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, ttsOptions.Concurrency)
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for _, i := range todo {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			var resp *tts.TTSResp
+			var err error
+			for attempt := 1; attempt <= ttsChunkAttempts && ctx.Err() == nil; attempt++ {
+				if resp, err = tts.Svc.SynthesizeWithResource(ctx, plan.pieces[i].Text, plan.speaker, plan.resourceId, plan.prosody); err == nil {
+					break
+				}
+				log.Printf("[TTS] 片段合成失败 - Piece: %d/%d, Attempt: %d, Error: %v", i+1, total, attempt, err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil || resp == nil {
+				if err == nil {
+					err = ctx.Err() // 其它片段失败后被取消
+				}
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			plan.pieces[i].Audio = resp.Audio
+			plan.pieces[i].Sentences = resp.Sentences
+			plan.pieces[i].Fresh = true
+			requestIds[i] = resp.RequestId
+			done++
+			if progress != nil {
+				progress.Update(done)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return l.assemblePlan(ctx, plan, requestIds)
+}
+
+// streamPlan 按原文顺序逐段合成并转发音频：命中缓存的片段整段转发，其余片段边合成边转发；
+// 已转发的音频无法撤回，片段失败时不重试
+func (l *TTSLogic) streamPlan(ctx context.Context, plan *TTSPlan, onAudio TTSStreamFunc) (*tts.TTSResp, error) {
+	log.Printf("[TTS] 流式合成 - Pieces: %d, CacheHits: %d", len(plan.pieces), plan.hits)
+	requestIds := make([]string, len(plan.pieces))
+	done := map[string]ttsPiece{}
+	for i := range plan.pieces {
+		piece := &plan.pieces[i]
+		if prev, ok := done[piece.Hash]; ok && piece.Audio == nil {
+			*piece = prev
+		}
+		if piece.Audio != nil {
+			if err := onAudio(piece.Audio); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		resp, err := tts.Svc.SynthesizeStream(ctx, piece.Text, plan.speaker, plan.resourceId, plan.prosody, onAudio)
+		if err != nil {
+			log.Printf("[TTS] 流式片段合成失败 - Piece: %d/%d, Error: %v", i+1, len(plan.pieces), err)
+			return nil, err
+		}
+		piece.Audio, piece.Sentences, piece.Fresh = resp.Audio, resp.Sentences, true
+		requestIds[i] = resp.RequestId
+		if plan.cached {
+			done[piece.Hash] = *piece
+		}
+	}
+	return l.assemblePlan(ctx, plan, requestIds)
+}
+
+// assemblePlan 按原文顺序拼接各片段的音频，并换算整段的时间戳
+func (l *TTSLogic) assemblePlan(ctx context.Context, plan *TTSPlan, requestIds []string) (*tts.TTSResp, error) {
+	// 重复的分句复用首次合成的音频与时间戳
+	byHash := map[string]ttsPiece{}
+	for _, piece := range plan.pieces {
+		if piece.Hash != "" && piece.Audio != nil {
+			byHash[piece.Hash] = piece
+		}
+	}
+	parts := make([][]byte, len(plan.pieces))
+	requestId := ""
+	for i, piece := range plan.pieces {
+		if piece.Audio == nil {
+			plan.pieces[i] = byHash[piece.Hash]
+		}
+		parts[i] = plan.pieces[i].Audio
+		if requestId == "" {
+			requestId = requestIds[i]
+		}
+	}
+	audio := parts[0]
+	var infos []audiochunk.MP3Info
+	var err error
+	if len(parts) > 1 {
+		audio, infos, err = audiochunk.ConcatMP3(ctx, parts, tts.SampleRate)
+	} else {
+		info, _ := audiochunk.ProbeMP3(audio)
+		infos = []audiochunk.MP3Info{info}
+	}
+	if err != nil {
+		log.Printf("[TTS] 拼接音频失败 - Pieces: %d, Error: %v", len(parts), err)
+		return nil, err
+	}
+	log.Printf("[TTS] 合成完成 - Pieces: %d, CacheHits: %d, Bytes: %d", len(parts), plan.hits, len(audio))
+	sentences := ttsTimings(plan.pieces, infos)
+	durationMs := infos[0].DurationMs
+	if len(parts) > 1 {
+		// 按帧拼接时各段的编码延迟与补齐仍会播放，整段时长以输出音频为准
+		last := infos[len(infos)-1]
+		durationMs = last.OffsetMs + last.DurationMs
+		if info, err := audiochunk.ProbeMP3(audio); err == nil {
+			durationMs = info.DurationMs
+		}
+	}
+	return &tts.TTSResp{
+		Audio:          audio,
+		Size:           len(audio),
+		UsedSpeaker:    plan.speaker,
+		UsedResourceId: plan.resourceId,
+		RequestId:      requestId,
+		Sentences:      sentences,
+		DurationMs:     durationMs,
+	}, nil
+}
+
+// ttsTimings 按各片段在整段音频中的起点把片段内的时间戳换算为整段音频的时间；片段没有词级时间时整段作为一句
+func ttsTimings(pieces []ttsPiece, infos []audiochunk.MP3Info) []tts.TTSSentence {
+	var out []tts.TTSSentence
+	for i, piece := range pieces {
+		offset, duration := infos[i].OffsetMs, infos[i].DurationMs
+		if len(piece.Sentences) > 0 {
+			out = append(out, tts.ShiftSentences(piece.Sentences, offset)...)
+		} else if duration > 0 {
+			out = append(out, tts.TTSSentence{Text: piece.Text, StartMs: offset, EndMs: offset + duration})
+		}
+	}
+	return out
+}
+
+// PurgeTTSSentenceCache 清理长期未使用的分句音频缓存，返回删除条数
+func PurgeTTSSentenceCache(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-ttsOptions.SentenceCacheTTL)
+	cacheModel := ttsSentenceStore
+	var total int64
+	for {
+		n, err := cacheModel.PurgeUnused(ctx, before, ttsCacheBatch)
+		total += n
+		if err != nil || n < ttsCacheBatch {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"go-gin/const/errcode"
+	"go-gin/internal/httpx"
+)
+
+var adminToken string
+
+// SetAdminToken 注入管理接口令牌；未配置时管理接口一律拒绝
+func SetAdminToken(token string) { adminToken = token }
+
+type AdminHeader struct {
+	Token string `header:"X-Admin-Token" binding:"required"`
+}
+
+// AdminCheck 管理接口校验：请求头 X-Admin-Token 必须与配置的令牌一致
+func AdminCheck() httpx.HandlerFunc {
+	return func(ctx *httpx.Context) (any, error) {
+		var req AdminHeader
+		if err := ctx.ShouldBindHeader(&req); err != nil || adminToken == "" {
+			return nil, errcode.ErrAdminForbidden
+		}
+		if subtle.ConstantTimeCompare([]byte(req.Token), []byte(adminToken)) != 1 {
+			return nil, errcode.ErrAdminForbidden
+		}
+		return nil, nil
+	}
+}
@@ -0,0 +1,26 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddEditedToTranscript20261018130100{})
+}
+
+// AddEditedToTranscript20261018130100 为 youtube_transcript 增加 edited_at/edited_by 字段（用户编辑保护）
+type AddEditedToTranscript20261018130100 struct{}
+
+// Up 执行迁移
+func (m *AddEditedToTranscript20261018130100) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("youtube_transcript", "edited_at") {
+		if err := migrator.Exec(`
+            ALTER TABLE youtube_transcript
+            ADD COLUMN edited_at DATETIME NULL COMMENT '最近一次用户编辑时间，非空时自动刷新不覆盖文本' AFTER translate_char_count,
+            ADD COLUMN edited_by VARCHAR(64) NOT NULL DEFAULT '' COMMENT '最近一次编辑的 user_identity' AFTER edited_at;
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
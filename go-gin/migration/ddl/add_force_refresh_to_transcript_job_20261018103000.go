@@ -0,0 +1,25 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddForceRefreshToTranscriptJob20261018103000{})
+}
+
+// AddForceRefreshToTranscriptJob20261018103000 为 transcript_job 增加 force_refresh 字段
+type AddForceRefreshToTranscriptJob20261018103000 struct{}
+
+// Up 执行迁移
+func (m *AddForceRefreshToTranscriptJob20261018103000) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("transcript_job", "force_refresh") {
+		if err := migrator.Exec(`
+            ALTER TABLE transcript_job
+            ADD COLUMN force_refresh TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否忽略已有转录重新生成' AFTER source_lang;
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
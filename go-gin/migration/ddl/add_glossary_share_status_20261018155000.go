@@ -0,0 +1,26 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddGlossaryShareStatus20261018155000{})
+}
+
+// AddGlossaryShareStatus20261018155000 glossary_share 增加状态，成员接受邀请后共享才生效
+type AddGlossaryShareStatus20261018155000 struct{}
+
+// Up 执行迁移
+func (m *AddGlossaryShareStatus20261018155000) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("glossary_share", "status") {
+		if err := migrator.Exec(`
+            ALTER TABLE glossary_share
+            ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'pending' COMMENT '状态：pending 待接受/accepted 已接受' AFTER member_identity,
+            ADD COLUMN accepted_at DATETIME NULL COMMENT '接受时间' AFTER status;
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddGlossaryVersionToTranscript20261018160000{})
+}
+
+// AddGlossaryVersionToTranscript20261018160000 youtube_transcript 按术语表版本区分：
+// 未使用术语的结果为共享缓存，使用术语表翻译的结果单独保存，不覆盖共享缓存
+type AddGlossaryVersionToTranscript20261018160000 struct{}
+
+// Up 执行迁移
+func (m *AddGlossaryVersionToTranscript20261018160000) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("youtube_transcript", "glossary_version") {
+		if err := migrator.Exec(`
+            ALTER TABLE youtube_transcript
+            ADD COLUMN glossary_version VARCHAR(32) NOT NULL DEFAULT '' COMMENT '翻译所用术语表版本，空表示未使用术语（共享缓存）' AFTER language;
+        `); err != nil {
+			return err
+		}
+	}
+	if migrator.HasIndex("youtube_transcript", "uk_video_lang") {
+		if err := migrator.Exec(`
+            ALTER TABLE youtube_transcript
+            DROP INDEX uk_video_lang,
+            ADD UNIQUE KEY uk_video_lang_glossary (video_id, language, glossary_version);
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddLangToTranscript20261018100000{})
+}
+
+// AddLangToTranscript20261018100000 为 youtube_transcript 增加识别语言、为 transcript_job 增加请求的识别语言
+type AddLangToTranscript20261018100000 struct{}
+
+// Up 执行迁移
+func (m *AddLangToTranscript20261018100000) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("youtube_transcript", "detected_lang") {
+		if err := migrator.Exec(`
+            ALTER TABLE youtube_transcript
+            ADD COLUMN detected_lang VARCHAR(16) NOT NULL DEFAULT '' COMMENT 'ASR 识别出的源语言' AFTER language;
+        `); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasColumn("transcript_job", "source_lang") {
+		if err := migrator.Exec(`
+            ALTER TABLE transcript_job
+            ADD COLUMN source_lang VARCHAR(16) NOT NULL DEFAULT '' COMMENT '识别语言：auto 或语言代码，空为默认' AFTER target_lang;
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddOwnerToTranscript20261018161000{})
+}
+
+// AddOwnerToTranscript20261018161000 youtube_transcript 增加 owner_identity/forked_from：
+// 用户首次编辑时复制出自己的副本，共享转录不再被单个用户修改
+type AddOwnerToTranscript20261018161000 struct{}
+
+// Up 执行迁移
+func (m *AddOwnerToTranscript20261018161000) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("youtube_transcript", "owner_identity") {
+		if err := migrator.Exec(`
+            ALTER TABLE youtube_transcript
+            ADD COLUMN owner_identity VARCHAR(64) NOT NULL DEFAULT '' COMMENT '编辑副本所属的 user_identity，空表示共享转录' AFTER glossary_version,
+            ADD COLUMN forked_from BIGINT NOT NULL DEFAULT 0 COMMENT '编辑副本复制自的共享转录ID' AFTER owner_identity;
+        `); err != nil {
+			return err
+		}
+	}
+	if migrator.HasIndex("youtube_transcript", "uk_video_lang_glossary") {
+		if err := migrator.Exec(`
+            ALTER TABLE youtube_transcript
+            DROP INDEX uk_video_lang_glossary,
+            ADD UNIQUE KEY uk_video_lang_glossary_owner (video_id, language, glossary_version, owner_identity),
+            ADD KEY idx_forked_from (forked_from, owner_identity);
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddSearchFulltext20261018150100{})
+}
+
+// AddSearchFulltext20261018150100 为全文搜索建立 ngram 全文索引（支持中日韩），并补充按用户查找转录的索引
+type AddSearchFulltext20261018150100 struct{}
+
+// Up 执行迁移
+func (m *AddSearchFulltext20261018150100) Up(migrator *migration.DDLMigrator) error {
+	indexes := []struct {
+		table, name, sql string
+	}{
+		{"youtube_transcript", "ft_text", `ALTER TABLE youtube_transcript ADD FULLTEXT INDEX ft_text (original_text, translated_text) WITH PARSER ngram;`},
+		{"youtube_transcript_segment", "ft_text", `ALTER TABLE youtube_transcript_segment ADD FULLTEXT INDEX ft_text (original_text, translated_text) WITH PARSER ngram;`},
+		{"youtube_video", "ft_title", `ALTER TABLE youtube_video ADD FULLTEXT INDEX ft_title (title) WITH PARSER ngram;`},
+		{"tts_history", "ft_text", `ALTER TABLE tts_history ADD FULLTEXT INDEX ft_text (text_preview) WITH PARSER ngram;`},
+		// 转录本身没有归属，用户的转录来自其流水线与异步任务
+		{"pipeline_run", "idx_identity_transcript", `ALTER TABLE pipeline_run ADD INDEX idx_identity_transcript (user_identity, transcript_id);`},
+		{"transcript_job", "idx_identity_transcript", `ALTER TABLE transcript_job ADD INDEX idx_identity_transcript (user_identity, transcript_id);`},
+	}
+	for _, idx := range indexes {
+		if migrator.HasIndex(idx.table, idx.name) {
+			continue
+		}
+		if err := migrator.Exec(idx.sql); err != nil {
+			return err
+		}
+	}
+	return nil
+}
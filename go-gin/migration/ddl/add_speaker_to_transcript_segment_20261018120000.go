@@ -0,0 +1,25 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddSpeakerToTranscriptSegment20261018120000{})
+}
+
+// AddSpeakerToTranscriptSegment20261018120000 为 youtube_transcript_segment 增加 speaker 字段（说话人标识）
+type AddSpeakerToTranscriptSegment20261018120000 struct{}
+
+// Up 执行迁移
+func (m *AddSpeakerToTranscriptSegment20261018120000) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("youtube_transcript_segment", "speaker") {
+		if err := migrator.Exec(`
+            ALTER TABLE youtube_transcript_segment
+            ADD COLUMN speaker VARCHAR(16) NOT NULL DEFAULT '' COMMENT '说话人标识（A、B…），未分离时为空' AFTER confidence;
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
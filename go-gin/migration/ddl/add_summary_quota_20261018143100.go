@@ -0,0 +1,48 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddSummaryQuota20261018143100{})
+}
+
+// AddSummaryQuota20261018143100 增加摘要 token 计量：套餐配额、用户余额与每日用量
+type AddSummaryQuota20261018143100 struct{}
+
+// Up 执行迁移
+func (m *AddSummaryQuota20261018143100) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("package", "quota_summary_tokens") {
+		if err := migrator.Exec(`
+            ALTER TABLE package
+            ADD COLUMN quota_summary_tokens INT NOT NULL DEFAULT 0 COMMENT '摘要可用 token 数' AFTER quota_tts_chars;
+        `); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasColumn("user_package", "remain_summary_tokens") {
+		if err := migrator.Exec(`
+            ALTER TABLE user_package
+            ADD COLUMN remain_summary_tokens INT NOT NULL DEFAULT 0 COMMENT '剩余摘要 token' AFTER remain_tts_chars;
+        `); err != nil {
+			return err
+		}
+		// 存量有效套餐赠送一份摘要额度，避免上线后老用户无法使用
+		if err := migrator.Exec(`
+            UPDATE user_package SET remain_summary_tokens = 200000
+            WHERE expire_at IS NULL OR expire_at > NOW();
+        `); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasColumn("usage_daily", "summary_tokens") {
+		if err := migrator.Exec(`
+            ALTER TABLE usage_daily
+            ADD COLUMN summary_tokens INT NOT NULL DEFAULT 0 COMMENT '当日摘要 token' AFTER tm_misses;
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
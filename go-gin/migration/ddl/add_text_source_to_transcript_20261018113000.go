@@ -0,0 +1,25 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddTextSourceToTranscript20261018113000{})
+}
+
+// AddTextSourceToTranscript20261018113000 为 youtube_transcript 增加 text_source 字段（原文来源）
+type AddTextSourceToTranscript20261018113000 struct{}
+
+// Up 执行迁移
+func (m *AddTextSourceToTranscript20261018113000) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("youtube_transcript", "text_source") {
+		if err := migrator.Exec(`
+            ALTER TABLE youtube_transcript
+            ADD COLUMN text_source VARCHAR(16) NOT NULL DEFAULT 'asr' COMMENT '原文来源：asr/manual_caption/auto_caption' AFTER detected_lang;
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
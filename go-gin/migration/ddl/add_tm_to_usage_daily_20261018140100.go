@@ -0,0 +1,26 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddTmToUsageDaily20261018140100{})
+}
+
+// AddTmToUsageDaily20261018140100 为 usage_daily 增加翻译记忆命中/未命中分句数
+type AddTmToUsageDaily20261018140100 struct{}
+
+// Up 执行迁移
+func (m *AddTmToUsageDaily20261018140100) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("usage_daily", "tm_hits") {
+		if err := migrator.Exec(`
+            ALTER TABLE usage_daily
+            ADD COLUMN tm_hits INT NOT NULL DEFAULT 0 COMMENT '当日翻译记忆命中分句数' AFTER requests,
+            ADD COLUMN tm_misses INT NOT NULL DEFAULT 0 COMMENT '当日翻译记忆未命中分句数' AFTER tm_hits;
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddTTSProsody20261018154000{})
+}
+
+// AddTTSProsody20261018154000 tts_history 记录合成使用的韵律，tts_job 保存提交时的韵律参数
+type AddTTSProsody20261018154000 struct{}
+
+// Up 执行迁移
+func (m *AddTTSProsody20261018154000) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("tts_history", "speed_ratio") {
+		if err := migrator.Exec(`
+            ALTER TABLE tts_history
+            ADD COLUMN speed_ratio DECIMAL(3,2) NOT NULL DEFAULT 1.00 COMMENT '语速倍率' AFTER speaker,
+            ADD COLUMN loudness_ratio DECIMAL(3,2) NOT NULL DEFAULT 1.00 COMMENT '音量倍率' AFTER speed_ratio,
+            ADD COLUMN pitch TINYINT NOT NULL DEFAULT 0 COMMENT '音调（半音）' AFTER loudness_ratio,
+            ADD COLUMN emotion VARCHAR(32) NOT NULL DEFAULT '' COMMENT '情感' AFTER pitch,
+            ADD COLUMN emotion_scale TINYINT NOT NULL DEFAULT 0 COMMENT '情感强度 1~5，0 表示默认' AFTER emotion;
+        `); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasColumn("tts_job", "prosody") {
+		if err := migrator.Exec(`
+            ALTER TABLE tts_job
+            ADD COLUMN prosody VARCHAR(255) NOT NULL DEFAULT '' COMMENT '韵律参数 JSON，为空时使用音色默认' AFTER use_my_voice;
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
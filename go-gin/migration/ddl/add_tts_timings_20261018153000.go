@@ -0,0 +1,34 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddTTSTimings20261018153000{})
+}
+
+// AddTTSTimings20261018153000 tts_history 与 tts_sentence_cache 增加逐句、逐词时间戳
+type AddTTSTimings20261018153000 struct{}
+
+// Up 执行迁移
+func (m *AddTTSTimings20261018153000) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("tts_history", "timings") {
+		if err := migrator.Exec(`
+            ALTER TABLE tts_history
+            ADD COLUMN duration_ms INT NOT NULL DEFAULT 0 COMMENT '音频时长（毫秒）' AFTER audio_url,
+            ADD COLUMN timings MEDIUMTEXT NULL COMMENT '逐句、逐词时间戳（JSON，毫秒）' AFTER duration_ms;
+        `); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasColumn("tts_sentence_cache", "timings") {
+		if err := migrator.Exec(`
+            ALTER TABLE tts_sentence_cache
+            ADD COLUMN timings TEXT NULL COMMENT '分句内的逐词时间戳（JSON，毫秒，相对分句音频起点）' AFTER duration_ms;
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&AddWordsToTranscriptSegment20261018123000{})
+}
+
+// AddWordsToTranscriptSegment20261018123000 为 youtube_transcript_segment 增加 words 字段（词级时间戳与置信度）
+type AddWordsToTranscriptSegment20261018123000 struct{}
+
+// Up 执行迁移
+func (m *AddWordsToTranscriptSegment20261018123000) Up(migrator *migration.DDLMigrator) error {
+	if !migrator.HasColumn("youtube_transcript_segment", "words") {
+		if err := migrator.Exec(`
+            ALTER TABLE youtube_transcript_segment
+            ADD COLUMN words MEDIUMTEXT NULL COMMENT '词级时间戳与置信度(JSON)' AFTER speaker;
+        `); err != nil {
+			return err
+		}
+	}
+	return nil
+}
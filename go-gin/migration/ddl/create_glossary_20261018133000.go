@@ -0,0 +1,40 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&CreateGlossary20261018133000{})
+}
+
+// CreateGlossary20261018133000 创建 glossary_term（用户术语表）与 glossary_share（术语表共享）表
+type CreateGlossary20261018133000 struct{}
+
+// Up 执行迁移
+func (m *CreateGlossary20261018133000) Up(migrator *migration.DDLMigrator) error {
+	if err := migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS glossary_term (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			user_identity VARCHAR(64) NOT NULL COMMENT '所属用户',
+			source_term VARCHAR(255) NOT NULL COMMENT '原文术语',
+			target_term VARCHAR(255) NOT NULL COMMENT '指定译法',
+			case_sensitive TINYINT(1) NOT NULL DEFAULT 0 COMMENT '匹配原文时是否区分大小写',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+			UNIQUE KEY uk_user_source (user_identity, source_term)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`); err != nil {
+		return err
+	}
+	return migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS glossary_share (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			owner_identity VARCHAR(64) NOT NULL COMMENT '术语表所有者',
+			member_identity VARCHAR(64) NOT NULL COMMENT '共享给的团队成员',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+			UNIQUE KEY uk_owner_member (owner_identity, member_identity),
+			KEY idx_member (member_identity)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`)
+}
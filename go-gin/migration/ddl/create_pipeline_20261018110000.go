@@ -0,0 +1,56 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&CreatePipeline20261018110000{})
+}
+
+// CreatePipeline20261018110000 创建 pipeline_run / pipeline_stage 表（可续跑的多阶段流水线）
+type CreatePipeline20261018110000 struct{}
+
+// Up 执行迁移
+func (m *CreatePipeline20261018110000) Up(migrator *migration.DDLMigrator) error {
+	if err := migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS pipeline_run (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			kind VARCHAR(32) NOT NULL DEFAULT '' COMMENT '流水线类型，如 transcript',
+			user_identity VARCHAR(128) NOT NULL DEFAULT '' COMMENT '发起人',
+			job_id CHAR(36) NOT NULL DEFAULT '' COMMENT '关联的 transcript_job.job_id，同步请求为空',
+			params TEXT NULL COMMENT '请求参数(JSON)',
+			status VARCHAR(16) NOT NULL DEFAULT 'running' COMMENT 'running/done/failed',
+			current_stage VARCHAR(16) NOT NULL DEFAULT '' COMMENT '当前/最后执行的阶段',
+			error_code INT NOT NULL DEFAULT 0 COMMENT '失败错误码',
+			error_msg VARCHAR(512) NOT NULL DEFAULT '' COMMENT '失败原因',
+			transcript_id BIGINT NOT NULL DEFAULT 0 COMMENT 'youtube_transcript.id',
+			attempts INT NOT NULL DEFAULT 1 COMMENT '执行次数',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+			KEY idx_status_updated (status, updated_at),
+			KEY idx_job_id (job_id)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`); err != nil {
+		return err
+	}
+	return migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS pipeline_stage (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			run_id BIGINT NOT NULL COMMENT 'pipeline_run.id',
+			stage VARCHAR(16) NOT NULL COMMENT 'info/captions/audio/asr/translate/persist/bill',
+			status VARCHAR(16) NOT NULL DEFAULT 'running' COMMENT 'running/done/failed/skipped',
+			input MEDIUMTEXT NULL COMMENT '阶段输入(JSON)',
+			output LONGTEXT NULL COMMENT '阶段输出(JSON)，续跑时直接复用',
+			error_msg VARCHAR(512) NOT NULL DEFAULT '' COMMENT '失败原因',
+			attempts INT NOT NULL DEFAULT 0 COMMENT '执行次数',
+			started_at DATETIME NULL COMMENT '最近一次开始时间',
+			finished_at DATETIME NULL COMMENT '最近一次结束时间',
+			duration_ms BIGINT NOT NULL DEFAULT 0 COMMENT '最近一次耗时(毫秒)',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+			UNIQUE KEY uk_run_stage (run_id, stage),
+			CONSTRAINT fk_stage_run FOREIGN KEY (run_id) REFERENCES pipeline_run(id) ON DELETE CASCADE ON UPDATE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`)
+}
@@ -0,0 +1,34 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&CreateTranscriptJob20261018090000{})
+}
+
+// CreateTranscriptJob20261018090000 创建 transcript_job 表（异步转录任务）
+type CreateTranscriptJob20261018090000 struct{}
+
+// Up 执行迁移
+func (m *CreateTranscriptJob20261018090000) Up(migrator *migration.DDLMigrator) error {
+	return migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS transcript_job (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			job_id CHAR(36) NOT NULL COMMENT '对外任务ID(uuid)',
+			user_identity VARCHAR(128) NOT NULL DEFAULT '' COMMENT '提交人',
+			id_or_url VARCHAR(512) NOT NULL DEFAULT '' COMMENT '视频ID或链接',
+			platform VARCHAR(16) NOT NULL DEFAULT '' COMMENT '平台类型',
+			target_lang VARCHAR(16) NOT NULL DEFAULT '' COMMENT '目标语言',
+			stage VARCHAR(16) NOT NULL DEFAULT 'queued' COMMENT 'queued/downloading/recognizing/translating/done/failed',
+			error_code INT NOT NULL DEFAULT 0 COMMENT '失败错误码',
+			error_msg VARCHAR(512) NOT NULL DEFAULT '' COMMENT '失败原因',
+			transcript_id BIGINT NOT NULL DEFAULT 0 COMMENT 'youtube_transcript.id',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+			UNIQUE KEY uk_job_id (job_id),
+			KEY idx_identity_created (user_identity, created_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`)
+}
@@ -0,0 +1,32 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&CreateTranscriptQa20261018150000{})
+}
+
+// CreateTranscriptQa20261018150000 创建 youtube_transcript_qa 表（转录问答历史）
+type CreateTranscriptQa20261018150000 struct{}
+
+// Up 执行迁移
+func (m *CreateTranscriptQa20261018150000) Up(migrator *migration.DDLMigrator) error {
+	return migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS youtube_transcript_qa (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			transcript_id BIGINT NOT NULL COMMENT 'youtube_transcript.id',
+			user_identity VARCHAR(64) NOT NULL COMMENT '提问用户',
+			question TEXT NOT NULL COMMENT '问题',
+			answer TEXT NOT NULL COMMENT '回答',
+			citations TEXT NOT NULL COMMENT '引用的分句 JSON 数组（起止毫秒、原文）',
+			retrieval VARCHAR(16) NOT NULL DEFAULT '' COMMENT '检索方式：full | bm25 | embedding',
+			provider VARCHAR(32) NOT NULL DEFAULT '' COMMENT 'llm 提供方',
+			model VARCHAR(64) NOT NULL DEFAULT '' COMMENT '模型',
+			tokens INT NOT NULL DEFAULT 0 COMMENT '消耗的 token 数',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '提问时间',
+			KEY idx_transcript_user (transcript_id, user_identity, id)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`)
+}
@@ -0,0 +1,33 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&CreateTranscriptRevision20261018130000{})
+}
+
+// CreateTranscriptRevision20261018130000 创建 youtube_transcript_revision 表（转录修订历史）
+type CreateTranscriptRevision20261018130000 struct{}
+
+// Up 执行迁移
+func (m *CreateTranscriptRevision20261018130000) Up(migrator *migration.DDLMigrator) error {
+	return migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS youtube_transcript_revision (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			transcript_id BIGINT NOT NULL COMMENT 'youtube_transcript.id',
+			rev_no INT NOT NULL COMMENT '修订号，从1开始递增',
+			source VARCHAR(16) NOT NULL DEFAULT 'user' COMMENT '来源：auto 自动生成/user 用户编辑/restore 恢复',
+			author VARCHAR(64) NOT NULL DEFAULT '' COMMENT '修改人 user_identity，自动生成时为空',
+			note VARCHAR(255) NOT NULL DEFAULT '' COMMENT '修改说明',
+			applied TINYINT(1) NOT NULL DEFAULT 1 COMMENT '是否已应用到转录（受编辑保护的自动刷新结果为0）',
+			original_text MEDIUMTEXT NULL COMMENT '原文快照',
+			translated_text MEDIUMTEXT NULL COMMENT '译文快照',
+			segments LONGTEXT NULL COMMENT '分句快照(JSON)',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+			UNIQUE KEY uk_transcript_rev (transcript_id, rev_no),
+			CONSTRAINT fk_revision_transcript FOREIGN KEY (transcript_id) REFERENCES youtube_transcript(id) ON DELETE CASCADE ON UPDATE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`)
+}
@@ -0,0 +1,28 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&CreateTranscriptSpeaker20261018120100{})
+}
+
+// CreateTranscriptSpeaker20261018120100 创建 youtube_transcript_speaker 表（说话人显示名）
+type CreateTranscriptSpeaker20261018120100 struct{}
+
+// Up 执行迁移
+func (m *CreateTranscriptSpeaker20261018120100) Up(migrator *migration.DDLMigrator) error {
+	return migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS youtube_transcript_speaker (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			transcript_id BIGINT NOT NULL COMMENT 'youtube_transcript.id',
+			speaker VARCHAR(16) NOT NULL COMMENT '说话人标识（A、B…）',
+			name VARCHAR(64) NOT NULL DEFAULT '' COMMENT '显示名',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+			UNIQUE KEY uk_transcript_speaker (transcript_id, speaker),
+			CONSTRAINT fk_speaker_transcript FOREIGN KEY (transcript_id) REFERENCES youtube_transcript(id) ON DELETE CASCADE ON UPDATE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`)
+}
@@ -0,0 +1,33 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&CreateTranscriptSummary20261018143000{})
+}
+
+// CreateTranscriptSummary20261018143000 创建 youtube_transcript_summary 表（转录摘要、要点与章节缓存）
+type CreateTranscriptSummary20261018143000 struct{}
+
+// Up 执行迁移
+func (m *CreateTranscriptSummary20261018143000) Up(migrator *migration.DDLMigrator) error {
+	return migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS youtube_transcript_summary (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			transcript_id BIGINT NOT NULL COMMENT 'youtube_transcript.id',
+			language VARCHAR(16) NOT NULL COMMENT '摘要语言',
+			source_hash CHAR(64) NOT NULL COMMENT '生成时分句文本的摘要，文本变化后缓存失效',
+			summary TEXT NOT NULL COMMENT '摘要',
+			key_points TEXT NOT NULL COMMENT '要点 JSON 数组',
+			chapters MEDIUMTEXT NOT NULL COMMENT '章节 JSON 数组（起止毫秒、标题、概要）',
+			provider VARCHAR(32) NOT NULL DEFAULT '' COMMENT 'llm 提供方',
+			model VARCHAR(64) NOT NULL DEFAULT '' COMMENT '模型',
+			tokens INT NOT NULL DEFAULT 0 COMMENT '生成消耗的 token 数',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+			UNIQUE KEY uk_transcript_lang (transcript_id, language)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`)
+}
@@ -0,0 +1,32 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&CreateTranslationMemory20261018140000{})
+}
+
+// CreateTranslationMemory20261018140000 创建 translation_memory 表（分句级翻译记忆）
+type CreateTranslationMemory20261018140000 struct{}
+
+// Up 执行迁移
+func (m *CreateTranslationMemory20261018140000) Up(migrator *migration.DDLMigrator) error {
+	return migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS translation_memory (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			hash CHAR(64) NOT NULL COMMENT 'sha256(原文, 源语言, 目标语言, 提供方, 术语表版本)',
+			source_lang VARCHAR(16) NOT NULL DEFAULT '' COMMENT '源语言',
+			target_lang VARCHAR(16) NOT NULL COMMENT '目标语言',
+			provider VARCHAR(32) NOT NULL COMMENT '翻译提供方',
+			glossary_version VARCHAR(32) NOT NULL DEFAULT '' COMMENT '术语表版本，无术语时为空',
+			source_text TEXT NOT NULL COMMENT '原文分句',
+			target_text TEXT NOT NULL COMMENT '译文',
+			hits INT NOT NULL DEFAULT 0 COMMENT '命中次数',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+			UNIQUE KEY uk_hash (hash)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`)
+}
@@ -0,0 +1,37 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&CreateTTSJob20261018151000{})
+}
+
+// CreateTTSJob20261018151000 创建 tts_job 表（长文本异步合成任务）
+type CreateTTSJob20261018151000 struct{}
+
+// Up 执行迁移
+func (m *CreateTTSJob20261018151000) Up(migrator *migration.DDLMigrator) error {
+	return migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS tts_job (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			job_id CHAR(36) NOT NULL COMMENT '对外任务ID(uuid)',
+			user_identity VARCHAR(128) NOT NULL DEFAULT '' COMMENT '提交人',
+			text MEDIUMTEXT NOT NULL COMMENT '合成文本',
+			speaker VARCHAR(128) NOT NULL DEFAULT '' COMMENT '请求的音色',
+			use_my_voice TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否使用我的声音',
+			char_count INT NOT NULL DEFAULT 0 COMMENT '字符数',
+			status VARCHAR(16) NOT NULL DEFAULT 'queued' COMMENT 'queued/running/done/failed',
+			total_chunks INT NOT NULL DEFAULT 0 COMMENT '切分的片段数',
+			done_chunks INT NOT NULL DEFAULT 0 COMMENT '已合成的片段数',
+			history_id BIGINT NOT NULL DEFAULT 0 COMMENT 'tts_history.id',
+			error_code INT NOT NULL DEFAULT 0 COMMENT '失败错误码',
+			error_msg VARCHAR(512) NOT NULL DEFAULT '' COMMENT '失败原因',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+			UNIQUE KEY uk_job_id (job_id),
+			KEY idx_identity_created (user_identity, created_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`)
+}
@@ -0,0 +1,46 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&CreateTTSSentenceCache20261018152000{})
+}
+
+// CreateTTSSentenceCache20261018152000 创建 tts_sentence_cache 表（分句音频缓存），tts_history 增加实际计费字数
+type CreateTTSSentenceCache20261018152000 struct{}
+
+// Up 执行迁移
+func (m *CreateTTSSentenceCache20261018152000) Up(migrator *migration.DDLMigrator) error {
+	if err := migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS tts_sentence_cache (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			hash CHAR(64) NOT NULL COMMENT 'sha256(规范化分句|音色|资源|音频参数)',
+			speaker VARCHAR(128) NOT NULL DEFAULT '' COMMENT '音色',
+			resource_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT '合成资源',
+			text TEXT NOT NULL COMMENT '规范化后的分句',
+			audio MEDIUMBLOB NOT NULL COMMENT '分句音频（mp3）',
+			duration_ms INT NOT NULL DEFAULT 0 COMMENT '音频时长（毫秒）',
+			hits INT NOT NULL DEFAULT 0 COMMENT '命中次数',
+			last_used_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '最近使用时间，长期未使用的缓存会被清理',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+			UNIQUE KEY uk_hash (hash),
+			KEY idx_last_used (last_used_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`); err != nil {
+		return err
+	}
+	if !migrator.HasColumn("tts_history", "billed_chars") {
+		if err := migrator.Exec(`
+            ALTER TABLE tts_history
+            ADD COLUMN billed_chars INT NOT NULL DEFAULT 0 COMMENT '实际计费字数（命中分句缓存的部分不计费）' AFTER char_count;
+        `); err != nil {
+			return err
+		}
+		if err := migrator.Exec(`UPDATE tts_history SET billed_chars = char_count;`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
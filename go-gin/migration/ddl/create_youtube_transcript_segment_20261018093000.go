@@ -0,0 +1,32 @@
+package ddl
+
+import (
+	"go-gin/internal/migration"
+)
+
+func init() {
+	migration.RegisterDDL(&CreateYoutubeTranscriptSegment20261018093000{})
+}
+
+// CreateYoutubeTranscriptSegment20261018093000 创建 youtube_transcript_segment 表（带时间戳的分句）
+type CreateYoutubeTranscriptSegment20261018093000 struct{}
+
+// Up 执行迁移
+func (m *CreateYoutubeTranscriptSegment20261018093000) Up(migrator *migration.DDLMigrator) error {
+	return migrator.Exec(`
+		CREATE TABLE IF NOT EXISTS youtube_transcript_segment (
+			id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+			transcript_id BIGINT NOT NULL COMMENT 'youtube_transcript.id',
+			seg_index INT NOT NULL DEFAULT 0 COMMENT '分句序号，从0开始',
+			start_ms BIGINT NOT NULL DEFAULT 0 COMMENT '开始时间(毫秒)',
+			end_ms BIGINT NOT NULL DEFAULT 0 COMMENT '结束时间(毫秒)',
+			original_text TEXT NULL COMMENT '原文',
+			translated_text TEXT NULL COMMENT '译文',
+			confidence FLOAT NOT NULL DEFAULT 0 COMMENT '词置信度均值',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+			UNIQUE KEY uk_transcript_index (transcript_id, seg_index),
+			CONSTRAINT fk_segment_transcript FOREIGN KEY (transcript_id) REFERENCES youtube_transcript(id) ON DELETE CASCADE ON UPDATE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`)
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"context"
+	"go-gin/internal/component/db"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+type GlossaryTerm struct {
+	Id            int64     `gorm:"column:id;primaryKey" json:"id"`
+	UserIdentity  string    `gorm:"column:user_identity" json:"user_identity"`
+	SourceTerm    string    `gorm:"column:source_term" json:"source_term"`
+	TargetTerm    string    `gorm:"column:target_term" json:"target_term"`
+	CaseSensitive bool      `gorm:"column:case_sensitive" json:"case_sensitive"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (GlossaryTerm) TableName() string { return "glossary_term" }
+
+// 共享状态
+const (
+	GlossaryShareStatusPending  = "pending"  // 待成员接受
+	GlossaryShareStatusAccepted = "accepted" // 已接受，成员翻译时使用
+)
+
+// GlossaryShare 所有者把术语表共享给团队成员，成员接受后翻译时一并使用
+type GlossaryShare struct {
+	Id             int64      `gorm:"column:id;primaryKey" json:"id"`
+	OwnerIdentity  string     `gorm:"column:owner_identity" json:"owner_identity"`
+	MemberIdentity string     `gorm:"column:member_identity" json:"member_identity"`
+	Status         string     `gorm:"column:status" json:"status"`
+	AcceptedAt     *time.Time `gorm:"column:accepted_at" json:"accepted_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (GlossaryShare) TableName() string { return "glossary_share" }
+
+type GlossaryModel struct{}
+
+func NewGlossaryModel() *GlossaryModel {
+	return &GlossaryModel{}
+}
+
+// ListByOwners 获取多个用户的术语，按所有者与原文排序
+func (m *GlossaryModel) ListByOwners(ctx context.Context, identities []string) ([]GlossaryTerm, error) {
+	var items []GlossaryTerm
+	if len(identities) == 0 {
+		return items, nil
+	}
+	err := db.WithContext(ctx).Where("user_identity IN ?", identities).Order("user_identity asc, source_term asc").Find(&items).Error()
+	return items, err
+}
+
+// Get 获取用户自己的术语
+func (m *GlossaryModel) Get(ctx context.Context, identity string, id int64) (*GlossaryTerm, error) {
+	var item GlossaryTerm
+	err := db.WithContext(ctx).Where("id = ? AND user_identity = ?", id, identity).First(&item).Error()
+	return &item, err
+}
+
+// GetBySource 按原文查找用户的术语
+func (m *GlossaryModel) GetBySource(ctx context.Context, identity, source string) (*GlossaryTerm, error) {
+	var item GlossaryTerm
+	err := db.WithContext(ctx).Where("user_identity = ? AND source_term = ?", identity, source).First(&item).Error()
+	return &item, err
+}
+
+// Add 新增术语
+func (m *GlossaryModel) Add(ctx context.Context, item *GlossaryTerm) error {
+	return db.WithContext(ctx).Create(item).Error()
+}
+
+// Update 更新用户自己的术语
+func (m *GlossaryModel) Update(ctx context.Context, identity string, id int64, updates map[string]any) error {
+	return db.WithContext(ctx).Model(&GlossaryTerm{}).Where("id = ? AND user_identity = ?", id, identity).Updates(updates).Error
+}
+
+// Delete 删除用户自己的术语
+func (m *GlossaryModel) Delete(ctx context.Context, identity string, id int64) error {
+	return db.WithContext(ctx).Where("id = ? AND user_identity = ?", id, identity).Delete(&GlossaryTerm{}).Error()
+}
+
+// Upsert 按 (user_identity, source_term) 新增或覆盖译法
+func (m *GlossaryModel) Upsert(ctx context.Context, item *GlossaryTerm) error {
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_identity"}, {Name: "source_term"}},
+		DoUpdates: clause.AssignmentColumns([]string{"target_term", "case_sensitive", "updated_at"}),
+	}).Create(item).Error()
+}
+
+// ListMembers 获取所有者共享给的成员
+func (m *GlossaryModel) ListMembers(ctx context.Context, owner string) ([]GlossaryShare, error) {
+	var items []GlossaryShare
+	err := db.WithContext(ctx).Where("owner_identity = ?", owner).Order("id asc").Find(&items).Error()
+	return items, err
+}
+
+// ListOwners 获取共享给该成员且已接受的所有者
+func (m *GlossaryModel) ListOwners(ctx context.Context, member string) ([]string, error) {
+	return m.listOwners(ctx, member, GlossaryShareStatusAccepted)
+}
+
+// ListInviters 获取邀请该成员、尚未接受的所有者
+func (m *GlossaryModel) ListInviters(ctx context.Context, member string) ([]string, error) {
+	return m.listOwners(ctx, member, GlossaryShareStatusPending)
+}
+
+func (m *GlossaryModel) listOwners(ctx context.Context, member, status string) ([]string, error) {
+	var items []GlossaryShare
+	if err := db.WithContext(ctx).Where("member_identity = ? AND status = ?", member, status).Order("id asc").Find(&items).Error(); err != nil {
+		return nil, err
+	}
+	owners := make([]string, 0, len(items))
+	for _, v := range items {
+		owners = append(owners, v.OwnerIdentity)
+	}
+	return owners, nil
+}
+
+// AddShare 邀请成员共享术语表，成员接受前不生效（已邀请或已共享时忽略）
+func (m *GlossaryModel) AddShare(ctx context.Context, owner, member string) error {
+	return db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&GlossaryShare{OwnerIdentity: owner, MemberIdentity: member, Status: GlossaryShareStatusPending}).Error()
+}
+
+// AcceptShare 成员接受邀请，返回是否存在待接受的邀请
+func (m *GlossaryModel) AcceptShare(ctx context.Context, owner, member string) (bool, error) {
+	res := db.WithContext(ctx).Model(&GlossaryShare{}).
+		Where("owner_identity = ? AND member_identity = ? AND status = ?", owner, member, GlossaryShareStatusPending).
+		Updates(map[string]any{"status": GlossaryShareStatusAccepted, "accepted_at": time.Now()})
+	return res.RowsAffected > 0, res.Error
+}
+
+// DeleteShare 取消共享
+func (m *GlossaryModel) DeleteShare(ctx context.Context, owner, member string) error {
+	return db.WithContext(ctx).Where("owner_identity = ? AND member_identity = ?", owner, member).Delete(&GlossaryShare{}).Error()
+}
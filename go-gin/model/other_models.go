@@ -5,17 +5,25 @@ import (
 )
 
 type TTSHistory struct {
-	Id           int64     `gorm:"column:id;primaryKey" json:"id"`
-	UserIdentity string    `gorm:"column:user_identity" json:"user_identity"`
-	TextHash     string    `gorm:"column:text_hash" json:"text_hash"`
-	TextPreview  string    `gorm:"column:text_preview" json:"text_preview"`
-	CharCount    int       `gorm:"column:char_count" json:"char_count"`
-	Speaker      string    `gorm:"column:speaker" json:"speaker"`
-	AudioUrl     string    `gorm:"column:audio_url" json:"audio_url"`
-	RequestId    string    `gorm:"column:request_id" json:"request_id"`
-	Status       int       `gorm:"column:status" json:"status"`
-	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
-	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	Id            int64     `gorm:"column:id;primaryKey" json:"id"`
+	UserIdentity  string    `gorm:"column:user_identity" json:"user_identity"`
+	TextHash      string    `gorm:"column:text_hash" json:"text_hash"`
+	TextPreview   string    `gorm:"column:text_preview" json:"text_preview"`
+	CharCount     int       `gorm:"column:char_count" json:"char_count"`
+	BilledChars   int       `gorm:"column:billed_chars" json:"billed_chars"` // 实际计费字数，命中分句缓存的部分不计费
+	Speaker       string    `gorm:"column:speaker" json:"speaker"`
+	AudioUrl      string    `gorm:"column:audio_url" json:"audio_url"`
+	DurationMs    int64     `gorm:"column:duration_ms" json:"duration_ms"`
+	Timings       string    `gorm:"column:timings" json:"-"` // 逐句、逐词时间戳 JSON，通过 /history/tts/:id/timings 获取
+	RequestId     string    `gorm:"column:request_id" json:"request_id"`
+	Status        int       `gorm:"column:status" json:"status"`
+	SpeedRatio    float64   `gorm:"column:speed_ratio" json:"speed_ratio"` // 合成时使用的韵律：语速、音量倍率，音调，情感及强度
+	LoudnessRatio float64   `gorm:"column:loudness_ratio" json:"loudness_ratio"`
+	Pitch         int       `gorm:"column:pitch" json:"pitch"`
+	Emotion       string    `gorm:"column:emotion" json:"emotion"`
+	EmotionScale  int       `gorm:"column:emotion_scale" json:"emotion_scale"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 }
 
 func (TTSHistory) TableName() string { return "tts_history" }
@@ -31,34 +39,40 @@ type UserWhitelist struct {
 func (UserWhitelist) TableName() string { return "user_whitelist" }
 
 type Package struct {
-	Id            int64  `gorm:"column:id;primaryKey" json:"id"`
-	Name          string `gorm:"column:name" json:"name"`
-	QuotaASRChars int    `gorm:"column:quota_asr_chars" json:"quota_asr_chars"`
-	QuotaTTSChars int    `gorm:"column:quota_tts_chars" json:"quota_tts_chars"`
-	MonthlyReset  int    `gorm:"column:monthly_reset" json:"monthly_reset"`
+	Id                 int64  `gorm:"column:id;primaryKey" json:"id"`
+	Name               string `gorm:"column:name" json:"name"`
+	QuotaASRChars      int    `gorm:"column:quota_asr_chars" json:"quota_asr_chars"`
+	QuotaTTSChars      int    `gorm:"column:quota_tts_chars" json:"quota_tts_chars"`
+	QuotaSummaryTokens int    `gorm:"column:quota_summary_tokens" json:"quota_summary_tokens"` // 摘要可用 token 数
+	MonthlyReset       int    `gorm:"column:monthly_reset" json:"monthly_reset"`
 }
 
 func (Package) TableName() string { return "package" }
 
 type UserPackage struct {
-	Id             int64   `gorm:"column:id;primaryKey" json:"id"`
-	UserIdentity   string  `gorm:"column:user_identity" json:"user_identity"`
-	PackageId      int64   `gorm:"column:package_id" json:"package_id"`
-	RemainASRChars int     `gorm:"column:remain_asr_chars" json:"remain_asr_chars"`
-	RemainTTSChars int     `gorm:"column:remain_tts_chars" json:"remain_tts_chars"`
-	ExpireAt       *string `gorm:"column:expire_at" json:"expire_at"`
+	Id                  int64   `gorm:"column:id;primaryKey" json:"id"`
+	UserIdentity        string  `gorm:"column:user_identity" json:"user_identity"`
+	PackageId           int64   `gorm:"column:package_id" json:"package_id"`
+	RemainASRChars      int     `gorm:"column:remain_asr_chars" json:"remain_asr_chars"`
+	RemainTTSChars      int     `gorm:"column:remain_tts_chars" json:"remain_tts_chars"`
+	RemainSummaryTokens int     `gorm:"column:remain_summary_tokens" json:"remain_summary_tokens"` // 剩余摘要 token
+	ExpireAt            *string `gorm:"column:expire_at" json:"expire_at"`
 }
 
 func (UserPackage) TableName() string { return "user_package" }
 
 type UsageDaily struct {
-	Id           int64     `gorm:"column:id;primaryKey" json:"id"`
-	UserIdentity string    `gorm:"column:user_identity" json:"user_identity"`
-	Date         string    `gorm:"column:date" json:"date"`
-	ASRChars     int       `gorm:"column:asr_chars" json:"asr_chars"`
-	TTSChars     int       `gorm:"column:tts_chars" json:"tts_chars"`
-	Requests     int       `gorm:"column:requests" json:"requests"`
-	CreatedAt    time.Time `gorm:"column:created_at" json:"created_at"`
+	Id            int64     `gorm:"column:id;primaryKey" json:"id"`
+	UserIdentity  string    `gorm:"column:user_identity" json:"user_identity"`
+	Date          string    `gorm:"column:date" json:"date"`
+	ASRChars      int       `gorm:"column:asr_chars" json:"asr_chars"`
+	TTSChars      int       `gorm:"column:tts_chars" json:"tts_chars"`
+	Requests      int       `gorm:"column:requests" json:"requests"`
+	TMHits        int       `gorm:"column:tm_hits" json:"tm_hits"`     // 翻译记忆命中分句数
+	TMMisses      int       `gorm:"column:tm_misses" json:"tm_misses"` // 翻译记忆未命中分句数
+	TMHitRatio    float64   `gorm:"-" json:"tm_hit_ratio"`
+	SummaryTokens int       `gorm:"column:summary_tokens" json:"summary_tokens"` // 摘要消耗 token
+	CreatedAt     time.Time `gorm:"column:created_at" json:"created_at"`
 }
 
 func (UsageDaily) TableName() string { return "usage_daily" }
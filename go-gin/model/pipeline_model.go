@@ -0,0 +1,146 @@
+package model
+
+import (
+	"context"
+	"go-gin/internal/component/db"
+	"time"
+)
+
+// 流水线类型
+const PipelineKindTranscript = "transcript"
+
+// 流水线状态
+const (
+	PipelineStatusRunning = "running"
+	PipelineStatusDone    = "done"
+	PipelineStatusFailed  = "failed"
+)
+
+// 转录流水线的阶段，按执行顺序排列
+const (
+	PipelineStageInfo      = "info"
+	PipelineStageCaptions  = "captions"
+	PipelineStageAudio     = "audio"
+	PipelineStageASR       = "asr"
+	PipelineStageTranslate = "translate"
+	PipelineStagePersist   = "persist"
+	PipelineStageBill      = "bill"
+)
+
+// 阶段状态
+const (
+	StageStatusRunning = "running"
+	StageStatusDone    = "done"
+	StageStatusFailed  = "failed"
+	StageStatusSkipped = "skipped"
+)
+
+type PipelineRun struct {
+	Id           int64     `gorm:"column:id;primaryKey" json:"id"`
+	Kind         string    `gorm:"column:kind" json:"kind"`
+	UserIdentity string    `gorm:"column:user_identity" json:"user_identity"`
+	JobId        string    `gorm:"column:job_id" json:"job_id"`
+	Params       string    `gorm:"column:params" json:"params"`
+	Status       string    `gorm:"column:status" json:"status"`
+	CurrentStage string    `gorm:"column:current_stage" json:"current_stage"`
+	ErrorCode    int       `gorm:"column:error_code" json:"error_code"`
+	ErrorMsg     string    `gorm:"column:error_msg" json:"error_msg"`
+	TranscriptId int64     `gorm:"column:transcript_id" json:"transcript_id"`
+	Attempts     int       `gorm:"column:attempts" json:"attempts"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (PipelineRun) TableName() string { return "pipeline_run" }
+
+type PipelineStage struct {
+	Id         int64      `gorm:"column:id;primaryKey" json:"id"`
+	RunId      int64      `gorm:"column:run_id" json:"run_id"`
+	Stage      string     `gorm:"column:stage" json:"stage"`
+	Status     string     `gorm:"column:status" json:"status"`
+	Input      string     `gorm:"column:input" json:"input"`
+	Output     string     `gorm:"column:output" json:"output"`
+	ErrorMsg   string     `gorm:"column:error_msg" json:"error_msg"`
+	Attempts   int        `gorm:"column:attempts" json:"attempts"`
+	StartedAt  *time.Time `gorm:"column:started_at" json:"started_at"`
+	FinishedAt *time.Time `gorm:"column:finished_at" json:"finished_at"`
+	DurationMs int64      `gorm:"column:duration_ms" json:"duration_ms"`
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (PipelineStage) TableName() string { return "pipeline_stage" }
+
+// Finished 阶段已完成（或被跳过），续跑时无需再执行
+func (s *PipelineStage) Finished() bool {
+	return s.Status == StageStatusDone || s.Status == StageStatusSkipped
+}
+
+type PipelineModel struct{}
+
+func NewPipelineModel() *PipelineModel {
+	return &PipelineModel{}
+}
+
+// AddRun 创建流水线
+func (m *PipelineModel) AddRun(ctx context.Context, run *PipelineRun) error {
+	return db.WithContext(ctx).Create(run).Error()
+}
+
+// GetRun 根据ID获取流水线
+func (m *PipelineModel) GetRun(ctx context.Context, id int64) (*PipelineRun, error) {
+	var run PipelineRun
+	err := db.WithContext(ctx).Where("id = ?", id).First(&run).Error()
+	return &run, err
+}
+
+// GetRunByJobId 获取异步任务关联的最近一次流水线
+func (m *PipelineModel) GetRunByJobId(ctx context.Context, jobId string) (*PipelineRun, error) {
+	var run PipelineRun
+	err := db.WithContext(ctx).Where("job_id = ?", jobId).Order("id desc").First(&run).Error()
+	return &run, err
+}
+
+// ListRuns 按状态分页查询流水线，status 为空时查询全部
+func (m *PipelineModel) ListRuns(ctx context.Context, status string, page, size int) ([]PipelineRun, int64, error) {
+	var items []PipelineRun
+	var total int64
+	q := db.WithContext(ctx).Model(&PipelineRun{})
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := q.Order("id desc").Limit(size).Offset((page - 1) * size).Find(&items).Error
+	return items, total, err
+}
+
+// UpdateRun 更新流水线字段
+func (m *PipelineModel) UpdateRun(ctx context.Context, id int64, updates map[string]any) error {
+	return db.WithContext(ctx).Model(&PipelineRun{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// ListStages 获取流水线的全部阶段
+func (m *PipelineModel) ListStages(ctx context.Context, runId int64) ([]PipelineStage, error) {
+	var items []PipelineStage
+	err := db.WithContext(ctx).Where("run_id = ?", runId).Order("id asc").Find(&items).Error()
+	return items, err
+}
+
+// SaveStage 新增或更新阶段记录（run_id + stage 唯一）
+func (m *PipelineModel) SaveStage(ctx context.Context, stage *PipelineStage) error {
+	if stage.Id == 0 {
+		return db.WithContext(ctx).Create(stage).Error()
+	}
+	return db.WithContext(ctx).Model(&PipelineStage{}).Where("id = ?", stage.Id).Updates(map[string]any{
+		"status":      stage.Status,
+		"input":       stage.Input,
+		"output":      stage.Output,
+		"error_msg":   stage.ErrorMsg,
+		"attempts":    stage.Attempts,
+		"started_at":  stage.StartedAt,
+		"finished_at": stage.FinishedAt,
+		"duration_ms": stage.DurationMs,
+	}).Error
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"context"
+	"go-gin/internal/component/db"
+	"time"
+)
+
+// SegmentMatch 分句命中
+type SegmentMatch struct {
+	TranscriptId   int64   `gorm:"column:transcript_id"`
+	SegIndex       int     `gorm:"column:seg_index"`
+	StartMs        int64   `gorm:"column:start_ms"`
+	EndMs          int64   `gorm:"column:end_ms"`
+	OriginalText   string  `gorm:"column:original_text"`
+	TranslatedText string  `gorm:"column:translated_text"`
+	Score          float64 `gorm:"column:score"`
+}
+
+// TranscriptMatch 转录全文或视频标题命中
+type TranscriptMatch struct {
+	TranscriptId int64   `gorm:"column:transcript_id"`
+	Score        float64 `gorm:"column:score"`
+}
+
+// TranscriptCard 搜索结果展示所需的转录与视频信息
+type TranscriptCard struct {
+	TranscriptId   int64  `gorm:"column:transcript_id"`
+	Language       string `gorm:"column:language"`
+	OriginalText   string `gorm:"column:original_text"`
+	TranslatedText string `gorm:"column:translated_text"`
+	SourceSite     string `gorm:"column:source_site"`
+	VideoId        string `gorm:"column:video_id"`
+	Title          string `gorm:"column:title"`
+}
+
+// TTSMatch 合成历史命中
+type TTSMatch struct {
+	Id          int64     `gorm:"column:id"`
+	TextPreview string    `gorm:"column:text_preview"`
+	Speaker     string    `gorm:"column:speaker"`
+	AudioUrl    string    `gorm:"column:audio_url"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	Score       float64   `gorm:"column:score"`
+}
+
+// SearchModel 基于 ngram 全文索引的搜索，所有查询都按用户拥有的转录（ownedTranscriptsSQL）限定范围；
+// against 为 BOOLEAN MODE 的检索表达式
+type SearchModel struct{}
+
+func NewSearchModel() *SearchModel {
+	return &SearchModel{}
+}
+
+// Segments 用户转录中命中的分句，按相关度降序
+func (m *SearchModel) Segments(ctx context.Context, identity, against string, limit int) ([]SegmentMatch, error) {
+	var items []SegmentMatch
+	err := db.WithContext(ctx).Raw(`
+		SELECT s.transcript_id, s.seg_index, s.start_ms, s.end_ms, s.original_text, s.translated_text,
+			MATCH(s.original_text, s.translated_text) AGAINST (? IN BOOLEAN MODE) AS score
+		FROM youtube_transcript_segment s
+		WHERE s.transcript_id IN (`+ownedTranscriptsSQL+`)
+			AND MATCH(s.original_text, s.translated_text) AGAINST (? IN BOOLEAN MODE)
+		ORDER BY score DESC
+		LIMIT ?`, against, identity, identity, against, limit).Scan(&items).Error()
+	return items, err
+}
+
+// Transcripts 用户转录中全文命中的转录（覆盖没有分句的历史转录）
+func (m *SearchModel) Transcripts(ctx context.Context, identity, against string, limit int) ([]TranscriptMatch, error) {
+	var items []TranscriptMatch
+	err := db.WithContext(ctx).Raw(`
+		SELECT t.id AS transcript_id, MATCH(t.original_text, t.translated_text) AGAINST (? IN BOOLEAN MODE) AS score
+		FROM youtube_transcript t
+		WHERE t.id IN (`+ownedTranscriptsSQL+`)
+			AND MATCH(t.original_text, t.translated_text) AGAINST (? IN BOOLEAN MODE)
+		ORDER BY score DESC
+		LIMIT ?`, against, identity, identity, against, limit).Scan(&items).Error()
+	return items, err
+}
+
+// Titles 用户转录中视频标题命中的转录
+func (m *SearchModel) Titles(ctx context.Context, identity, against string, limit int) ([]TranscriptMatch, error) {
+	var items []TranscriptMatch
+	err := db.WithContext(ctx).Raw(`
+		SELECT t.id AS transcript_id, MATCH(v.title) AGAINST (? IN BOOLEAN MODE) AS score
+		FROM youtube_transcript t
+		JOIN youtube_video v ON v.id = t.video_id
+		WHERE t.id IN (`+ownedTranscriptsSQL+`)
+			AND MATCH(v.title) AGAINST (? IN BOOLEAN MODE)
+		ORDER BY score DESC
+		LIMIT ?`, against, identity, identity, against, limit).Scan(&items).Error()
+	return items, err
+}
+
+// Cards 批量获取转录与视频信息
+func (m *SearchModel) Cards(ctx context.Context, transcriptIds []int64) ([]TranscriptCard, error) {
+	var items []TranscriptCard
+	if len(transcriptIds) == 0 {
+		return items, nil
+	}
+	err := db.WithContext(ctx).Raw(`
+		SELECT t.id AS transcript_id, t.language, t.original_text, t.translated_text,
+			COALESCE(v.source_site, '') AS source_site, COALESCE(v.video_id, '') AS video_id, COALESCE(v.title, '') AS title
+		FROM youtube_transcript t
+		LEFT JOIN youtube_video v ON v.id = t.video_id
+		WHERE t.id IN ?`, transcriptIds).Scan(&items).Error()
+	return items, err
+}
+
+// TTS 用户合成历史中文本命中的记录
+func (m *SearchModel) TTS(ctx context.Context, identity, against string, limit int) ([]TTSMatch, error) {
+	var items []TTSMatch
+	err := db.WithContext(ctx).Raw(`
+		SELECT id, text_preview, speaker, audio_url, created_at,
+			MATCH(text_preview) AGAINST (? IN BOOLEAN MODE) AS score
+		FROM tts_history
+		WHERE user_identity = ? AND MATCH(text_preview) AGAINST (? IN BOOLEAN MODE)
+		ORDER BY score DESC
+		LIMIT ?`, against, identity, against, limit).Scan(&items).Error()
+	return items, err
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"context"
+	"go-gin/internal/component/db"
+	"time"
+)
+
+// 转录任务阶段
+const (
+	TranscriptJobStageQueued      = "queued"
+	TranscriptJobStageDownloading = "downloading"
+	TranscriptJobStageRecognizing = "recognizing"
+	TranscriptJobStageTranslating = "translating"
+	TranscriptJobStageDone        = "done"
+	TranscriptJobStageFailed      = "failed"
+)
+
+type TranscriptJob struct {
+	Id           int64     `gorm:"column:id;primaryKey" json:"id"`
+	JobId        string    `gorm:"column:job_id" json:"job_id"`
+	UserIdentity string    `gorm:"column:user_identity" json:"user_identity"`
+	IdOrUrl      string    `gorm:"column:id_or_url" json:"id_or_url"`
+	Platform     string    `gorm:"column:platform" json:"platform"`
+	TargetLang   string    `gorm:"column:target_lang" json:"target_lang"`
+	SourceLang   string    `gorm:"column:source_lang" json:"source_lang"`
+	ForceRefresh bool      `gorm:"column:force_refresh" json:"force_refresh"`
+	Stage        string    `gorm:"column:stage" json:"stage"`
+	ErrorCode    int       `gorm:"column:error_code" json:"error_code"`
+	ErrorMsg     string    `gorm:"column:error_msg" json:"error_msg"`
+	TranscriptId int64     `gorm:"column:transcript_id" json:"transcript_id"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (TranscriptJob) TableName() string { return "transcript_job" }
+
+type TranscriptJobModel struct{}
+
+func NewTranscriptJobModel() *TranscriptJobModel {
+	return &TranscriptJobModel{}
+}
+
+// Add 创建任务
+func (m *TranscriptJobModel) Add(ctx context.Context, job *TranscriptJob) error {
+	return db.WithContext(ctx).Create(job).Error()
+}
+
+// GetByJobId 根据对外任务ID获取任务
+func (m *TranscriptJobModel) GetByJobId(ctx context.Context, jobId string) (*TranscriptJob, error) {
+	var job TranscriptJob
+	err := db.WithContext(ctx).Where("job_id = ?", jobId).First(&job).Error()
+	return &job, err
+}
+
+// UpdateStage 更新任务阶段
+func (m *TranscriptJobModel) UpdateStage(ctx context.Context, jobId string, stage string) error {
+	return db.WithContext(ctx).Model(&TranscriptJob{}).Where("job_id = ?", jobId).Update("stage", stage).Error
+}
+
+// MarkDone 标记任务完成并关联转录结果
+func (m *TranscriptJobModel) MarkDone(ctx context.Context, jobId string, transcriptId int64) error {
+	return db.WithContext(ctx).Model(&TranscriptJob{}).Where("job_id = ?", jobId).Updates(map[string]any{
+		"stage":         TranscriptJobStageDone,
+		"transcript_id": transcriptId,
+		"error_code":    0,
+		"error_msg":     "",
+	}).Error
+}
+
+// MarkFailed 标记任务失败并记录错误
+func (m *TranscriptJobModel) MarkFailed(ctx context.Context, jobId string, code int, msg string) error {
+	if len([]rune(msg)) > 500 {
+		msg = string([]rune(msg)[:500])
+	}
+	return db.WithContext(ctx).Model(&TranscriptJob{}).Where("job_id = ?", jobId).Updates(map[string]any{
+		"stage":      TranscriptJobStageFailed,
+		"error_code": code,
+		"error_msg":  msg,
+	}).Error
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"context"
+	"go-gin/internal/component/db"
+	"go-gin/internal/errorx"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ownedTranscriptsSQL 用户拥有的转录：转录本身没有归属，取其流水线与异步任务产出的转录
+// （复制出编辑副本后，用户的流水线与任务改为指向副本）
+const ownedTranscriptsSQL = `SELECT transcript_id FROM pipeline_run WHERE user_identity = ? AND transcript_id > 0
+	UNION SELECT transcript_id FROM transcript_job WHERE user_identity = ? AND transcript_id > 0`
+
+type TranscriptModel struct{}
+
+func NewTranscriptModel() *TranscriptModel {
+	return &TranscriptModel{}
+}
+
+// Get 获取转录
+func (m *TranscriptModel) Get(ctx context.Context, transcriptId int64) (*YoutubeTranscript, error) {
+	var transcript YoutubeTranscript
+	err := db.WithContext(ctx).Where("id = ?", transcriptId).First(&transcript).Error()
+	return &transcript, err
+}
+
+// Owns 转录是否属于用户（由用户的流水线或异步任务产出）
+func (m *TranscriptModel) Owns(ctx context.Context, identity string, transcriptId int64) (bool, error) {
+	var total int64
+	err := db.WithContext(ctx).Raw(`SELECT COUNT(*) FROM (`+ownedTranscriptsSQL+`) o WHERE o.transcript_id = ?`,
+		identity, identity, transcriptId).Scan(&total).Error()
+	return total > 0, err
+}
+
+// ForkOf 获取用户由指定共享转录复制出的编辑副本
+func (m *TranscriptModel) ForkOf(ctx context.Context, identity string, sourceId int64) (*YoutubeTranscript, error) {
+	var transcript YoutubeTranscript
+	err := db.WithContext(ctx).Where("forked_from = ? AND owner_identity = ?", sourceId, identity).First(&transcript).Error()
+	return &transcript, err
+}
+
+// FindFork 按视频、语言与术语表版本获取用户的编辑副本
+func (m *TranscriptModel) FindFork(ctx context.Context, identity string, videoId int64, language, glossaryVersion string) (*YoutubeTranscript, error) {
+	var transcript YoutubeTranscript
+	err := db.WithContext(ctx).Where("video_id = ? AND language = ? AND glossary_version = ? AND owner_identity = ?",
+		videoId, language, glossaryVersion, identity).First(&transcript).Error()
+	return &transcript, err
+}
+
+// Fork 复制共享转录（含分句与说话人名称）作为用户的编辑副本，并把用户指向共享转录的流水线与任务改为指向副本；
+// 已有副本时直接返回
+func (m *TranscriptModel) Fork(ctx context.Context, source *YoutubeTranscript, identity string) (*YoutubeTranscript, error) {
+	var fork YoutubeTranscript
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定共享转录，同一用户并发的首次编辑只复制一次
+		var locked int64
+		if err := tx.Model(&YoutubeTranscript{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", source.Id).Select("id").Scan(&locked).Error; err != nil {
+			return err
+		}
+		err := tx.Where("forked_from = ? AND owner_identity = ?", source.Id, identity).First(&fork).Error
+		if err == nil {
+			return nil
+		}
+		if !errorx.IsRecordNotFound(err) {
+			return err
+		}
+
+		fork = *source
+		fork.Id = 0
+		fork.OwnerIdentity = identity
+		fork.ForkedFrom = source.Id
+		fork.EditedAt, fork.EditedBy = nil, ""
+		fork.CreatedAt, fork.UpdatedAt = time.Time{}, time.Time{}
+		if err := tx.Create(&fork).Error; err != nil {
+			return err
+		}
+
+		segModel := NewTranscriptSegmentModel()
+		segments, err := segModel.ListByTranscriptTx(tx, source.Id)
+		if err != nil {
+			return err
+		}
+		for i := range segments {
+			segments[i].Id = 0
+		}
+		if err := segModel.ReplaceTx(tx, fork.Id, segments); err != nil {
+			return err
+		}
+		if err := tx.Exec(`INSERT INTO youtube_transcript_speaker (transcript_id, speaker, name)
+			SELECT ?, speaker, name FROM youtube_transcript_speaker WHERE transcript_id = ?`, fork.Id, source.Id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&PipelineRun{}).Where("user_identity = ? AND transcript_id = ?", identity, source.Id).
+			Update("transcript_id", fork.Id).Error; err != nil {
+			return err
+		}
+		return tx.Model(&TranscriptJob{}).Where("user_identity = ? AND transcript_id = ?", identity, source.Id).
+			Update("transcript_id", fork.Id).Error
+	})
+	return &fork, errorx.TryToDBError(err)
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"go-gin/internal/component/db"
+	"time"
+)
+
+// QACitation 回答引用的分句，起止为原视频中的毫秒位置
+type QACitation struct {
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+	Text    string `json:"text"`
+}
+
+// YoutubeTranscriptQa 转录问答历史，按用户隔离
+type YoutubeTranscriptQa struct {
+	Id           int64     `gorm:"column:id;primaryKey" json:"id"`
+	TranscriptId int64     `gorm:"column:transcript_id" json:"transcript_id"`
+	UserIdentity string    `gorm:"column:user_identity" json:"user_identity"`
+	Question     string    `gorm:"column:question" json:"question"`
+	Answer       string    `gorm:"column:answer" json:"answer"`
+	Citations    string    `gorm:"column:citations" json:"citations"`
+	Retrieval    string    `gorm:"column:retrieval" json:"retrieval"`
+	Provider     string    `gorm:"column:provider" json:"provider"`
+	Model        string    `gorm:"column:model" json:"model"`
+	Tokens       int       `gorm:"column:tokens" json:"tokens"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (YoutubeTranscriptQa) TableName() string { return "youtube_transcript_qa" }
+
+// SetCitations 编码引用列表
+func (q *YoutubeTranscriptQa) SetCitations(citations []QACitation) {
+	q.Citations = "[]"
+	if b, err := json.Marshal(citations); err == nil && len(citations) > 0 {
+		q.Citations = string(b)
+	}
+}
+
+// CitationList 解码引用列表
+func (q *YoutubeTranscriptQa) CitationList() []QACitation {
+	var citations []QACitation
+	if err := json.Unmarshal([]byte(q.Citations), &citations); err != nil {
+		return nil
+	}
+	return citations
+}
+
+type TranscriptQaModel struct{}
+
+func NewTranscriptQaModel() *TranscriptQaModel {
+	return &TranscriptQaModel{}
+}
+
+// Create 保存一轮问答
+func (m *TranscriptQaModel) Create(ctx context.Context, item *YoutubeTranscriptQa) error {
+	return db.WithContext(ctx).Create(item).Error()
+}
+
+// Recent 用户在该转录下最近的 limit 轮问答，按时间正序
+func (m *TranscriptQaModel) Recent(ctx context.Context, transcriptId int64, identity string, limit int) ([]YoutubeTranscriptQa, error) {
+	var items []YoutubeTranscriptQa
+	err := db.WithContext(ctx).Where("transcript_id = ? AND user_identity = ?", transcriptId, identity).
+		Order("id DESC").Limit(limit).Find(&items).Error()
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+	return items, err
+}
+
+// List 用户在该转录下的问答历史，按时间倒序；beforeId 大于 0 时只返回更早的记录
+func (m *TranscriptQaModel) List(ctx context.Context, transcriptId int64, identity string, beforeId int64, limit int) ([]YoutubeTranscriptQa, error) {
+	var items []YoutubeTranscriptQa
+	q := db.WithContext(ctx).Where("transcript_id = ? AND user_identity = ?", transcriptId, identity)
+	if beforeId > 0 {
+		q = q.Where("id < ?", beforeId)
+	}
+	err := q.Order("id DESC").Limit(limit).Find(&items).Error()
+	return items, err
+}
+
+// DeleteByUser 清空用户在该转录下的问答历史
+func (m *TranscriptQaModel) DeleteByUser(ctx context.Context, transcriptId int64, identity string) (int64, error) {
+	result := db.WithContext(ctx).Where("transcript_id = ? AND user_identity = ?", transcriptId, identity).Delete(&YoutubeTranscriptQa{})
+	return result.RowsAffected, result.Error()
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"go-gin/internal/component/db"
+	"go-gin/internal/errorx"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 修订来源
+const (
+	RevisionSourceAuto    = "auto"    // 自动生成（首次编辑前的基线、被保护时的刷新结果）
+	RevisionSourceUser    = "user"    // 用户编辑
+	RevisionSourceRestore = "restore" // 恢复到历史修订
+)
+
+type YoutubeTranscriptRevision struct {
+	Id             int64     `gorm:"column:id;primaryKey" json:"id"`
+	TranscriptId   int64     `gorm:"column:transcript_id" json:"transcript_id"`
+	RevNo          int       `gorm:"column:rev_no" json:"rev_no"`
+	Source         string    `gorm:"column:source" json:"source"`
+	Author         string    `gorm:"column:author" json:"author"`
+	Note           string    `gorm:"column:note" json:"note"`
+	Applied        bool      `gorm:"column:applied" json:"applied"` // 受编辑保护的自动刷新结果仅记录不应用
+	OriginalText   string    `gorm:"column:original_text" json:"original_text"`
+	TranslatedText string    `gorm:"column:translated_text" json:"translated_text"`
+	Segments       string    `gorm:"column:segments" json:"-"` // []RevisionSegment 的 JSON
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (YoutubeTranscriptRevision) TableName() string { return "youtube_transcript_revision" }
+
+// RevisionSegment 修订中的分句快照
+type RevisionSegment struct {
+	Index          int    `json:"index"`
+	StartMs        int64  `json:"start_ms"`
+	EndMs          int64  `json:"end_ms"`
+	Speaker        string `json:"speaker,omitempty"`
+	OriginalText   string `json:"original_text"`
+	TranslatedText string `json:"translated_text"`
+}
+
+// SetSegments 编码分句快照
+func (r *YoutubeTranscriptRevision) SetSegments(segments []RevisionSegment) {
+	r.Segments = ""
+	if len(segments) == 0 {
+		return
+	}
+	if b, err := json.Marshal(segments); err == nil {
+		r.Segments = string(b)
+	}
+}
+
+// SegmentList 解码分句快照
+func (r *YoutubeTranscriptRevision) SegmentList() []RevisionSegment {
+	if r.Segments == "" {
+		return nil
+	}
+	var segments []RevisionSegment
+	if err := json.Unmarshal([]byte(r.Segments), &segments); err != nil {
+		return nil
+	}
+	return segments
+}
+
+type TranscriptRevisionModel struct{}
+
+func NewTranscriptRevisionModel() *TranscriptRevisionModel {
+	return &TranscriptRevisionModel{}
+}
+
+// Add 追加修订，修订号为当前最大值加一
+func (m *TranscriptRevisionModel) Add(ctx context.Context, rev *YoutubeTranscriptRevision) error {
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return m.AddTx(tx, rev)
+	})
+	return errorx.TryToDBError(err)
+}
+
+// AddTx 在事务中追加修订；先锁定转录行，使同一转录的修订号分配串行进行
+func (m *TranscriptRevisionModel) AddTx(tx *gorm.DB, rev *YoutubeTranscriptRevision) error {
+	var locked int64
+	if err := tx.Model(&YoutubeTranscript{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", rev.TranscriptId).Select("id").Scan(&locked).Error; err != nil {
+		return err
+	}
+	var last int
+	if err := tx.Model(&YoutubeTranscriptRevision{}).Where("transcript_id = ?", rev.TranscriptId).
+		Select("COALESCE(MAX(rev_no), 0)").Scan(&last).Error; err != nil {
+		return err
+	}
+	rev.RevNo = last + 1
+	return tx.Create(rev).Error
+}
+
+// CountTx 在事务中获取转录的修订数
+func (m *TranscriptRevisionModel) CountTx(tx *gorm.DB, transcriptId int64) (int64, error) {
+	var total int64
+	err := tx.Model(&YoutubeTranscriptRevision{}).Where("transcript_id = ?", transcriptId).Count(&total).Error
+	return total, err
+}
+
+// List 按修订号倒序获取修订（不含文本与分句快照）
+func (m *TranscriptRevisionModel) List(ctx context.Context, transcriptId int64) ([]YoutubeTranscriptRevision, error) {
+	var items []YoutubeTranscriptRevision
+	err := db.WithContext(ctx).Select("id", "transcript_id", "rev_no", "source", "author", "note", "applied", "created_at").
+		Where("transcript_id = ?", transcriptId).Order("rev_no desc").Find(&items).Error()
+	return items, err
+}
+
+// LatestApplied 获取最近一次已应用的修订号，没有修订时为 0
+func (m *TranscriptRevisionModel) LatestApplied(ctx context.Context, transcriptId int64) (int, error) {
+	var revNo int
+	err := db.WithContext(ctx).Model(&YoutubeTranscriptRevision{}).Where("transcript_id = ? AND applied = ?", transcriptId, true).
+		Select("COALESCE(MAX(rev_no), 0)").Scan(&revNo).Error
+	return revNo, err
+}
+
+// Get 获取指定修订
+func (m *TranscriptRevisionModel) Get(ctx context.Context, transcriptId int64, revNo int) (*YoutubeTranscriptRevision, error) {
+	rev, err := m.GetTx(db.WithContext(ctx).DB, transcriptId, revNo)
+	return rev, errorx.TryToDBError(err)
+}
+
+// GetTx 在事务中获取指定修订
+func (m *TranscriptRevisionModel) GetTx(tx *gorm.DB, transcriptId int64, revNo int) (*YoutubeTranscriptRevision, error) {
+	var rev YoutubeTranscriptRevision
+	err := tx.Where("transcript_id = ? AND rev_no = ?", transcriptId, revNo).First(&rev).Error
+	return &rev, err
+}
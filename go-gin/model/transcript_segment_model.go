@@ -0,0 +1,103 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"go-gin/internal/component/db"
+	"go-gin/internal/errorx"
+
+	"gorm.io/gorm"
+)
+
+type YoutubeTranscriptSegment struct {
+	Id             int64   `gorm:"column:id;primaryKey" json:"id"`
+	TranscriptId   int64   `gorm:"column:transcript_id" json:"transcript_id"`
+	SegIndex       int     `gorm:"column:seg_index" json:"index"`
+	StartMs        int64   `gorm:"column:start_ms" json:"start_ms"`
+	EndMs          int64   `gorm:"column:end_ms" json:"end_ms"`
+	OriginalText   string  `gorm:"column:original_text" json:"original_text"`
+	TranslatedText string  `gorm:"column:translated_text" json:"translated_text"`
+	Confidence     float64 `gorm:"column:confidence" json:"confidence"`
+	Speaker        string  `gorm:"column:speaker" json:"speaker"` // 说话人标识（A、B…），未分离时为空
+	Words          string  `gorm:"column:words" json:"-"`         // []SegmentWord 的 JSON
+}
+
+func (YoutubeTranscriptSegment) TableName() string { return "youtube_transcript_segment" }
+
+// SegmentWord 分句内的词级时间戳与置信度（0~100，0 表示上游未提供）
+type SegmentWord struct {
+	Text       string `json:"text"`
+	StartMs    int64  `json:"start_ms"`
+	EndMs      int64  `json:"end_ms"`
+	Confidence int    `json:"confidence"`
+}
+
+// SetWords 编码词列表，为空时清空
+func (s *YoutubeTranscriptSegment) SetWords(words []SegmentWord) {
+	s.Words = ""
+	if len(words) == 0 {
+		return
+	}
+	if b, err := json.Marshal(words); err == nil {
+		s.Words = string(b)
+	}
+}
+
+// WordList 解码词列表
+func (s *YoutubeTranscriptSegment) WordList() []SegmentWord {
+	if s.Words == "" {
+		return nil
+	}
+	var words []SegmentWord
+	if err := json.Unmarshal([]byte(s.Words), &words); err != nil {
+		return nil
+	}
+	return words
+}
+
+type TranscriptSegmentModel struct{}
+
+func NewTranscriptSegmentModel() *TranscriptSegmentModel {
+	return &TranscriptSegmentModel{}
+}
+
+// ListByTranscript 按序号获取转录的全部分句
+func (m *TranscriptSegmentModel) ListByTranscript(ctx context.Context, transcriptId int64) ([]YoutubeTranscriptSegment, error) {
+	items, err := m.ListByTranscriptTx(db.WithContext(ctx).DB, transcriptId)
+	return items, errorx.TryToDBError(err)
+}
+
+// ListByTranscriptTx 在事务中按序号获取转录的全部分句
+func (m *TranscriptSegmentModel) ListByTranscriptTx(tx *gorm.DB, transcriptId int64) ([]YoutubeTranscriptSegment, error) {
+	var items []YoutubeTranscriptSegment
+	err := tx.Where("transcript_id = ?", transcriptId).Order("seg_index asc").Find(&items).Error
+	return items, err
+}
+
+// UpdateByIndexTx 在事务中更新指定序号的分句
+func (m *TranscriptSegmentModel) UpdateByIndexTx(tx *gorm.DB, transcriptId int64, segIndex int, updates map[string]any) error {
+	return tx.Model(&YoutubeTranscriptSegment{}).
+		Where("transcript_id = ? AND seg_index = ?", transcriptId, segIndex).Updates(updates).Error
+}
+
+// Replace 用新结果整体替换转录的分句
+func (m *TranscriptSegmentModel) Replace(ctx context.Context, transcriptId int64, items []YoutubeTranscriptSegment) error {
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return m.ReplaceTx(tx, transcriptId, items)
+	})
+	return errorx.TryToDBError(err)
+}
+
+// ReplaceTx 在事务中整体替换转录的分句
+func (m *TranscriptSegmentModel) ReplaceTx(tx *gorm.DB, transcriptId int64, items []YoutubeTranscriptSegment) error {
+	for i := range items {
+		items[i].TranscriptId = transcriptId
+	}
+	if err := tx.Where("transcript_id = ?", transcriptId).Delete(&YoutubeTranscriptSegment{}).Error; err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(items, 200).Error
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"context"
+	"go-gin/internal/component/db"
+	"go-gin/internal/errorx"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type YoutubeTranscriptSpeaker struct {
+	Id           int64     `gorm:"column:id;primaryKey" json:"id"`
+	TranscriptId int64     `gorm:"column:transcript_id" json:"transcript_id"`
+	Speaker      string    `gorm:"column:speaker" json:"speaker"`
+	Name         string    `gorm:"column:name" json:"name"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (YoutubeTranscriptSpeaker) TableName() string { return "youtube_transcript_speaker" }
+
+// SpeakerDisplayName 说话人显示名：已改名时用新名称，否则为 Speaker A；未分离时为空
+func SpeakerDisplayName(speaker string, names map[string]string) string {
+	if speaker == "" {
+		return ""
+	}
+	if name := names[speaker]; name != "" {
+		return name
+	}
+	return "Speaker " + speaker
+}
+
+type TranscriptSpeakerModel struct{}
+
+func NewTranscriptSpeakerModel() *TranscriptSpeakerModel {
+	return &TranscriptSpeakerModel{}
+}
+
+// Names 获取转录中已改名的说话人，speaker -> name
+func (m *TranscriptSpeakerModel) Names(ctx context.Context, transcriptId int64) (map[string]string, error) {
+	var items []YoutubeTranscriptSpeaker
+	if err := db.WithContext(ctx).Where("transcript_id = ?", transcriptId).Find(&items).Error(); err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(items))
+	for _, v := range items {
+		names[v.Speaker] = v.Name
+	}
+	return names, nil
+}
+
+// SetNames 保存说话人显示名，名称为空时删除记录（恢复默认）
+func (m *TranscriptSpeakerModel) SetNames(ctx context.Context, transcriptId int64, names map[string]string) error {
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for speaker, name := range names {
+			if name == "" {
+				if err := tx.Where("transcript_id = ? AND speaker = ?", transcriptId, speaker).Delete(&YoutubeTranscriptSpeaker{}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			item := YoutubeTranscriptSpeaker{TranscriptId: transcriptId, Speaker: speaker, Name: name}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "transcript_id"}, {Name: "speaker"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+			}).Create(&item).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return errorx.TryToDBError(err)
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"go-gin/internal/component/db"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// SummaryChapter 章节，起止为原视频中的毫秒位置
+type SummaryChapter struct {
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+	Title   string `json:"title"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// YoutubeTranscriptSummary 转录摘要缓存，按 (transcript_id, language) 唯一
+type YoutubeTranscriptSummary struct {
+	Id           int64     `gorm:"column:id;primaryKey" json:"id"`
+	TranscriptId int64     `gorm:"column:transcript_id" json:"transcript_id"`
+	Language     string    `gorm:"column:language" json:"language"`
+	SourceHash   string    `gorm:"column:source_hash" json:"source_hash"`
+	Summary      string    `gorm:"column:summary" json:"summary"`
+	KeyPoints    string    `gorm:"column:key_points" json:"key_points"`
+	Chapters     string    `gorm:"column:chapters" json:"chapters"`
+	Provider     string    `gorm:"column:provider" json:"provider"`
+	Model        string    `gorm:"column:model" json:"model"`
+	Tokens       int       `gorm:"column:tokens" json:"tokens"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (YoutubeTranscriptSummary) TableName() string { return "youtube_transcript_summary" }
+
+// SetKeyPoints 编码要点列表
+func (s *YoutubeTranscriptSummary) SetKeyPoints(points []string) {
+	s.KeyPoints = "[]"
+	if b, err := json.Marshal(points); err == nil && len(points) > 0 {
+		s.KeyPoints = string(b)
+	}
+}
+
+// KeyPointList 解码要点列表
+func (s *YoutubeTranscriptSummary) KeyPointList() []string {
+	var points []string
+	if err := json.Unmarshal([]byte(s.KeyPoints), &points); err != nil {
+		return nil
+	}
+	return points
+}
+
+// SetChapters 编码章节列表
+func (s *YoutubeTranscriptSummary) SetChapters(chapters []SummaryChapter) {
+	s.Chapters = "[]"
+	if b, err := json.Marshal(chapters); err == nil && len(chapters) > 0 {
+		s.Chapters = string(b)
+	}
+}
+
+// ChapterList 解码章节列表
+func (s *YoutubeTranscriptSummary) ChapterList() []SummaryChapter {
+	var chapters []SummaryChapter
+	if err := json.Unmarshal([]byte(s.Chapters), &chapters); err != nil {
+		return nil
+	}
+	return chapters
+}
+
+type TranscriptSummaryModel struct{}
+
+func NewTranscriptSummaryModel() *TranscriptSummaryModel {
+	return &TranscriptSummaryModel{}
+}
+
+// Get 获取转录指定语言的摘要
+func (m *TranscriptSummaryModel) Get(ctx context.Context, transcriptId int64, language string) (*YoutubeTranscriptSummary, error) {
+	var item YoutubeTranscriptSummary
+	err := db.WithContext(ctx).Where("transcript_id = ? AND language = ?", transcriptId, language).First(&item).Error()
+	return &item, err
+}
+
+// Save 按 (transcript_id, language) 新增或覆盖摘要
+func (m *TranscriptSummaryModel) Save(ctx context.Context, item *YoutubeTranscriptSummary) error {
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "transcript_id"}, {Name: "language"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"source_hash", "summary", "key_points", "chapters", "provider", "model", "tokens", "updated_at",
+		}),
+	}).Create(item).Error()
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"context"
+	"go-gin/internal/component/db"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TranslationMemory 分句级翻译记忆，按 hash 唯一
+type TranslationMemory struct {
+	Id              int64     `gorm:"column:id;primaryKey" json:"id"`
+	Hash            string    `gorm:"column:hash" json:"hash"`
+	SourceLang      string    `gorm:"column:source_lang" json:"source_lang"`
+	TargetLang      string    `gorm:"column:target_lang" json:"target_lang"`
+	Provider        string    `gorm:"column:provider" json:"provider"`
+	GlossaryVersion string    `gorm:"column:glossary_version" json:"glossary_version"`
+	SourceText      string    `gorm:"column:source_text" json:"source_text"`
+	TargetText      string    `gorm:"column:target_text" json:"target_text"`
+	Hits            int       `gorm:"column:hits" json:"hits"`
+	CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (TranslationMemory) TableName() string { return "translation_memory" }
+
+type TranslationMemoryModel struct{}
+
+func NewTranslationMemoryModel() *TranslationMemoryModel {
+	return &TranslationMemoryModel{}
+}
+
+// FindByHashes 批量按 hash 查找翻译记忆
+func (m *TranslationMemoryModel) FindByHashes(ctx context.Context, hashes []string) ([]TranslationMemory, error) {
+	var items []TranslationMemory
+	if len(hashes) == 0 {
+		return items, nil
+	}
+	err := db.WithContext(ctx).Where("hash IN ?", hashes).Find(&items).Error()
+	return items, err
+}
+
+// Save 批量写入翻译记忆，hash 已存在时覆盖译文
+func (m *TranslationMemoryModel) Save(ctx context.Context, items []TranslationMemory) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"target_text", "updated_at"}),
+	}).Create(&items).Error()
+}
+
+// IncrHits 累加命中次数
+func (m *TranslationMemoryModel) IncrHits(ctx context.Context, hashes []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	return db.WithContext(ctx).Model(&TranslationMemory{}).Where("hash IN ?", hashes).
+		UpdateColumn("hits", gorm.Expr("hits + 1")).Error
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"context"
+	"go-gin/internal/component/db"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 长文本合成任务状态
+const (
+	TTSJobStatusQueued  = "queued"
+	TTSJobStatusRunning = "running"
+	TTSJobStatusDone    = "done"
+	TTSJobStatusFailed  = "failed"
+)
+
+type TTSJob struct {
+	Id           int64     `gorm:"column:id;primaryKey" json:"id"`
+	JobId        string    `gorm:"column:job_id" json:"job_id"`
+	UserIdentity string    `gorm:"column:user_identity" json:"user_identity"`
+	Text         string    `gorm:"column:text" json:"-"`
+	Speaker      string    `gorm:"column:speaker" json:"speaker"`
+	UseMyVoice   bool      `gorm:"column:use_my_voice" json:"use_my_voice"`
+	Prosody      string    `gorm:"column:prosody" json:"prosody"` // 韵律参数 JSON，为空时使用音色默认
+	CharCount    int       `gorm:"column:char_count" json:"char_count"`
+	Status       string    `gorm:"column:status" json:"status"`
+	TotalChunks  int       `gorm:"column:total_chunks" json:"total_chunks"`
+	DoneChunks   int       `gorm:"column:done_chunks" json:"done_chunks"`
+	HistoryId    int64     `gorm:"column:history_id" json:"history_id"`
+	ErrorCode    int       `gorm:"column:error_code" json:"error_code"`
+	ErrorMsg     string    `gorm:"column:error_msg" json:"error_msg"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (TTSJob) TableName() string { return "tts_job" }
+
+type TTSJobModel struct{}
+
+func NewTTSJobModel() *TTSJobModel {
+	return &TTSJobModel{}
+}
+
+// Add 创建任务
+func (m *TTSJobModel) Add(ctx context.Context, job *TTSJob) error {
+	return db.WithContext(ctx).Create(job).Error()
+}
+
+// GetByJobId 根据对外任务ID获取任务
+func (m *TTSJobModel) GetByJobId(ctx context.Context, jobId string) (*TTSJob, error) {
+	var job TTSJob
+	err := db.WithContext(ctx).Where("job_id = ?", jobId).First(&job).Error()
+	return &job, err
+}
+
+// MarkRunning 标记任务开始执行并记录片段数，doneChunks 为命中缓存无需合成的片段数
+func (m *TTSJobModel) MarkRunning(ctx context.Context, jobId string, totalChunks, doneChunks int) error {
+	return db.WithContext(ctx).Model(&TTSJob{}).Where("job_id = ?", jobId).Updates(map[string]any{
+		"status":       TTSJobStatusRunning,
+		"total_chunks": totalChunks,
+		"done_chunks":  doneChunks,
+	}).Error
+}
+
+// UpdateProgress 更新已合成的片段数
+func (m *TTSJobModel) UpdateProgress(ctx context.Context, jobId string, doneChunks int) error {
+	return db.WithContext(ctx).Model(&TTSJob{}).Where("job_id = ?", jobId).Update("done_chunks", doneChunks).Error
+}
+
+// MarkDone 标记任务完成并关联合成历史
+func (m *TTSJobModel) MarkDone(ctx context.Context, jobId string, historyId int64) error {
+	return db.WithContext(ctx).Model(&TTSJob{}).Where("job_id = ?", jobId).Updates(map[string]any{
+		"status":      TTSJobStatusDone,
+		"done_chunks": gorm.Expr("total_chunks"),
+		"history_id":  historyId,
+		"error_code":  0,
+		"error_msg":   "",
+	}).Error
+}
+
+// MarkFailed 标记任务失败并记录错误
+func (m *TTSJobModel) MarkFailed(ctx context.Context, jobId string, code int, msg string) error {
+	if len([]rune(msg)) > 500 {
+		msg = string([]rune(msg)[:500])
+	}
+	return db.WithContext(ctx).Model(&TTSJob{}).Where("job_id = ?", jobId).Updates(map[string]any{
+		"status":     TTSJobStatusFailed,
+		"error_code": code,
+		"error_msg":  msg,
+	}).Error
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"context"
+	"go-gin/internal/component/db"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TTSSentenceCache 分句音频缓存，按 hash 唯一
+type TTSSentenceCache struct {
+	Id         int64     `gorm:"column:id;primaryKey" json:"id"`
+	Hash       string    `gorm:"column:hash" json:"hash"`
+	Speaker    string    `gorm:"column:speaker" json:"speaker"`
+	ResourceId string    `gorm:"column:resource_id" json:"resource_id"`
+	Text       string    `gorm:"column:text" json:"text"`
+	Audio      []byte    `gorm:"column:audio" json:"-"`
+	DurationMs int64     `gorm:"column:duration_ms" json:"duration_ms"`
+	Timings    string    `gorm:"column:timings" json:"-"` // 分句内的逐词时间戳 JSON
+	Hits       int       `gorm:"column:hits" json:"hits"`
+	LastUsedAt time.Time `gorm:"column:last_used_at" json:"last_used_at"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (TTSSentenceCache) TableName() string { return "tts_sentence_cache" }
+
+type TTSSentenceCacheModel struct{}
+
+func NewTTSSentenceCacheModel() *TTSSentenceCacheModel {
+	return &TTSSentenceCacheModel{}
+}
+
+// FindByHashes 批量查找缓存的分句音频
+func (m *TTSSentenceCacheModel) FindByHashes(ctx context.Context, hashes []string) ([]TTSSentenceCache, error) {
+	var items []TTSSentenceCache
+	if len(hashes) == 0 {
+		return items, nil
+	}
+	err := db.WithContext(ctx).Where("hash IN ?", hashes).Find(&items).Error()
+	return items, err
+}
+
+// Save 新增或覆盖分句音频
+func (m *TTSSentenceCacheModel) Save(ctx context.Context, item *TTSSentenceCache) error {
+	item.LastUsedAt = time.Now()
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"audio", "duration_ms", "timings", "last_used_at"}),
+	}).Create(item).Error()
+}
+
+// Touch 记录命中：命中次数加一并刷新最近使用时间
+func (m *TTSSentenceCacheModel) Touch(ctx context.Context, hashes []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	return db.WithContext(ctx).Model(&TTSSentenceCache{}).Where("hash IN ?", hashes).
+		UpdateColumns(map[string]any{"hits": gorm.Expr("hits + 1"), "last_used_at": time.Now()}).Error
+}
+
+// PurgeUnused 删除 before 之前未使用的缓存，每次最多 limit 条
+func (m *TTSSentenceCacheModel) PurgeUnused(ctx context.Context, before time.Time, limit int) (int64, error) {
+	result := db.WithContext(ctx).Exec("DELETE FROM tts_sentence_cache WHERE last_used_at < ? LIMIT ?", before, limit)
+	return result.RowsAffected, result.Error()
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type YoutubeVideo struct {
 	Id           int64   `gorm:"column:id;primaryKey" json:"id"`
 	SourceSite   string  `gorm:"column:source_site" json:"source_site"`
@@ -17,13 +19,32 @@ type YoutubeVideo struct {
 func (YoutubeVideo) TableName() string { return "youtube_video" }
 
 type YoutubeTranscript struct {
-	Id                 int64  `gorm:"column:id;primaryKey" json:"id"`
-	VideoId            int64  `gorm:"column:video_id" json:"video_id"`
-	Language           string `gorm:"column:language" json:"language"`
-	OriginalText       string `gorm:"column:original_text" json:"original_text"`
-	TranslatedText     string `gorm:"column:translated_text" json:"translated_text"`
-	AsrCharCount       int    `gorm:"column:asr_char_count" json:"asr_char_count"`
-	TranslateCharCount int    `gorm:"column:translate_char_count" json:"translate_char_count"`
+	Id                 int64      `gorm:"column:id;primaryKey" json:"id"`
+	VideoId            int64      `gorm:"column:video_id" json:"video_id"`
+	Language           string     `gorm:"column:language" json:"language"`
+	GlossaryVersion    string     `gorm:"column:glossary_version" json:"glossary_version"` // 翻译所用术语表版本，空为共享缓存
+	OwnerIdentity      string     `gorm:"column:owner_identity" json:"-"`                  // 用户编辑副本的所有者，空为共享转录
+	ForkedFrom         int64      `gorm:"column:forked_from" json:"forked_from"`           // 编辑副本复制自的共享转录
+	DetectedLang       string     `gorm:"column:detected_lang" json:"detected_lang"`       // ASR 识别出的源语言
+	TextSource         string     `gorm:"column:text_source" json:"text_source"`           // 原文来源：asr | manual_caption | auto_caption
+	OriginalText       string     `gorm:"column:original_text" json:"original_text"`
+	TranslatedText     string     `gorm:"column:translated_text" json:"translated_text"`
+	AsrCharCount       int        `gorm:"column:asr_char_count" json:"asr_char_count"`
+	TranslateCharCount int        `gorm:"column:translate_char_count" json:"translate_char_count"`
+	EditedAt           *time.Time `gorm:"column:edited_at" json:"edited_at"` // 仅编辑副本会被编辑
+	EditedBy           string     `gorm:"column:edited_by" json:"edited_by"`
+	CreatedAt          time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 }
 
+// Edited 是否被用户编辑过
+func (t *YoutubeTranscript) Edited() bool { return t.EditedAt != nil }
+
 func (YoutubeTranscript) TableName() string { return "youtube_transcript" }
+
+// 转录原文来源
+const (
+	TranscriptSourceASR           = "asr"
+	TranscriptSourceManualCaption = "manual_caption"
+	TranscriptSourceAutoCaption   = "auto_caption"
+)
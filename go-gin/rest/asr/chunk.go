@@ -0,0 +1,362 @@
+package asr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go-gin/const/errcode"
+	"go-gin/internal/audiochunk"
+	"go-gin/internal/component/redisx"
+)
+
+const (
+	chunkSilenceNoise = "-35dB"
+	chunkSilenceMin   = 500 * time.Millisecond
+	chunkCacheTTL     = 7 * 24 * time.Hour
+	chunkCachePrefix  = "asr:chunk:"
+)
+
+// recognizeChunked 长音频切片识别；ok 为 false 表示无需（或无法）切片，由调用方走整段识别
+func (s *ASRSvc) recognizeChunked(ctx context.Context, chain Provider, audioUrl string, language string) (*ASRResp, bool, error) {
+	opt := pkgOptions
+	if opt.ChunkWindow <= 0 || !audiochunk.Available() {
+		return nil, false, nil
+	}
+	input := audioUrl
+	if isLocalStatic(audioUrl) {
+		input = mapStaticToLocal(audioUrl)
+	}
+	durationMs, err := audiochunk.Probe(ctx, input)
+	if err != nil {
+		log.Printf("[ASR] 获取音频时长失败，按整段识别 - input: %s, error: %v", input, err)
+		return nil, false, nil
+	}
+	if durationMs <= opt.ChunkWindow.Milliseconds() {
+		return nil, false, nil
+	}
+
+	silences, err := audiochunk.DetectSilences(ctx, input, chunkSilenceNoise, chunkSilenceMin)
+	if err != nil {
+		// 静音检测失败时退化为固定窗口加重叠切分
+		log.Printf("[ASR] 静音检测失败，按固定窗口切分 - error: %v", err)
+	}
+	chunks := audiochunk.Plan(durationMs, silences, audiochunk.Options{
+		WindowMs:  opt.ChunkWindow.Milliseconds(),
+		OverlapMs: opt.ChunkOverlap.Milliseconds(),
+	})
+	log.Printf("[ASR] 长音频切片识别 - duration: %dms, silences: %d, chunks: %d, concurrency: %d",
+		durationMs, len(silences), len(chunks), opt.ChunkConcurrency)
+
+	dir, err := os.MkdirTemp("", "asr_chunks_")
+	if err != nil {
+		log.Printf("[ASR] 创建切片目录失败 - error: %v", err)
+		return nil, true, errcode.ErrASRUpstream
+	}
+	defer os.RemoveAll(dir)
+
+	results := make([]*ASRResp, len(chunks))
+	cctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	sem := make(chan struct{}, max(opt.ChunkConcurrency, 1))
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i, c := range chunks {
+		wg.Add(1)
+		go func(i int, c audiochunk.Chunk) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-cctx.Done():
+				return
+			}
+			resp, err := s.recognizeChunk(cctx, chain, input, dir, c, language)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = resp
+		}(i, c)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, true, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, true, err
+	}
+
+	resp := MergeChunks(chunks, results, normalizeLang(language))
+	if resp.Text == "" {
+		log.Printf("[ASR] 切片识别结果为空 - chunks: %d", len(chunks))
+		return nil, true, errcode.ErrASRUpstream
+	}
+	log.Printf("[ASR] 切片识别完成 - chunks: %d, CharCount: %d, Segments: %d, Language: %s",
+		len(chunks), resp.CharCount, len(resp.Segments), resp.Language)
+	return resp, true, nil
+}
+
+// recognizeChunk 识别单个切片；结果按切片内容与语言缓存，重试时已完成的切片直接复用
+func (s *ASRSvc) recognizeChunk(ctx context.Context, chain Provider, input, dir string, c audiochunk.Chunk, language string) (*ASRResp, error) {
+	path, err := audiochunk.Extract(ctx, input, c, dir)
+	if err != nil {
+		log.Printf("[ASR] 切片转码失败 - chunk: %d, error: %v", c.Index, err)
+		return nil, errcode.ErrASRUpstream
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("[ASR] 读取切片失败 - chunk: %d, error: %v", c.Index, err)
+		return nil, errcode.ErrASRUpstream
+	}
+
+	key := chunkCacheKey(data, normalizeLang(language))
+	if resp, ok := loadChunkCache(ctx, key); ok {
+		log.Printf("[ASR] 切片命中缓存 - chunk: %d, range: %d-%dms", c.Index, c.StartMs, c.EndMs)
+		return resp, nil
+	}
+
+	log.Printf("[ASR] 识别切片 - chunk: %d, range: %d-%dms, size: %d bytes", c.Index, c.StartMs, c.EndMs, len(data))
+	resp, err := chain.Recognize(ctx, Audio{Data: data, Name: filepath.Base(path)}, language)
+	if err != nil {
+		log.Printf("[ASR] 切片识别失败 - chunk: %d, error: %v", c.Index, err)
+		return nil, errcode.ErrASRUpstream
+	}
+	saveChunkCache(ctx, key, resp)
+	return resp, nil
+}
+
+func chunkCacheKey(data []byte, lang string) string {
+	sum := sha256.Sum256(data)
+	return chunkCachePrefix + hex.EncodeToString(sum[:]) + ":" + lang
+}
+
+func loadChunkCache(ctx context.Context, key string) (*ASRResp, bool) {
+	raw, err := redisx.Client().Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	var resp ASRResp
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, false
+	}
+	return &resp, true
+}
+
+func saveChunkCache(ctx context.Context, key string, resp *ASRResp) {
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	if err := redisx.Client().Set(ctx, key, raw, chunkCacheTTL).Err(); err != nil {
+		log.Printf("[ASR] 写入切片缓存失败 - key: %s, error: %v", key, err)
+	}
+}
+
+// MergeChunks 将切片结果的时间戳平移回原音频，并按切片 Keep 区间去除重叠部分的重复分句。
+// 各切片独立做说话人分离，相邻切片有重叠时按重叠区间内的时间重合对齐说话人，
+// 对不上的说话人使用新的标识（宁可把同一人拆成两个标识，也不把不同的人合并）；
+// 没有分句的切片整段文本归属本切片，相邻切片的重叠部分让给它，两段都没有分句时按文本去重
+func MergeChunks(chunks []audiochunk.Chunk, results []*ASRResp, requested string) *ASRResp {
+	merged := &ASRResp{Language: requested}
+	langChars := map[string]int{}
+	var texts []string
+	// prevSegs 上一个有分句的切片的全部分句（含 Keep 区间外的），说话人为全局标识
+	var prevSegs []Segment
+	prev := -1
+	for i, c := range chunks {
+		r := results[i]
+		if r == nil {
+			continue
+		}
+		overlapped := prev >= 0 && c.StartMs < chunks[prev].EndMs
+		if len(r.Segments) == 0 {
+			t := strings.TrimSpace(r.Text)
+			if overlapped && textOnly(results[prev]) && len(texts) > 0 {
+				t = trimOverlapText(texts[len(texts)-1], t)
+			}
+			if t != "" {
+				texts = append(texts, t)
+				langChars[r.Language] += len([]rune(t))
+			}
+			prevSegs, prev = nil, i
+			continue
+		}
+
+		keepFrom, keepTo := c.KeepFrom, c.KeepTo
+		if overlapped && textOnly(results[prev]) {
+			keepFrom = max(keepFrom, chunks[prev].EndMs)
+		}
+		if i+1 < len(chunks) && textOnly(results[i+1]) {
+			keepTo = min(keepTo, chunks[i+1].StartMs)
+		}
+
+		segs := make([]Segment, len(r.Segments))
+		for j, seg := range r.Segments {
+			seg.StartMs += c.StartMs
+			seg.EndMs += c.StartMs
+			words := make([]Word, len(seg.Words))
+			for k, w := range seg.Words {
+				w.StartMs += c.StartMs
+				w.EndMs += c.StartMs
+				words[k] = w
+			}
+			seg.Words = words
+			segs[j] = seg
+		}
+		var aligned map[string]string
+		if overlapped && len(prevSegs) > 0 {
+			aligned = alignSpeakers(prevSegs, segs, c.StartMs, chunks[prev].EndMs)
+		}
+		for j := range segs {
+			if segs[j].Speaker == "" {
+				continue
+			}
+			if g, ok := aligned[segs[j].Speaker]; ok {
+				segs[j].Speaker = g
+			} else {
+				// 切片序号 + 切片内标识作为全局标识，合并后再统一为 A、B…
+				segs[j].Speaker = fmt.Sprintf("%d/%s", i, segs[j].Speaker)
+			}
+		}
+		prevSegs, prev = segs, i
+
+		for _, seg := range segs {
+			if seg.StartMs < keepFrom || seg.StartMs >= keepTo {
+				continue
+			}
+			merged.Segments = append(merged.Segments, seg)
+			texts = append(texts, seg.Text)
+			langChars[r.Language] += len([]rune(seg.Text))
+		}
+	}
+	labelSpeakers(merged.Segments)
+
+	if merged.Language == "" || merged.Language == LangAuto {
+		best := 0
+		for lang, n := range langChars {
+			if lang != "" && n > best {
+				merged.Language, best = lang, n
+			}
+		}
+	}
+	sep := " "
+	if merged.Language == "zh" || merged.Language == "ja" {
+		sep = ""
+	}
+	merged.Text = strings.TrimSpace(strings.Join(texts, sep))
+	merged.CharCount = len([]rune(merged.Text))
+	if merged.Language == "" || merged.Language == LangAuto {
+		merged.Language = detectLanguage(merged.Text)
+	}
+	return merged
+}
+
+// textOnly 切片结果只有整段文本、没有分句
+func textOnly(r *ASRResp) bool {
+	return r != nil && len(r.Segments) == 0 && strings.TrimSpace(r.Text) != ""
+}
+
+// alignSpeakers 按重叠区间 [from, to) 内的时间重合，将当前切片的说话人对应到上一切片的全局标识；
+// 重合时长最大的一对优先，一一对应，对不上的说话人不出现在返回值中
+func alignSpeakers(prev, cur []Segment, from, to int64) map[string]string {
+	type pair struct {
+		cur, prev string
+		ms        int64
+	}
+	overlap := map[[2]string]int64{}
+	for _, c := range cur {
+		if c.Speaker == "" {
+			continue
+		}
+		for _, p := range prev {
+			if p.Speaker == "" {
+				continue
+			}
+			start := max(c.StartMs, p.StartMs, from)
+			end := min(c.EndMs, p.EndMs, to)
+			if end > start {
+				overlap[[2]string{c.Speaker, p.Speaker}] += end - start
+			}
+		}
+	}
+	pairs := make([]pair, 0, len(overlap))
+	for k, ms := range overlap {
+		pairs = append(pairs, pair{cur: k[0], prev: k[1], ms: ms})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].ms != pairs[j].ms {
+			return pairs[i].ms > pairs[j].ms
+		}
+		if pairs[i].cur != pairs[j].cur {
+			return pairs[i].cur < pairs[j].cur
+		}
+		return pairs[i].prev < pairs[j].prev
+	})
+	aligned := map[string]string{}
+	used := map[string]bool{}
+	for _, p := range pairs {
+		if _, ok := aligned[p.cur]; ok || used[p.prev] {
+			continue
+		}
+		aligned[p.cur] = p.prev
+		used[p.prev] = true
+	}
+	return aligned
+}
+
+const (
+	// overlapMinRunes 重复部分少于该字符数时视为巧合，不做去重
+	overlapMinRunes = 8
+	// overlapMaxRunes 只在上一段结尾、本段开头的这些字符内查找重复
+	overlapMaxRunes = 600
+)
+
+// trimOverlapText 去掉 cur 开头与 prev 结尾重复的部分；比较时忽略大小写、空白与标点
+func trimOverlapText(prev, cur string) string {
+	var tail []rune
+	for _, r := range prev {
+		if isWordRune(r) {
+			tail = append(tail, unicode.ToLower(r))
+		}
+	}
+	if len(tail) > overlapMaxRunes {
+		tail = tail[len(tail)-overlapMaxRunes:]
+	}
+	var head []rune
+	var ends []int // head[k] 在 cur 中的结束字节偏移
+	for i, r := range cur {
+		if len(head) >= overlapMaxRunes {
+			break
+		}
+		if isWordRune(r) {
+			head = append(head, unicode.ToLower(r))
+			ends = append(ends, i+utf8.RuneLen(r))
+		}
+	}
+	for k := min(len(tail), len(head)); k >= overlapMinRunes; k-- {
+		if string(tail[len(tail)-k:]) == string(head[:k]) {
+			return strings.TrimLeftFunc(cur[ends[k-1]:], func(r rune) bool { return !isWordRune(r) })
+		}
+	}
+	return cur
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -1,9 +1,10 @@
 package asr
 
 type VolcCreds struct {
-	AppId         string
-	AccessKey     string
-	ASRResourceId string
+	AppId               string
+	AccessKey           string
+	ASRResourceId       string
+	ASRSubmitResourceId string // 标准版（提交/查询）资源 ID，为空时使用 volc.bigasr.auc
 }
 
 var volcCreds VolcCreds
@@ -4,7 +4,9 @@ var (
 	Svc IASRSvc = (*ASRSvc)(nil)
 )
 
+// Init 注册火山提供方（极速版与标准版共用同一服务地址）
 func Init(url string) {
-	Svc = NewASRSvc(url)
+	Register(NewVolcFlashProvider(url))
+	Register(NewVolcSubmitProvider(url))
+	Svc = NewASRSvc()
 }
-
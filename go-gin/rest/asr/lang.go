@@ -0,0 +1,137 @@
+package asr
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// LangAuto 表示不指定识别语言，由上游自动识别
+const LangAuto = "auto"
+
+// Options ASR 运行选项，由上层（config.InitSvc）在启动时注入
+type Options struct {
+	// 默认识别语言：auto 或语言代码（en、zh、ja…），请求未指定 source_lang 时使用
+	Language string
+	// 识别提供方，按优先级逗号分隔，前一个失败时转移到下一个，如 "volc_flash,whisper"
+	Provider string
+	// 长音频切片：超过 ChunkWindow 的音频按该时长切片并发识别，负值表示关闭切片
+	ChunkWindow time.Duration
+	// 固定窗口切分（找不到静音）时相邻切片的重叠时长
+	ChunkOverlap time.Duration
+	// 切片识别并发数
+	ChunkConcurrency int
+}
+
+var pkgOptions = Options{
+	Language:         LangAuto,
+	Provider:         ProviderVolcFlash,
+	ChunkWindow:      10 * time.Minute,
+	ChunkOverlap:     5 * time.Second,
+	ChunkConcurrency: 3,
+}
+
+// SetOptions 仅当有值时覆盖默认值
+func SetOptions(opt Options) {
+	if opt.Language != "" {
+		pkgOptions.Language = opt.Language
+	}
+	if opt.Provider != "" {
+		pkgOptions.Provider = opt.Provider
+	}
+	if opt.ChunkWindow != 0 {
+		pkgOptions.ChunkWindow = opt.ChunkWindow
+	}
+	if opt.ChunkOverlap > 0 {
+		pkgOptions.ChunkOverlap = opt.ChunkOverlap
+	}
+	if opt.ChunkConcurrency > 0 {
+		pkgOptions.ChunkConcurrency = opt.ChunkConcurrency
+	}
+}
+
+// locales 语言代码到火山识别 language 参数的映射
+var locales = map[string]string{
+	"zh": "zh-CN",
+	"en": "en-US",
+	"ja": "ja-JP",
+	"ko": "ko-KR",
+	"es": "es-MX",
+	"fr": "fr-FR",
+	"de": "de-DE",
+	"ru": "ru-RU",
+	"pt": "pt-BR",
+	"it": "it-IT",
+	"ar": "ar-SA",
+	"id": "id-ID",
+	"vi": "vi-VN",
+	"th": "th-TH",
+}
+
+// normalizeLang en-US -> en；空值使用默认语言
+func normalizeLang(lang string) string {
+	c := strings.ToLower(strings.TrimSpace(lang))
+	if c == "" {
+		c = strings.ToLower(pkgOptions.Language)
+	}
+	if c == "" || c == LangAuto {
+		return LangAuto
+	}
+	if i := strings.IndexAny(c, "-_"); i > 0 {
+		c = c[:i]
+	}
+	return c
+}
+
+// IsSupportedLang 判断是否为可指定的识别语言（auto 也视为支持）
+func IsSupportedLang(lang string) bool {
+	c := normalizeLang(lang)
+	if c == LangAuto {
+		return true
+	}
+	_, ok := locales[c]
+	return ok
+}
+
+// resolveLocale 返回请求体中的 language 参数，自动识别时返回空串
+func resolveLocale(lang string) string {
+	return locales[normalizeLang(lang)]
+}
+
+// detectLanguage 根据识别文本的文字系统粗略判断语言；拉丁字母无法区分英、西、法、德等语言，
+// 以拉丁字母为主时返回空串（未知），由翻译按未知源语言处理，不猜测为英文
+func detectLanguage(text string) string {
+	counts := map[string]int{}
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			counts["ja"]++
+		case unicode.Is(unicode.Hangul, r):
+			counts["ko"]++
+		case unicode.Is(unicode.Han, r):
+			counts["zh"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts["ru"]++
+		case unicode.Is(unicode.Arabic, r):
+			counts["ar"]++
+		case unicode.Is(unicode.Thai, r):
+			counts["th"]++
+		case unicode.Is(unicode.Latin, r):
+			counts["latin"]++
+		}
+	}
+	// 日文混用汉字，出现假名即判为日文
+	if counts["ja"] > 0 && counts["ja"]*5 >= counts["zh"] {
+		return "ja"
+	}
+	best, max := "", 0
+	for _, code := range []string{"zh", "ko", "ru", "ar", "th", "latin"} {
+		if counts[code] > max {
+			best, max = code, counts[code]
+		}
+	}
+	if best == "latin" {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,146 @@
+package asr
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+)
+
+// 内置识别提供方名称，可在 yaml: svc.asr_provider 中按优先级组合，如 "volc_flash,whisper"
+const (
+	ProviderVolcFlash  = "volc_flash"  // 火山极速版，单次请求返回结果
+	ProviderVolcSubmit = "volc_submit" // 火山标准版，提交任务后轮询，适合长文件（仅支持 URL）
+	ProviderWhisper    = "whisper"     // OpenAI 兼容 /v1/audio/transcriptions，可指向自建 whisper 服务
+)
+
+// ErrProviderUnavailable 提供方未配置或不支持该音频输入，故障转移时直接跳过
+var ErrProviderUnavailable = errors.New("asr: provider unavailable")
+
+// Audio 待识别音频：URL 与 Data 二选一，Name 用于需要上传文件的提供方推断格式
+type Audio struct {
+	URL  string
+	Data []byte
+	Name string
+}
+
+// Provider 识别提供方；返回的结果文本可能为空（静音），由调用方决定是否视为失败
+type Provider interface {
+	Name() string
+	Recognize(ctx context.Context, audio Audio, language string) (*ASRResp, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]Provider{}
+)
+
+// Register 注册（或替换）同名提供方
+func Register(p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[p.Name()] = p
+}
+
+// Lookup 按名称获取已注册的提供方
+func Lookup(name string) (Provider, bool) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	p, ok := providers[strings.TrimSpace(name)]
+	return p, ok
+}
+
+// providerChain 按 Options.Provider 配置的顺序组装提供方，未注册的名称会被忽略
+func providerChain() Provider {
+	var chain []Provider
+	for _, name := range strings.Split(pkgOptions.Provider, ",") {
+		if p, ok := Lookup(name); ok {
+			chain = append(chain, p)
+		} else if strings.TrimSpace(name) != "" {
+			log.Printf("[ASR] 未注册的识别提供方，已忽略 - provider: %s", name)
+		}
+	}
+	return NewFailover(chain...)
+}
+
+type failover struct {
+	providers []Provider
+}
+
+// NewFailover 依次尝试各提供方，前一个失败时转移到下一个；上下文取消时立即返回
+func NewFailover(providers ...Provider) Provider {
+	return &failover{providers: providers}
+}
+
+func (f *failover) Name() string {
+	names := make([]string, 0, len(f.providers))
+	for _, p := range f.providers {
+		names = append(names, p.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (f *failover) Recognize(ctx context.Context, audio Audio, language string) (*ASRResp, error) {
+	lastErr := ErrProviderUnavailable
+	for i, p := range f.providers {
+		resp, err := p.Recognize(ctx, audio, language)
+		if err == nil {
+			if i > 0 {
+				log.Printf("[ASR] 故障转移成功 - provider: %s", p.Name())
+			}
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !errors.Is(err, ErrProviderUnavailable) {
+			lastErr = err
+		}
+		log.Printf("[ASR] 识别提供方失败 - provider: %s, error: %v", p.Name(), err)
+	}
+	return nil, lastErr
+}
+
+// finishResp 补全字符数、语言与说话人标识：指定语言时沿用指定值，否则依次使用上游识别结果与文字系统推断
+func finishResp(resp *ASRResp, requested, upstreamLang string) *ASRResp {
+	resp.Text = strings.TrimSpace(resp.Text)
+	resp.CharCount = len([]rune(resp.Text)) // 使用rune计算字符数（支持中文）
+	labelSpeakers(resp.Segments)
+	resp.Language = requested
+	if resp.Language == LangAuto {
+		if upstreamLang != "" {
+			resp.Language = normalizeLang(upstreamLang)
+		} else {
+			resp.Language = detectLanguage(resp.Text)
+		}
+	}
+	return resp
+}
+
+// labelSpeakers 将上游说话人标识（1、SPEAKER_00…）按首次出现顺序统一为 A、B、C…
+func labelSpeakers(segments []Segment) {
+	labels := map[string]string{}
+	for i := range segments {
+		raw := strings.TrimSpace(segments[i].Speaker)
+		if raw == "" {
+			continue
+		}
+		label, ok := labels[raw]
+		if !ok {
+			label = SpeakerLabel(len(labels))
+			labels[raw] = label
+		}
+		segments[i].Speaker = label
+	}
+}
+
+// SpeakerLabel 0 -> A，25 -> Z，26 -> AA
+func SpeakerLabel(n int) string {
+	label := ""
+	for n >= 0 {
+		label = string(rune('A'+n%26)) + label
+		n = n/26 - 1
+	}
+	return label
+}
@@ -27,9 +27,13 @@ type APIResponse struct {
 				EndTime    int64  `json:"end_time"`
 				Confidence int    `json:"confidence"`
 			} `json:"words"`
+			Additions struct {
+				Speaker string `json:"speaker"` // 开启 enable_speaker_info 时返回
+			} `json:"additions"`
 		} `json:"utterances"`
 		Additions struct {
 			Duration string `json:"duration"`
+			LidLang  string `json:"lid_lang"` // 自动识别出的语种（上游返回时）
 		} `json:"additions"`
 	} `json:"result"`
 	Data any `json:"data"` // 保留兼容性
@@ -3,11 +3,32 @@ package asr
 import "context"
 
 type IASRSvc interface {
-	Recognize(ctx context.Context, audioUrl string) (*ASRResp, error)
+	// Recognize 识别音频；language 为语言代码，auto 表示自动识别，为空时使用配置的默认语言
+	Recognize(ctx context.Context, audioUrl string, language string) (*ASRResp, error)
 }
 
 // 火山引擎ASR响应数据结构
 type ASRResp struct {
-	Text      string `json:"text"`       // 合并后的完整文本
-	CharCount int    `json:"char_count"` // 字符数统计
+	Text      string    `json:"text"`       // 合并后的完整文本
+	CharCount int       `json:"char_count"` // 字符数统计
+	Segments  []Segment `json:"segments"`   // 带时间戳的分句（utterances）
+	Language  string    `json:"language"`   // 识别语言代码：指定时为指定值，自动识别时为检测结果，无法判断时为空
+}
+
+// Segment 带时间戳的识别分句
+type Segment struct {
+	StartMs    int64   `json:"start_ms"`
+	EndMs      int64   `json:"end_ms"`
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"` // 分句内词置信度均值（0~100）
+	Speaker    string  `json:"speaker"`    // 说话人标识（A、B…），提供方不支持分离时为空
+	Words      []Word  `json:"words"`
+}
+
+// Word 分句内的词级时间戳与置信度
+type Word struct {
+	Text       string `json:"text"`
+	StartMs    int64  `json:"start_ms"`
+	EndMs      int64  `json:"end_ms"`
+	Confidence int    `json:"confidence"` // 0~100，0 表示上游未提供
 }
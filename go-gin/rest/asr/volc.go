@@ -0,0 +1,275 @@
+package asr
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"go-gin/internal/httpc"
+	"go-gin/internal/traceid"
+	"go-gin/util/jsonx"
+)
+
+const (
+	FlashURL  = "/api/v3/auc/bigmodel/recognize/flash"
+	SubmitURL = "/api/v3/auc/bigmodel/submit"
+	QueryURL  = "/api/v3/auc/bigmodel/query"
+
+	// 标准版默认资源 ID，可通过 creds.volc.asr_submit_resource_id 覆盖
+	defaultSubmitResourceId = "volc.bigasr.auc"
+)
+
+// 火山 X-Api-Status-Code
+const (
+	volcStatusOK         = "20000000"
+	volcStatusProcessing = "20000001"
+	volcStatusQueued     = "20000002"
+	volcStatusSilent     = "20000003"
+)
+
+// 标准版轮询间隔：从 1s 开始逐步放大到 5s
+const (
+	submitPollMin = time.Second
+	submitPollMax = 5 * time.Second
+)
+
+type volcFlash struct{ httpc.BaseSvc }
+
+// NewVolcFlashProvider 火山极速版，支持 URL 与本地文件（Base64）
+func NewVolcFlashProvider(url string) Provider {
+	return &volcFlash{BaseSvc: *httpc.NewBaseSvc(url)}
+}
+
+func (p *volcFlash) Name() string { return ProviderVolcFlash }
+
+func (p *volcFlash) Recognize(ctx context.Context, audio Audio, language string) (*ASRResp, error) {
+	// 检查认证信息
+	if volcCreds.AppId == "" || volcCreds.AccessKey == "" || volcCreds.ASRResourceId == "" {
+		log.Printf("[ASR] 认证信息不完整 - AppId: %s, AccessKey: %s, ASRResourceId: %s",
+			maskString(volcCreds.AppId), maskString(volcCreds.AccessKey), maskString(volcCreds.ASRResourceId))
+		return nil, ErrProviderUnavailable
+	}
+
+	// 指定语言时传入对应 locale，auto 时不传由上游自动识别
+	requested := normalizeLang(language)
+	requestId := traceid.New()
+	var result APIResponse
+	body := map[string]any{
+		"user": map[string]any{
+			"uid": volcCreds.AppId,
+		},
+		"audio":   volcAudio(audio),
+		"request": buildRequestParams(resolveLocale(language)),
+	}
+	log.Printf("[ASR] 发送请求 - RequestId: %s, AudioSource: %s", requestId, audioSource(audio))
+
+	// 发送请求
+	if err := p.Client().NewRequest().SetContext(ctx).POST(FlashURL).
+		SetHeaders(volcHeaders(volcCreds.ASRResourceId, requestId, true)).
+		SetBody(body).
+		SetResult(&result).
+		Exec(); err != nil {
+		log.Printf("[ASR] 请求失败 - RequestId: %s, Error: %v", requestId, err)
+		return nil, err
+	}
+
+	resp := volcToResp(&result, requested)
+	log.Printf("[ASR] 识别成功 - RequestId: %s, Language: %s, CharCount: %d, Utterances: %d, Duration: %dms",
+		requestId, resp.Language, resp.CharCount, len(result.Result.Utterances), result.AudioInfo.Duration)
+	log.Printf("[ASR] 识别文本预览: %.200s...", resp.Text)
+	return resp, nil
+}
+
+type volcSubmit struct{ httpc.BaseSvc }
+
+// NewVolcSubmitProvider 火山标准版：提交任务后轮询结果，仅支持可公网访问的 URL
+func NewVolcSubmitProvider(url string) Provider {
+	return &volcSubmit{BaseSvc: *httpc.NewBaseSvc(url)}
+}
+
+func (p *volcSubmit) Name() string { return ProviderVolcSubmit }
+
+func (p *volcSubmit) Recognize(ctx context.Context, audio Audio, language string) (*ASRResp, error) {
+	if volcCreds.AppId == "" || volcCreds.AccessKey == "" {
+		return nil, ErrProviderUnavailable
+	}
+	if audio.URL == "" {
+		return nil, ErrProviderUnavailable
+	}
+	resourceId := volcCreds.ASRSubmitResourceId
+	if resourceId == "" {
+		resourceId = defaultSubmitResourceId
+	}
+
+	requested := normalizeLang(language)
+	requestId := traceid.New()
+	body := map[string]any{
+		"user": map[string]any{
+			"uid": volcCreds.AppId,
+		},
+		"audio": map[string]any{
+			"url":    audio.URL,
+			"format": audioFormat(audio),
+		},
+		"request": buildRequestParams(resolveLocale(language)),
+	}
+	log.Printf("[ASR] 提交识别任务 - RequestId: %s, url: %s", requestId, audio.URL)
+	r, err := p.Client().NewRequest().SetContext(ctx).POST(SubmitURL).
+		SetHeaders(volcHeaders(resourceId, requestId, true)).
+		SetBody(body).
+		Send()
+	if err != nil {
+		return nil, err
+	}
+	if code := r.Header().Get("X-Api-Status-Code"); code != volcStatusOK {
+		log.Printf("[ASR] 提交识别任务失败 - RequestId: %s, Code: %s, Message: %s", requestId, code, r.Header().Get("X-Api-Message"))
+		return nil, errSubmitFailed
+	}
+
+	interval := submitPollMin
+	for {
+		r, err := p.Client().NewRequest().SetContext(ctx).POST(QueryURL).
+			SetHeaders(volcHeaders(resourceId, requestId, false)).
+			SetBody(map[string]any{}).
+			Send()
+		if err != nil {
+			return nil, err
+		}
+		switch code := r.Header().Get("X-Api-Status-Code"); code {
+		case volcStatusOK:
+			var result APIResponse
+			if err := jsonx.Unmarshal(r.Body(), &result); err != nil {
+				return nil, err
+			}
+			resp := volcToResp(&result, requested)
+			log.Printf("[ASR] 识别任务完成 - RequestId: %s, Language: %s, CharCount: %d", requestId, resp.Language, resp.CharCount)
+			return resp, nil
+		case volcStatusSilent:
+			return finishResp(&ASRResp{}, requested, ""), nil
+		case volcStatusProcessing, volcStatusQueued:
+		default:
+			log.Printf("[ASR] 查询识别任务失败 - RequestId: %s, Code: %s, Message: %s", requestId, code, r.Header().Get("X-Api-Message"))
+			return nil, errSubmitFailed
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, submitPollMax)
+	}
+}
+
+var errSubmitFailed = errors.New("asr: volc submit task failed")
+
+func volcHeaders(resourceId, requestId string, withSequence bool) map[string]string {
+	h := map[string]string{
+		"X-Api-App-Key":     volcCreds.AppId,
+		"X-Api-Access-Key":  volcCreds.AccessKey,
+		"X-Api-Resource-Id": resourceId,
+		"X-Api-Request-Id":  requestId,
+	}
+	if withSequence {
+		h["X-Api-Sequence"] = "-1"
+	}
+	return h
+}
+
+// volcAudio 音频参数：{"url": ...} 或 {"data": base64}
+func volcAudio(audio Audio) map[string]any {
+	if audio.URL != "" {
+		return map[string]any{"url": audio.URL}
+	}
+	base64Data := base64.StdEncoding.EncodeToString(audio.Data)
+	log.Printf("[ASR] 文件转Base64成功 - size: %d bytes, base64Length: %d", len(audio.Data), len(base64Data))
+	return map[string]any{"data": base64Data}
+}
+
+func audioSource(audio Audio) string {
+	if audio.URL != "" {
+		return "url"
+	}
+	return "base64_file"
+}
+
+// audioFormat 由文件名推断音频格式，默认 mp3
+func audioFormat(audio Audio) string {
+	name := audio.Name
+	if name == "" {
+		name = audio.URL
+	}
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	if ext := strings.TrimPrefix(strings.ToLower(path.Ext(name)), "."); ext != "" {
+		return ext
+	}
+	return "mp3"
+}
+
+// volcToResp 转换火山引擎数据为统一格式
+func volcToResp(result *APIResponse, requested string) *ASRResp {
+	resp := &ASRResp{}
+	// 优先使用result.text，如果为空则从utterances合并
+	if result.Result.Text != "" {
+		resp.Text = result.Result.Text
+	} else {
+		var fullText strings.Builder
+		for _, utterance := range result.Result.Utterances {
+			fullText.WriteString(utterance.Text)
+		}
+		resp.Text = fullText.String()
+	}
+	resp.Segments = buildSegments(result)
+	return finishResp(resp, requested, result.Result.Additions.LidLang)
+}
+
+// buildRequestParams 构造识别参数，locale 为空时不指定语言
+func buildRequestParams(locale string) map[string]any {
+	params := map[string]any{
+		"model_name":          "bigmodel",
+		"enable_ddc":          true,
+		"enable_speaker_info": true, // 说话人分离
+	}
+	if locale != "" {
+		params["language"] = locale
+	}
+	return params
+}
+
+func buildSegments(result *APIResponse) []Segment {
+	segments := make([]Segment, 0, len(result.Result.Utterances))
+	for _, u := range result.Result.Utterances {
+		text := strings.TrimSpace(u.Text)
+		if text == "" {
+			continue
+		}
+		seg := Segment{
+			StartMs: u.StartTime,
+			EndMs:   u.EndTime,
+			Text:    text,
+			Speaker: u.Additions.Speaker,
+			Words:   make([]Word, 0, len(u.Words)),
+		}
+		total := 0
+		for _, w := range u.Words {
+			seg.Words = append(seg.Words, Word{
+				Text:       w.Text,
+				StartMs:    w.StartTime,
+				EndMs:      w.EndTime,
+				Confidence: w.Confidence,
+			})
+			total += w.Confidence
+		}
+		if len(u.Words) > 0 {
+			seg.Confidence = float64(total) / float64(len(u.Words))
+		}
+		segments = append(segments, seg)
+	}
+	return segments
+}
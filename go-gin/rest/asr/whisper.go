@@ -0,0 +1,219 @@
+package asr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-gin/internal/httpc"
+	"go-gin/util/jsonx"
+)
+
+const (
+	TranscriptionsURL = "/v1/audio/transcriptions"
+
+	defaultWhisperModel = "whisper-1"
+	// URL 音频需先下载再上传，限制单个文件大小
+	whisperMaxDownload = 512 << 20
+)
+
+// WhisperConfig OpenAI 兼容转写接口配置；URL 为空时不注册该提供方
+type WhisperConfig struct {
+	URL    string // 服务地址，如 https://api.openai.com 或自建 whisper 服务
+	ApiKey string // 可为空（自建服务通常不鉴权）
+	Model  string // 默认 whisper-1
+}
+
+// SetWhisperConfig 注册 whisper 提供方
+func SetWhisperConfig(c WhisperConfig) {
+	if strings.TrimSpace(c.URL) == "" {
+		return
+	}
+	Register(NewWhisperProvider(c))
+}
+
+type whisper struct {
+	httpc.BaseSvc
+	apiKey string
+	model  string
+}
+
+// NewWhisperProvider OpenAI 兼容 /v1/audio/transcriptions（verbose_json，含分句与词级时间戳）
+func NewWhisperProvider(c WhisperConfig) Provider {
+	model := c.Model
+	if model == "" {
+		model = defaultWhisperModel
+	}
+	return &whisper{BaseSvc: *httpc.NewBaseSvc(strings.TrimRight(c.URL, "/")), apiKey: c.ApiKey, model: model}
+}
+
+func (p *whisper) Name() string { return ProviderWhisper }
+
+// WhisperResponse verbose_json 响应；出错时只有 error 字段
+type WhisperResponse struct {
+	Text     string  `json:"text"`
+	Language string  `json:"language"` // 语言全称（english）或代码
+	Duration float64 `json:"duration"`
+	Segments []struct {
+		Start      float64 `json:"start"`
+		End        float64 `json:"end"`
+		Text       string  `json:"text"`
+		AvgLogprob float64 `json:"avg_logprob"`
+		Speaker    string  `json:"speaker"` // 支持说话人分离的服务（如 whisperX）返回
+	} `json:"segments"`
+	Words []struct {
+		Word        string  `json:"word"`
+		Start       float64 `json:"start"`
+		End         float64 `json:"end"`
+		Probability float64 `json:"probability"` // faster-whisper 等自建服务返回，OpenAI 不返回
+	} `json:"words"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+var _ httpc.IResponse = (*WhisperResponse)(nil)
+
+func (r *WhisperResponse) Parse(b []byte) error { return jsonx.Unmarshal(b, &r) }
+func (r *WhisperResponse) Valid() bool {
+	return r.Error != nil || r.Text != "" || r.Segments != nil || r.Duration > 0
+}
+func (r *WhisperResponse) IsSuccess() bool { return r.Error == nil }
+func (r *WhisperResponse) Msg() string {
+	if r.Error != nil {
+		return r.Error.Message
+	}
+	return ""
+}
+func (r *WhisperResponse) ParseData() error { return nil }
+
+func (p *whisper) Recognize(ctx context.Context, audio Audio, language string) (*ASRResp, error) {
+	requested := normalizeLang(language)
+	data := audio.Data
+	if len(data) == 0 {
+		var err error
+		if data, err = downloadAudio(ctx, audio.URL); err != nil {
+			return nil, err
+		}
+	}
+	name := audio.Name
+	if name == "" || !strings.Contains(name, ".") {
+		name = "audio." + audioFormat(audio)
+	}
+
+	fields := map[string]string{
+		"model":           p.model,
+		"response_format": "verbose_json",
+	}
+	if requested != LangAuto {
+		fields["language"] = requested
+	}
+	req := p.Client().NewRequest().SetContext(ctx).POST(TranscriptionsURL).
+		SetMultipartFile("file", name, bytes.NewReader(data)).
+		SetMultipartFormData(fields).
+		AddFormData("timestamp_granularities[]", []string{"segment", "word"})
+	if p.apiKey != "" {
+		req.SetHeader("Authorization", "Bearer "+p.apiKey)
+	}
+	log.Printf("[ASR] whisper 发送请求 - model: %s, file: %s, size: %d bytes", p.model, name, len(data))
+
+	var result WhisperResponse
+	if err := req.SetResult(&result).Exec(); err != nil {
+		log.Printf("[ASR] whisper 请求失败 - error: %v, message: %s", err, result.Msg())
+		return nil, err
+	}
+
+	resp := &ASRResp{Text: result.Text, Segments: make([]Segment, 0, len(result.Segments))}
+	for _, s := range result.Segments {
+		text := strings.TrimSpace(s.Text)
+		if text == "" {
+			continue
+		}
+		seg := Segment{
+			StartMs:    secToMs(s.Start),
+			EndMs:      secToMs(s.End),
+			Text:       text,
+			Confidence: math.Exp(s.AvgLogprob) * 100, // 平均对数概率换算为 0~100，与火山一致
+			Speaker:    s.Speaker,
+		}
+		for _, w := range result.Words {
+			if ms := secToMs(w.Start); ms >= seg.StartMs && ms < seg.EndMs {
+				seg.Words = append(seg.Words, Word{
+					Text:       strings.TrimSpace(w.Word),
+					StartMs:    ms,
+					EndMs:      secToMs(w.End),
+					Confidence: int(math.Round(w.Probability * 100)),
+				})
+			}
+		}
+		resp.Segments = append(resp.Segments, seg)
+	}
+	finishResp(resp, requested, whisperLangCode(result.Language))
+	log.Printf("[ASR] whisper 识别成功 - Language: %s, CharCount: %d, Segments: %d", resp.Language, resp.CharCount, len(resp.Segments))
+	return resp, nil
+}
+
+func secToMs(sec float64) int64 { return int64(math.Round(sec * 1000)) }
+
+// whisperLangs whisper 返回的语言全称到语言代码
+var whisperLangs = map[string]string{
+	"chinese":    "zh",
+	"english":    "en",
+	"japanese":   "ja",
+	"korean":     "ko",
+	"spanish":    "es",
+	"french":     "fr",
+	"german":     "de",
+	"russian":    "ru",
+	"portuguese": "pt",
+	"italian":    "it",
+	"arabic":     "ar",
+	"indonesian": "id",
+	"vietnamese": "vi",
+	"thai":       "th",
+}
+
+func whisperLangCode(lang string) string {
+	l := strings.ToLower(strings.TrimSpace(lang))
+	if code, ok := whisperLangs[l]; ok {
+		return code
+	}
+	return l
+}
+
+// downloadAudio 下载 URL 音频用于上传
+func downloadAudio(ctx context.Context, url string) ([]byte, error) {
+	if url == "" {
+		return nil, ErrProviderUnavailable
+	}
+	cctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	req, err := http.NewRequestWithContext(cctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download audio failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download audio failed: status %d", resp.StatusCode)
+	}
+	// 多读一个字节用于判断是否超限，避免静默截断音频
+	data, err := io.ReadAll(io.LimitReader(resp.Body, whisperMaxDownload+1))
+	if err != nil {
+		return nil, fmt.Errorf("download audio failed: %w", err)
+	}
+	if int64(len(data)) > whisperMaxDownload {
+		return nil, fmt.Errorf("download audio failed: exceeds %d bytes", int64(whisperMaxDownload))
+	}
+	return data, nil
+}
@@ -2,6 +2,8 @@ package dlyt
 
 import (
 	"context"
+
+	"go-gin/util/subtitle"
 )
 
 type IYtSvc interface {
@@ -10,6 +12,8 @@ type IYtSvc interface {
 	// 新增带平台参数的方法
 	InfoWithPlatform(ctx context.Context, idOrUrl, platform string) (*InfoResp, error)
 	AudioWithPlatform(ctx context.Context, idOrUrl, platform string) (*AudioResp, error)
+	// SubtitlesWithPlatform 获取平台提供的字幕（人工优先，可选自动字幕），没有字幕时返回错误
+	SubtitlesWithPlatform(ctx context.Context, idOrUrl, platform string, req SubtitlesReq) (*SubtitlesResp, error)
 }
 
 type InfoResp struct {
@@ -27,3 +31,14 @@ type AudioResp struct {
 	Title    string `json:"title"`
 	AudioUrl string `json:"audio_url"`
 }
+
+type SubtitlesReq struct {
+	Langs       []string // 按优先级排列的语言代码，为空时使用视频原始语言
+	IncludeAuto bool     // 没有人工字幕时是否使用自动字幕
+}
+
+type SubtitlesResp struct {
+	Lang   string         `json:"lang"`
+	Source string         `json:"source"` // manual | auto
+	Cues   []subtitle.Cue `json:"cues"`
+}
@@ -90,3 +90,8 @@ func (s *YtSvc) AudioWithPlatform(ctx context.Context, idOrUrl, platform string)
 	}
 	return resp, nil
 }
+
+// SubtitlesWithPlatform 远程服务暂不提供字幕接口，调用方回退到语音识别
+func (s *YtSvc) SubtitlesWithPlatform(ctx context.Context, idOrUrl, platform string, req SubtitlesReq) (*SubtitlesResp, error) {
+	return nil, errcode.ErrDLYTUpstream
+}
@@ -445,6 +445,22 @@ func (s *LocalYtSvc) AudioWithPlatform(ctx context.Context, idOrUrl, platform st
 	return &AudioResp{Id: videoId, Title: video.Title, AudioUrl: finalAudioURL}, nil
 }
 
+// SubtitlesWithPlatform 通过 yt-dlp 获取平台字幕
+func (s *LocalYtSvc) SubtitlesWithPlatform(ctx context.Context, idOrUrl, platform string, req SubtitlesReq) (*SubtitlesResp, error) {
+	videoSource := platform
+	if videoSource == "" {
+		videoSource = detectVideoSource(idOrUrl)
+	}
+	fullURL := constructFullURL(idOrUrl, videoSource)
+	subs, err := ytdl.FetchSubtitles(ctx, fullURL, videoSource, ytdl.SubtitleOptions{Langs: req.Langs, IncludeAuto: req.IncludeAuto})
+	if err != nil {
+		log.Printf("yt.subtitles unavailable input=%s source=%s err=%v", idOrUrl, videoSource, err)
+		return nil, err
+	}
+	log.Printf("yt.subtitles ok input=%s lang=%s source=%s cues=%d", idOrUrl, subs.Lang, subs.Source, len(subs.Cues))
+	return &SubtitlesResp{Lang: subs.Lang, Source: subs.Source, Cues: subs.Cues}, nil
+}
+
 // 工具函数
 func extractVideoIDFast(input string) string {
 	s := strings.TrimSpace(input)
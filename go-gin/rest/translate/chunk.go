@@ -0,0 +1,318 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"go-gin/const/errcode"
+	"go-gin/internal/traceid"
+)
+
+const (
+	batchTokenBudget = 2000 // 每批原文的估算 token 上限，给译文与提示词留足上下文
+	batchMaxSegments = 80   // 每批最多分句数，行数过多时模型容易漏行
+	batchConcurrency = 4    // 同时进行的批次数
+)
+
+// errMisaligned 模型输出的行号与输入无法一一对应
+var errMisaligned = errors.New("translate: batch output misaligned")
+
+// SegmentsResp 分句翻译结果，Texts 与输入下标一一对应；
+// CharCount 只统计本次实际调用模型翻译的字符，命中翻译记忆的分句不计费
+type SegmentsResp struct {
+	Texts        []string    `json:"texts"`
+	CharCount    int         `json:"char_count"`
+	Violations   []Violation `json:"violations,omitempty"` // 未遵守术语表的分句
+	MemoryHits   int         `json:"memory_hits"`          // 命中翻译记忆的分句数
+	MemoryMisses int         `json:"memory_misses"`        // 交给模型翻译的分句数
+}
+
+// segmentItem 待翻译的单个分句，index 为其在输入中的下标
+type segmentItem struct {
+	index int
+	text  string
+}
+
+// TranslateSegments 先按分句查询翻译记忆，未命中的按 token 预算分批翻译，批次并发执行，结果按原下标回填；
+// 空白分句原样返回；上游失败由 llm 客户端按配置重试，只重发失败的批次
+func (s *TranslateSvc) TranslateSegments(ctx context.Context, segments []string, sourceLang, targetLang string) (*SegmentsResp, error) {
+	if !IsSupported(targetLang) {
+		log.Printf("[Translate] 不支持的目标语言: %s", targetLang)
+		return nil, errcode.ErrLangNotSupported
+	}
+
+	items := make([]segmentItem, 0, len(segments))
+	for i, seg := range segments {
+		if text := strings.TrimSpace(seg); text != "" {
+			items = append(items, segmentItem{index: i, text: text})
+		}
+	}
+	texts := make([]string, len(segments))
+	copy(texts, segments)
+
+	pending := items
+	var memory *memoryScope
+	if memoryEnabled && len(items) > 0 {
+		memory = newMemoryScope(ctx, sourceLang, targetLang)
+		hits := memory.lookup(ctx, items)
+		pending = make([]segmentItem, 0, len(items)-len(hits))
+		for _, item := range items {
+			if text, ok := hits[item.index]; ok {
+				texts[item.index] = text
+			} else {
+				pending = append(pending, item)
+			}
+		}
+	}
+
+	batches := splitBatches(pending, batchTokenBudget, batchMaxSegments)
+	log.Printf("[Translate] 分批翻译 - 分句数: %d, 命中翻译记忆: %d, 批次数: %d, %s -> %s",
+		len(segments), len(items)-len(pending), len(batches), NormalizeLang(sourceLang), NormalizeLang(targetLang))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, batchConcurrency)
+	)
+	for n, batch := range batches {
+		wg.Add(1)
+		go func(n int, batch []segmentItem) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			out, err := s.runBatch(ctx, batch, sourceLang, targetLang)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					log.Printf("[Translate] 批次翻译失败 - Batch: %d/%d, Error: %v", n+1, len(batches), err)
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			for index, text := range out {
+				texts[index] = text
+			}
+		}(n, batch)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if memory != nil {
+		memory.save(ctx, pending, texts)
+	}
+
+	resp := &SegmentsResp{Texts: texts, MemoryHits: len(items) - len(pending), MemoryMisses: len(pending)}
+	for _, item := range pending {
+		resp.CharCount += len([]rune(texts[item.index]))
+	}
+	terms := glossaryFrom(ctx)
+	for _, item := range items {
+		resp.Violations = append(resp.Violations, CheckGlossary(terms, item.index, item.text, texts[item.index])...)
+	}
+	log.Printf("[Translate] 分批翻译完成 - 分句数: %d, CharCount: %d, 记忆命中/未命中: %d/%d, 术语违规: %d",
+		len(segments), resp.CharCount, resp.MemoryHits, resp.MemoryMisses, len(resp.Violations))
+	return resp, nil
+}
+
+// runBatch 翻译一个批次：行号对不齐时对半拆分后分别翻译；
+// 上游失败不在此重试（llm 客户端已按 MaxRetries 重试），避免两层重试叠加放大调用次数
+func (s *TranslateSvc) runBatch(ctx context.Context, batch []segmentItem, sourceLang, targetLang string) (map[int]string, error) {
+	out, err := s.translateBatch(ctx, batch, sourceLang, targetLang)
+	if err == nil {
+		return out, nil
+	}
+	if !errors.Is(err, errMisaligned) {
+		return nil, err
+	}
+	if len(batch) == 1 {
+		return nil, errcode.ErrTranslateUp
+	}
+	mid := len(batch) / 2
+	log.Printf("[Translate] 批次行号不一致，拆分重试 - Size: %d", len(batch))
+	left, err := s.runBatch(ctx, batch[:mid], sourceLang, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	right, err := s.runBatch(ctx, batch[mid:], sourceLang, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range right {
+		left[k] = v
+	}
+	return left, nil
+}
+
+// translateBatch 单次调用模型翻译一个批次；多行时使用 [序号] 标注每行并按序号解析
+func (s *TranslateSvc) translateBatch(ctx context.Context, batch []segmentItem, sourceLang, targetLang string) (map[int]string, error) {
+	requestId := traceid.New()
+	sources := make([]string, 0, len(batch))
+	for _, item := range batch {
+		sources = append(sources, item.text)
+	}
+	terms := MatchTerms(glossaryFrom(ctx), sources...)
+	if len(batch) == 1 {
+		content, _, err := s.chat(ctx, requestId, withGlossaryPrompt(buildSystemPrompt(sourceLang, targetLang), terms), buildUserPrompt(batch[0].text, sourceLang, targetLang))
+		if err != nil {
+			return nil, err
+		}
+		text := cleanContent(content)
+		if text == "" {
+			return nil, errMisaligned
+		}
+		return map[int]string{batch[0].index: text}, nil
+	}
+
+	var sb strings.Builder
+	for i, item := range batch {
+		// 分句内的换行会破坏逐行协议，先压成空格
+		fmt.Fprintf(&sb, "[%d] %s\n", i+1, strings.Join(strings.Fields(item.text), " "))
+	}
+	content, usageTokens, err := s.chat(ctx, requestId, withGlossaryPrompt(buildBatchSystemPrompt(sourceLang, targetLang), terms), buildUserPrompt(sb.String(), sourceLang, targetLang))
+	if err != nil {
+		return nil, err
+	}
+	lines, ok := parseNumberedLines(content, len(batch))
+	if !ok {
+		log.Printf("[Translate] 批次输出无法对齐 - RequestId: %s, Size: %d, Content: %.200s", requestId, len(batch), content)
+		return nil, errMisaligned
+	}
+	log.Printf("[Translate] 批次翻译成功 - RequestId: %s, Size: %d, UsageTokens: %d", requestId, len(batch), usageTokens)
+	out := make(map[int]string, len(batch))
+	for i, item := range batch {
+		out[item.index] = lines[i]
+	}
+	return out, nil
+}
+
+// buildBatchSystemPrompt 在语言对提示词基础上约定逐行编号的输入输出格式
+func buildBatchSystemPrompt(sourceLang, targetLang string) string {
+	return buildSystemPrompt(sourceLang, targetLang) +
+		"输入每行以 [序号] 开头，请逐行翻译并在译文前保留相同的 [序号]，输出行数必须与输入一致，不要合并、拆分或省略任何一行。"
+}
+
+var numberedLineRe = regexp.MustCompile(`^\s*\[(\d+)\]\s?(.*)$`)
+
+// parseNumberedLines 解析 "[n] 译文" 格式的输出；无序号的行视为上一行的续行
+func parseNumberedLines(content string, n int) ([]string, bool) {
+	lines := make([]string, n)
+	seen := make([]bool, n)
+	cur := -1
+	for _, line := range strings.Split(content, "\n") {
+		if m := numberedLineRe.FindStringSubmatch(line); m != nil {
+			num, err := strconv.Atoi(m[1])
+			if err != nil || num < 1 || num > n || seen[num-1] {
+				return nil, false
+			}
+			cur = num - 1
+			seen[cur] = true
+			lines[cur] = strings.TrimSpace(m[2])
+			continue
+		}
+		if line = strings.TrimSpace(line); line != "" && cur >= 0 {
+			lines[cur] = strings.TrimSpace(lines[cur] + " " + line)
+		}
+	}
+	for i := range lines {
+		if !seen[i] || lines[i] == "" {
+			return nil, false
+		}
+	}
+	return lines, true
+}
+
+// splitBatches 按估算 token 数与分句数切分批次，保持原有顺序；超出预算的单个分句独占一批
+func splitBatches(items []segmentItem, tokenBudget, maxSegments int) [][]segmentItem {
+	var batches [][]segmentItem
+	var cur []segmentItem
+	curTokens := 0
+	for _, item := range items {
+		tokens := estimateTokens(item.text)
+		if len(cur) > 0 && (curTokens+tokens > tokenBudget || len(cur) >= maxSegments) {
+			batches = append(batches, cur)
+			cur, curTokens = nil, 0
+		}
+		cur = append(cur, item)
+		curTokens += tokens
+	}
+	if len(cur) > 0 {
+		batches = append(batches, cur)
+	}
+	return batches
+}
+
+// estimateTokens 粗略估算 token 数：非 ASCII 字符按 1 个计，ASCII 按 4 个字符 1 个计
+func estimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r <= unicode.MaxASCII {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return other + (ascii+3)/4
+}
+
+// sentenceEnds 句末标点，用于没有分句信息时的兜底切分
+const sentenceEnds = ".!?。！？"
+
+// SplitSentences 按句末标点切分文本，标点保留在句尾
+func SplitSentences(text string) []string {
+	var sentences []string
+	var sb strings.Builder
+	runes := []rune(text)
+	for i, r := range runes {
+		sb.WriteRune(r)
+		if !strings.ContainsRune(sentenceEnds, r) {
+			continue
+		}
+		// 连续标点或半角句点后紧跟非空白（如小数、缩写）时不断句
+		if i+1 < len(runes) && (strings.ContainsRune(sentenceEnds, runes[i+1]) || (r <= unicode.MaxASCII && !unicode.IsSpace(runes[i+1]))) {
+			continue
+		}
+		if s := strings.TrimSpace(sb.String()); s != "" {
+			sentences = append(sentences, s)
+		}
+		sb.Reset()
+	}
+	if s := strings.TrimSpace(sb.String()); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
+
+// JoinTexts 拼接分句译文：中日韩目标语言直接相连，其余语言以空格分隔
+func JoinTexts(texts []string, targetLang string) string {
+	sep := " "
+	switch NormalizeLang(targetLang) {
+	case "zh", "ja", "ko":
+		sep = ""
+	}
+	parts := make([]string, 0, len(texts))
+	for _, t := range texts {
+		if t = strings.TrimSpace(t); t != "" {
+			parts = append(parts, t)
+		}
+	}
+	return strings.Join(parts, sep)
+}
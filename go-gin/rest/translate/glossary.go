@@ -0,0 +1,106 @@
+package translate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Term 术语表条目：原文术语必须译为 Target
+type Term struct {
+	Source        string `json:"source"`
+	Target        string `json:"target"`
+	CaseSensitive bool   `json:"case_sensitive"`
+}
+
+// Violation 译文未使用术语表指定译法；Segment 为分句下标，整段翻译时为 -1
+type Violation struct {
+	Segment int    `json:"segment"`
+	Source  string `json:"source"`
+	Target  string `json:"target"`
+}
+
+type glossaryKey struct{}
+
+// WithGlossary 为本次翻译附加术语表，翻译时注入提示词并校验译文
+func WithGlossary(ctx context.Context, terms []Term) context.Context {
+	if len(terms) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, glossaryKey{}, terms)
+}
+
+func glossaryFrom(ctx context.Context) []Term {
+	terms, _ := ctx.Value(glossaryKey{}).([]Term)
+	return terms
+}
+
+// GlossaryVersion 术语集合的摘要，与顺序无关；没有术语时为空
+func GlossaryVersion(terms []Term) string {
+	if len(terms) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(terms))
+	for _, t := range terms {
+		lines = append(lines, fmt.Sprintf("%s\x00%s\x00%t", t.Source, t.Target, t.CaseSensitive))
+	}
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+func containsTerm(text, term string, caseSensitive bool) bool {
+	if term == "" {
+		return false
+	}
+	if caseSensitive {
+		return strings.Contains(text, term)
+	}
+	return strings.Contains(strings.ToLower(text), strings.ToLower(term))
+}
+
+// MatchTerms 返回原文中出现的术语
+func MatchTerms(terms []Term, texts ...string) []Term {
+	var matched []Term
+	for _, t := range terms {
+		for _, text := range texts {
+			if containsTerm(text, t.Source, t.CaseSensitive) {
+				matched = append(matched, t)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// CheckGlossary 校验译文：原文出现的术语，译文中必须出现对应译法
+func CheckGlossary(terms []Term, segment int, source, translated string) []Violation {
+	var violations []Violation
+	for _, t := range MatchTerms(terms, source) {
+		if !containsTerm(translated, t.Target, t.CaseSensitive) {
+			violations = append(violations, Violation{Segment: segment, Source: t.Source, Target: t.Target})
+		}
+	}
+	return violations
+}
+
+// withGlossaryPrompt 在系统提示词后追加本批原文涉及的术语译法
+func withGlossaryPrompt(systemPrompt string, terms []Term) string {
+	if len(terms) == 0 {
+		return systemPrompt
+	}
+	var sb strings.Builder
+	sb.WriteString(systemPrompt)
+	sb.WriteString("\n请严格按照以下术语表翻译（原文 => 译文），术语译法不得改写：\n")
+	for _, t := range terms {
+		sb.WriteString("- ")
+		sb.WriteString(t.Source)
+		sb.WriteString(" => ")
+		sb.WriteString(t.Target)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
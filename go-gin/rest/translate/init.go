@@ -4,5 +4,5 @@ var (
 	Svc ITranslateSvc = (*TranslateSvc)(nil)
 )
 
-func Init(url string) { Svc = NewTranslateSvc(url) }
+func Init() { Svc = NewTranslateSvc() }
 
@@ -0,0 +1,80 @@
+package translate
+
+import "strings"
+
+// LangAuto 表示源语言未知，由模型自行判断
+const LangAuto = "auto"
+
+// Language 支持的语言
+type Language struct {
+	Code   string // ISO 639-1 代码
+	Name   string // 英文名称
+	ZhName string // 中文名称，用于构造提示词
+}
+
+var languages = map[string]Language{
+	"zh": {Code: "zh", Name: "Chinese", ZhName: "简体中文"},
+	"en": {Code: "en", Name: "English", ZhName: "英文"},
+	"ja": {Code: "ja", Name: "Japanese", ZhName: "日文"},
+	"ko": {Code: "ko", Name: "Korean", ZhName: "韩文"},
+	"es": {Code: "es", Name: "Spanish", ZhName: "西班牙文"},
+	"fr": {Code: "fr", Name: "French", ZhName: "法文"},
+	"de": {Code: "de", Name: "German", ZhName: "德文"},
+	"ru": {Code: "ru", Name: "Russian", ZhName: "俄文"},
+	"pt": {Code: "pt", Name: "Portuguese", ZhName: "葡萄牙文"},
+	"it": {Code: "it", Name: "Italian", ZhName: "意大利文"},
+	"ar": {Code: "ar", Name: "Arabic", ZhName: "阿拉伯文"},
+	"id": {Code: "id", Name: "Indonesian", ZhName: "印尼文"},
+	"vi": {Code: "vi", Name: "Vietnamese", ZhName: "越南文"},
+	"th": {Code: "th", Name: "Thai", ZhName: "泰文"},
+}
+
+// NormalizeLang 规范化语言代码：en-US -> en，zh_CN -> zh；空值视为 auto
+func NormalizeLang(code string) string {
+	c := strings.ToLower(strings.TrimSpace(code))
+	if c == "" || c == LangAuto {
+		return LangAuto
+	}
+	if i := strings.IndexAny(c, "-_"); i > 0 {
+		c = c[:i]
+	}
+	return c
+}
+
+// LookupLanguage 按代码查找语言（会先规范化）
+func LookupLanguage(code string) (Language, bool) {
+	l, ok := languages[NormalizeLang(code)]
+	return l, ok
+}
+
+// IsSupported 判断语言是否受支持
+func IsSupported(code string) bool {
+	_, ok := LookupLanguage(code)
+	return ok
+}
+
+// SameLang 判断两种语言是否相同（忽略地区后缀）
+func SameLang(a, b string) bool {
+	na, nb := NormalizeLang(a), NormalizeLang(b)
+	return na != LangAuto && na == nb
+}
+
+// buildSystemPrompt 根据语言对构造系统提示词
+func buildSystemPrompt(sourceLang, targetLang string) string {
+	target := languages[NormalizeLang(targetLang)]
+	if source, ok := LookupLanguage(sourceLang); ok {
+		return "你是一个专业的翻译助手。请将用户提供的" + source.ZhName + "文本翻译成" + target.ZhName + "，保持原意和语言风格。" +
+			"请直接返回翻译后的" + target.ZhName + "文本，不要添加任何解释或前缀，不要保留未翻译的原文。"
+	}
+	return "你是一个专业的翻译助手。请识别用户提供文本的语言，并将其翻译成" + target.ZhName + "，保持原意和语言风格。" +
+		"请直接返回翻译后的" + target.ZhName + "文本，不要添加任何解释或前缀，不要保留未翻译的原文。"
+}
+
+// buildUserPrompt 根据语言对构造用户消息
+func buildUserPrompt(text, sourceLang, targetLang string) string {
+	target := languages[NormalizeLang(targetLang)]
+	if source, ok := LookupLanguage(sourceLang); ok {
+		return "请翻译以下" + source.ZhName + "文本为" + target.ZhName + "：\n\n" + text
+	}
+	return "请翻译以下文本为" + target.ZhName + "：\n\n" + text
+}
@@ -0,0 +1,170 @@
+package translate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"go-gin/internal/component/redisx"
+	"go-gin/internal/llm"
+	"go-gin/model"
+)
+
+const (
+	memoryCacheTTL    = 7 * 24 * time.Hour
+	memoryCachePrefix = "translate:tm:"
+	memoryDBBatch     = 500 // 单次查询/写入 MySQL 的最大条数
+)
+
+var memoryEnabled = true
+
+// SetMemoryEnabled 开关分句级翻译记忆（默认开启）
+func SetMemoryEnabled(enabled bool) { memoryEnabled = enabled }
+
+// MemoryKey 翻译记忆键：原文、语言对、提供方与术语表版本都相同时复用译文
+func MemoryKey(text, sourceLang, targetLang string, p Provider, glossaryVersion string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		strings.TrimSpace(text), NormalizeLang(sourceLang), NormalizeLang(targetLang), string(p), glossaryVersion,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// memoryScope 一次分句翻译的记忆上下文，Redis 在前、MySQL 兜底
+type memoryScope struct {
+	sourceLang      string
+	targetLang      string
+	provider        Provider
+	glossaryVersion string
+	model           *model.TranslationMemoryModel
+}
+
+func newMemoryScope(ctx context.Context, sourceLang, targetLang string) *memoryScope {
+	p := provider
+	if client, err := llm.Get(string(p)); err == nil {
+		p = Provider(client.Name()) // 未指定时按实际使用的默认提供方区分
+	}
+	return &memoryScope{
+		sourceLang:      NormalizeLang(sourceLang),
+		targetLang:      NormalizeLang(targetLang),
+		provider:        p,
+		glossaryVersion: GlossaryVersion(glossaryFrom(ctx)),
+		model:           model.NewTranslationMemoryModel(),
+	}
+}
+
+func (m *memoryScope) key(text string) string {
+	return MemoryKey(text, m.sourceLang, m.targetLang, m.provider, m.glossaryVersion)
+}
+
+// lookup 按分句查找翻译记忆，返回命中的译文（按分句下标）；存储异常时按未命中处理
+func (m *memoryScope) lookup(ctx context.Context, items []segmentItem) map[int]string {
+	found := map[string]string{}
+	hashes := make([]string, 0, len(items))
+	seen := map[string]bool{}
+	for _, item := range items {
+		if h := m.key(item.text); !seen[h] {
+			seen[h] = true
+			hashes = append(hashes, h)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(hashes))
+	for i, h := range hashes {
+		keys[i] = memoryCachePrefix + h
+	}
+	if vals, err := redisx.Client().MGet(ctx, keys...).Result(); err != nil {
+		log.Printf("[Translate] 读取翻译记忆缓存失败 - Error: %v", err)
+	} else {
+		for i, v := range vals {
+			if s, ok := v.(string); ok && s != "" {
+				found[hashes[i]] = s
+			}
+		}
+	}
+
+	var missed []string
+	for _, h := range hashes {
+		if _, ok := found[h]; !ok {
+			missed = append(missed, h)
+		}
+	}
+	backfill := map[string]string{}
+	for start := 0; start < len(missed); start += memoryDBBatch {
+		rows, err := m.model.FindByHashes(ctx, missed[start:min(start+memoryDBBatch, len(missed))])
+		if err != nil {
+			log.Printf("[Translate] 查询翻译记忆失败 - Error: %v", err)
+			break
+		}
+		for _, row := range rows {
+			found[row.Hash] = row.TargetText
+			backfill[row.Hash] = row.TargetText
+		}
+	}
+	m.cache(ctx, backfill)
+
+	if len(found) > 0 {
+		hit := make([]string, 0, len(found))
+		for h := range found {
+			hit = append(hit, h)
+		}
+		if err := m.model.IncrHits(ctx, hit); err != nil {
+			log.Printf("[Translate] 更新翻译记忆命中次数失败 - Error: %v", err)
+		}
+	}
+
+	out := make(map[int]string, len(found))
+	for _, item := range items {
+		if text, ok := found[m.key(item.text)]; ok {
+			out[item.index] = text
+		}
+	}
+	return out
+}
+
+// save 写入新翻译的分句，同一原文只保留一条
+func (m *memoryScope) save(ctx context.Context, items []segmentItem, texts []string) {
+	rows := make([]model.TranslationMemory, 0, len(items))
+	entries := map[string]string{}
+	for _, item := range items {
+		h, text := m.key(item.text), strings.TrimSpace(texts[item.index])
+		if _, ok := entries[h]; ok || text == "" {
+			continue
+		}
+		entries[h] = text
+		rows = append(rows, model.TranslationMemory{
+			Hash:            h,
+			SourceLang:      m.sourceLang,
+			TargetLang:      m.targetLang,
+			Provider:        string(m.provider),
+			GlossaryVersion: m.glossaryVersion,
+			SourceText:      item.text,
+			TargetText:      text,
+		})
+	}
+	for start := 0; start < len(rows); start += memoryDBBatch {
+		if err := m.model.Save(ctx, rows[start:min(start+memoryDBBatch, len(rows))]); err != nil {
+			log.Printf("[Translate] 写入翻译记忆失败 - Error: %v", err)
+			return
+		}
+	}
+	m.cache(ctx, entries)
+}
+
+func (m *memoryScope) cache(ctx context.Context, entries map[string]string) {
+	if len(entries) == 0 {
+		return
+	}
+	pipe := redisx.Client().Pipeline()
+	for h, text := range entries {
+		pipe.Set(ctx, memoryCachePrefix+h, text, memoryCacheTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("[Translate] 写入翻译记忆缓存失败 - Error: %v", err)
+	}
+}
@@ -3,15 +3,19 @@ package translate
 import "context"
 
 type ITranslateSvc interface {
-	TranslateToZh(ctx context.Context, text string) (*TranslateResp, error)
+	// Translate 将 text 从 sourceLang 翻译为 targetLang；sourceLang 为空或 auto 时由模型识别
+	Translate(ctx context.Context, text, sourceLang, targetLang string) (*TranslateResp, error)
+	// TranslateSegments 分批翻译分句列表，返回的译文与输入一一对应
+	TranslateSegments(ctx context.Context, segments []string, sourceLang, targetLang string) (*SegmentsResp, error)
 }
 
 type TranslateResp struct {
-	Text      string `json:"text"`
-	CharCount int    `json:"char_count"`
+	Text       string      `json:"text"`
+	CharCount  int         `json:"char_count"`
+	Violations []Violation `json:"violations,omitempty"` // 未遵守术语表的译文
 }
 
-// Provider 枚举：当前支持 deepseek 与 bailian
+// Provider llm 提供方名称（见 internal/llm），内置 deepseek 与 bailian，可在配置中新增
 type Provider string
 
 const (
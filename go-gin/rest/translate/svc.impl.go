@@ -2,169 +2,97 @@ package translate
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"strings"
 
 	"go-gin/const/errcode"
-	"go-gin/internal/httpc"
+	"go-gin/internal/llm"
 	"go-gin/internal/traceid"
 )
 
-const (
-	ChatCompletionsURL        = "/chat/completions"
-	BailianChatCompletionsURL = "https://dashscope.aliyuncs.com/compatible-mode/v1/chat/completions"
-)
-
-type TranslateSvc struct {
-	httpc.BaseSvc
-}
-
-var deepseekCreds DeepSeekCreds
-var bailianCreds BailianCreds
-var provider Provider = ProviderDeepSeek
-
-type DeepSeekCreds struct {
-	ApiKey string
-}
-
-type BailianCreds struct {
-	ApiKey string
-}
-
-// SetDeepSeekCreds 设置DeepSeek凭据
-func SetDeepSeekCreds(creds DeepSeekCreds) {
-	deepseekCreds = creds
-	log.Printf("[Translate] DeepSeek凭据已设置 - ApiKey: %s", maskApiKey(creds.ApiKey))
-}
+// TranslateSvc 翻译服务，模型调用统一走 internal/llm 中配置的提供方
+type TranslateSvc struct{}
 
-// SetBailianCreds 设置阿里云百炼凭据
-func SetBailianCreds(creds BailianCreds) {
-	bailianCreds = creds
-	log.Printf("[Translate] Bailian凭据已设置 - ApiKey: %s", maskApiKey(creds.ApiKey))
-}
+// provider 翻译使用的 llm 提供方名称，为空时使用 llm 默认提供方
+var provider Provider
 
-// SetProvider 设置翻译提供方
+// SetProvider 设置翻译使用的 llm 提供方
 func SetProvider(p Provider) { provider = p }
 
-func NewTranslateSvc(url string) ITranslateSvc {
-	// 直接使用DeepSeek API地址
-	return &TranslateSvc{BaseSvc: *httpc.NewBaseSvc("https://api.deepseek.com")}
+func NewTranslateSvc() ITranslateSvc {
+	return &TranslateSvc{}
 }
 
-func (s *TranslateSvc) TranslateToZh(ctx context.Context, text string) (resp *TranslateResp, err error) {
-	log.Printf("[Translate] 开始翻译 - 文本长度: %d", len(text))
-
-	// 检查API Key（根据 provider）
-	switch provider {
-	case ProviderBailian:
-		if bailianCreds.ApiKey == "" {
-			log.Printf("[Translate] Bailian API Key未配置")
-			return nil, errcode.ErrTranslateUp
-		}
-	default:
-		if deepseekCreds.ApiKey == "" {
-			log.Printf("[Translate] DeepSeek API Key未配置")
-			return nil, errcode.ErrTranslateUp
-		}
-	}
-
-	requestId := traceid.New()
-
-	// 构建OpenAI兼容的请求体
-	model := "deepseek-chat"
-	if provider == ProviderBailian {
-		model = "qwen-turbo-latest"
-	}
-	requestBody := map[string]any{
-		"model": model,
-		"messages": []map[string]string{
-			{
-				"role":    "system",
-				"content": "你是一个专业的翻译助手。请将用户提供的英文文本翻译成中文，保持原意和语言风格。请直接返回翻译后的中文文本，不要添加任何解释或前缀，不要包含任何英文内容。",
-			},
-			{
-				"role":    "user",
-				"content": fmt.Sprintf("请翻译以下英文文本为中文：\n\n%s", text),
-			},
-		},
-		"stream": false,
-	}
+func (s *TranslateSvc) Translate(ctx context.Context, text, sourceLang, targetLang string) (resp *TranslateResp, err error) {
+	log.Printf("[Translate] 开始翻译 - 文本长度: %d, %s -> %s", len(text), NormalizeLang(sourceLang), NormalizeLang(targetLang))
 
-	var result DeepSeekAPIResponse
-	req := s.Client().NewRequest().SetContext(ctx)
-	if provider == ProviderBailian {
-		// 直连百炼完整URL（不依赖 BaseURL）
-		req = req.POST(BailianChatCompletionsURL).SetHeaders(map[string]string{
-			"Authorization": fmt.Sprintf("Bearer %s", bailianCreds.ApiKey),
-			"Content-Type":  "application/json",
-		})
-	} else {
-		// 使用 DeepSeek BaseURL + 相对路径
-		req = req.POST(ChatCompletionsURL).SetHeaders(map[string]string{
-			"Authorization": fmt.Sprintf("Bearer %s", deepseekCreds.ApiKey),
-			"Content-Type":  "application/json",
-		})
+	if !IsSupported(targetLang) {
+		log.Printf("[Translate] 不支持的目标语言: %s", targetLang)
+		return nil, errcode.ErrLangNotSupported
 	}
-	err = req.SetBody(requestBody).SetResult(&result).Exec()
 
+	requestId := traceid.New()
+	terms := MatchTerms(glossaryFrom(ctx), text)
+	content, usageTokens, err := s.chat(ctx, requestId, withGlossaryPrompt(buildSystemPrompt(sourceLang, targetLang), terms), buildUserPrompt(text, sourceLang, targetLang))
 	if err != nil {
-		log.Printf("[Translate] 请求失败 - RequestId: %s, Error: %v", requestId, err)
-		return nil, errcode.ErrTranslateUp
-	}
-
-	// 检查响应
-	if !result.Valid() {
-		log.Printf("[Translate] 响应格式无效 - RequestId: %s, Response: %+v", requestId, result)
-		return nil, errcode.ErrTranslateUp
-	}
-
-	if !result.IsSuccess() {
-		log.Printf("[Translate] API返回失败 - RequestId: %s, Error: %s", requestId, result.Msg())
-		return nil, errcode.ErrTranslateUp
+		return nil, err
 	}
 
 	// 提取翻译结果
-	translatedText := ""
-	if len(result.Choices) > 0 && result.Choices[0].Message.Content != "" {
-		content := strings.TrimSpace(result.Choices[0].Message.Content)
-
-		// 移除常见的前后缀和格式标记
-		content = strings.TrimPrefix(content, "翻译结果：")
-		content = strings.TrimPrefix(content, "翻译为：")
-		content = strings.TrimPrefix(content, "中文翻译：")
-		content = strings.TrimPrefix(content, "以下是翻译：")
-
-		// 移除引号包围
-		if strings.HasPrefix(content, `"`) && strings.HasSuffix(content, `"`) {
-			content = content[1 : len(content)-1]
-		}
-
-		translatedText = strings.TrimSpace(content)
-
-		log.Printf("[Translate] 提取翻译文本成功 - RequestId: %s, 长度: %d", requestId, len(translatedText))
-	}
-
+	translatedText := cleanContent(content)
 	if translatedText == "" {
-		log.Printf("[Translate] 提取翻译文本失败 - RequestId: %s, Content: %s", requestId, result.Choices[0].Message.Content)
+		log.Printf("[Translate] 提取翻译文本失败 - RequestId: %s, Content: %s", requestId, content)
 		return nil, errcode.ErrTranslateUp
 	}
+	log.Printf("[Translate] 提取翻译文本成功 - RequestId: %s, 长度: %d", requestId, len(translatedText))
 
 	resp = &TranslateResp{
-		Text:      translatedText,
-		CharCount: len([]rune(translatedText)), // 使用rune计算字符数
+		Text:       translatedText,
+		CharCount:  len([]rune(translatedText)), // 使用rune计算字符数
+		Violations: CheckGlossary(terms, -1, text, translatedText),
 	}
 
 	log.Printf("[Translate] 翻译成功 - RequestId: %s, CharCount: %d, UsageTokens: %d",
-		requestId, resp.CharCount, result.Usage.TotalTokens)
+		requestId, resp.CharCount, usageTokens)
 	log.Printf("[Translate] 翻译文本预览: %.200s...", resp.Text)
 
 	return resp, nil
 }
 
-func maskApiKey(key string) string {
-	if len(key) <= 8 {
-		return "***"
+// chat 调用翻译提供方，返回模型输出与消耗的 token 数
+func (s *TranslateSvc) chat(ctx context.Context, requestId, systemPrompt, userPrompt string) (string, int, error) {
+	client, err := llm.Get(string(provider))
+	if err != nil {
+		log.Printf("[Translate] 翻译提供方未配置 - RequestId: %s, Provider: %s", requestId, provider)
+		return "", 0, errcode.ErrTranslateUp
+	}
+	resp, err := client.Chat(ctx, llm.Request{Messages: []llm.Message{llm.System(systemPrompt), llm.User(userPrompt)}})
+	if err != nil {
+		log.Printf("[Translate] 请求失败 - RequestId: %s, Provider: %s, Error: %v", requestId, client.Name(), err)
+		return "", 0, errcode.ErrTranslateUp
+	}
+	if resp.Content == "" {
+		log.Printf("[Translate] 模型返回为空 - RequestId: %s, Provider: %s", requestId, client.Name())
+		return "", 0, errcode.ErrTranslateUp
+	}
+	if resp.FinishReason == "length" {
+		log.Printf("[Translate] 输出被截断 - RequestId: %s", requestId)
+	}
+	return resp.Content, resp.Usage.TotalTokens, nil
+}
+
+// cleanContent 移除模型输出中常见的前后缀和格式标记
+func cleanContent(content string) string {
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "翻译结果：")
+	content = strings.TrimPrefix(content, "翻译为：")
+	content = strings.TrimPrefix(content, "中文翻译：")
+	content = strings.TrimPrefix(content, "以下是翻译：")
+	content = strings.TrimPrefix(content, "Translation:")
+
+	// 移除引号包围
+	if len(content) >= 2 && strings.HasPrefix(content, `"`) && strings.HasSuffix(content, `"`) {
+		content = content[1 : len(content)-1]
 	}
-	return key[:4] + "***" + key[len(key)-4:]
+	return strings.TrimSpace(content)
 }
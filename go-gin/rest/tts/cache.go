@@ -0,0 +1,25 @@
+package tts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// AudioParamsKey 影响合成音频的参数摘要（编码、采样率与韵律），参与分句缓存键，参数变化后旧缓存自然失效
+func AudioParamsKey(prosody Prosody) string {
+	key := fmt.Sprintf("%s/%d", AudioFormat, SampleRate)
+	if k := prosody.Key(); k != "" {
+		key += "/" + k
+	}
+	return key
+}
+
+// SentenceKey 分句音频缓存键：规范化分句、音色、资源与音频参数都相同时复用音频
+func SentenceKey(sentence, speaker, resourceId string, prosody Prosody) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		NormalizeSentence(sentence), speaker, resourceId, AudioParamsKey(prosody),
+	}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
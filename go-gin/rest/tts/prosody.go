@@ -0,0 +1,66 @@
+package tts
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Prosody 韵律参数，零值表示使用音色默认
+type Prosody struct {
+	SpeedRatio    float64 `json:"speed_ratio,omitempty"`    // 语速倍率 0.5~2.0
+	LoudnessRatio float64 `json:"loudness_ratio,omitempty"` // 音量倍率 0.5~2.0
+	Pitch         int     `json:"pitch,omitempty"`          // 音调，单位半音 -12~12
+	Emotion       string  `json:"emotion,omitempty"`        // 情感（如 happy、sad），仅多情感音色支持
+	EmotionScale  int     `json:"emotion_scale,omitempty"`  // 情感强度 1~5，需同时指定 emotion
+}
+
+// Normalize 将等同默认值的参数归零：倍率 1 视为未设置，情感统一小写，未指定情感时忽略强度
+func (p Prosody) Normalize() Prosody {
+	if math.Abs(p.SpeedRatio-1) < 0.005 {
+		p.SpeedRatio = 0
+	}
+	if math.Abs(p.LoudnessRatio-1) < 0.005 {
+		p.LoudnessRatio = 0
+	}
+	p.Emotion = strings.ToLower(strings.TrimSpace(p.Emotion))
+	if p.Emotion == "" {
+		p.EmotionScale = 0
+	}
+	return p
+}
+
+// Key 规范化后的参数摘要，用于幂等与缓存键；全部为默认值时返回空串（与未支持韵律前的键保持一致）
+func (p Prosody) Key() string {
+	p = p.Normalize()
+	if p == (Prosody{}) {
+		return ""
+	}
+	return fmt.Sprintf("speed=%.2f,loudness=%.2f,pitch=%d,emotion=%s,scale=%d",
+		p.SpeedRatio, p.LoudnessRatio, p.Pitch, p.Emotion, p.EmotionScale)
+}
+
+// apply 写入上游请求：语速/音量倍率换算为 speech_rate/loudness_rate（-50~100，0 为正常），
+// 情感写入 audio_params，音调写入 additions.post_process
+func (p Prosody) apply(audioParams, additions map[string]any) {
+	p = p.Normalize()
+	if p.SpeedRatio > 0 {
+		audioParams["speech_rate"] = ratioToRate(p.SpeedRatio)
+	}
+	if p.LoudnessRatio > 0 {
+		audioParams["loudness_rate"] = ratioToRate(p.LoudnessRatio)
+	}
+	if p.Emotion != "" {
+		audioParams["emotion"] = p.Emotion
+		if p.EmotionScale > 0 {
+			audioParams["emotion_scale"] = p.EmotionScale
+		}
+	}
+	if p.Pitch != 0 {
+		additions["post_process"] = map[string]any{"pitch": p.Pitch}
+	}
+}
+
+func ratioToRate(ratio float64) int {
+	return min(max(int(math.Round((ratio-1)*100)), -50), 100)
+}
@@ -0,0 +1,149 @@
+package tts
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// sentenceEnds 句末标点，优先在这些位置切分
+	sentenceEnds = ".!?。！？;；\n"
+	// clauseEnds 句内停顿，单句超长时在这些位置切分
+	clauseEnds = ",，、:：)）]】》”’"
+)
+
+// SplitText 将长文本切分为不超过 maxRunes 的片段：尽量合并整句，单句超长时在句内停顿处切分，
+// 仍然超长时在空白处切分，最后才硬切；片段保持原文顺序，仅去除片段首尾空白
+func SplitText(text string, maxRunes int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if maxRunes <= 0 || len([]rune(text)) <= maxRunes {
+		return []string{text}
+	}
+	var chunks []string
+	var cur []rune
+	flush := func() {
+		if s := strings.TrimSpace(string(cur)); s != "" {
+			chunks = append(chunks, s)
+		}
+		cur = cur[:0]
+	}
+	for _, sentence := range splitAfter([]rune(text), sentenceEnds) {
+		if len(cur)+len(sentence) <= maxRunes {
+			cur = append(cur, sentence...)
+			continue
+		}
+		flush()
+		if len(sentence) <= maxRunes {
+			cur = append(cur, sentence...)
+			continue
+		}
+		for _, piece := range splitLong(sentence, maxRunes) {
+			if len(cur)+len(piece) > maxRunes {
+				flush()
+			}
+			cur = append(cur, piece...)
+		}
+	}
+	flush()
+	return chunks
+}
+
+// splitAfter 在 seps 中的字符之后切分，连续的分隔符归入同一段
+func splitAfter(runes []rune, seps string) [][]rune {
+	var parts [][]rune
+	start := 0
+	for i, r := range runes {
+		if !strings.ContainsRune(seps, r) {
+			continue
+		}
+		if i+1 < len(runes) && strings.ContainsRune(seps, runes[i+1]) {
+			continue
+		}
+		// 半角句点后紧跟非空白（如小数、缩写、网址）时不切分
+		if r == '.' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			continue
+		}
+		parts = append(parts, runes[start:i+1])
+		start = i + 1
+	}
+	if start < len(runes) {
+		parts = append(parts, runes[start:])
+	}
+	return parts
+}
+
+// splitLong 切分单个超长句子：句内停顿 → 空白 → 硬切
+func splitLong(sentence []rune, maxRunes int) [][]rune {
+	var out [][]rune
+	for _, clause := range splitAfter(sentence, clauseEnds) {
+		if len(clause) <= maxRunes {
+			out = append(out, clause)
+			continue
+		}
+		for len(clause) > maxRunes {
+			cut := maxRunes
+			for i := maxRunes; i > maxRunes/2; i-- {
+				if unicode.IsSpace(clause[i-1]) {
+					cut = i
+					break
+				}
+			}
+			out = append(out, clause[:cut])
+			clause = clause[cut:]
+		}
+		if len(clause) > 0 {
+			out = append(out, clause)
+		}
+	}
+	return out
+}
+
+// SplitSentences 将文本切分为逐句的片段（不合并短句），单句超过 maxRunes 时按 SplitText 的规则切分；
+// 片段经 NormalizeSentence 规范化，没有文字与数字的片段（如单独的标点、省略号）并入前一句
+func SplitSentences(text string, maxRunes int) []string {
+	var out []string
+	pending := ""
+	for _, sentence := range splitAfter([]rune(strings.TrimSpace(text)), sentenceEnds) {
+		pieces := [][]rune{sentence}
+		if maxRunes > 0 && len(sentence) > maxRunes {
+			pieces = splitLong(sentence, maxRunes)
+		}
+		for _, piece := range pieces {
+			s := NormalizeSentence(string(piece))
+			if s == "" {
+				continue
+			}
+			if !hasWord(s) {
+				if len(out) > 0 {
+					out[len(out)-1] += s
+				} else {
+					pending += s
+				}
+				continue
+			}
+			out = append(out, pending+s)
+			pending = ""
+		}
+	}
+	if pending != "" {
+		out = append(out, pending)
+	}
+	return out
+}
+
+// NormalizeSentence 规范化分句：去除首尾空白，连续空白合并为一个空格
+func NormalizeSentence(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func hasWord(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,7 +5,9 @@ import "context"
 type ITTSSvc interface {
 	Synthesize(ctx context.Context, text, speaker string) (*TTSResp, error)
 	// SynthesizeWithResource performs TTS using the specified resource id with no fallback logic
-	SynthesizeWithResource(ctx context.Context, text, speaker, resourceId string) (*TTSResp, error)
+	SynthesizeWithResource(ctx context.Context, text, speaker, resourceId string, prosody Prosody) (*TTSResp, error)
+	// SynthesizeStream 同 SynthesizeWithResource，每收到一段音频即回调 onAudio，回调返回错误时中止合成
+	SynthesizeStream(ctx context.Context, text, speaker, resourceId string, prosody Prosody, onAudio func(chunk []byte) error) (*TTSResp, error)
 }
 
 type TTSResp struct {
@@ -15,4 +17,8 @@ type TTSResp struct {
 	UsedSpeaker    string `json:"-"`
 	UsedResourceId string `json:"-"`
 	RequestId      string `json:"-"`
+	// Sentences 逐句、逐词的时间戳（毫秒，相对音频起点），上游未返回时为空
+	Sentences []TTSSentence `json:"-"`
+	// DurationMs 音频时长（毫秒），由合成逻辑解析音频后填充
+	DurationMs int64 `json:"-"`
 }
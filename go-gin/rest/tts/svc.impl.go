@@ -9,6 +9,7 @@ import (
 	"go-gin/internal/httpc"
 	"io"
 	"log"
+	"math"
 	"strings"
 
 	"github.com/google/uuid"
@@ -18,6 +19,10 @@ const (
 	SynthesizeURL   = "/api/v3/tts/unidirectional"
 	DefaultSpeaker  = "zh_female_shuangkuaisisi_moon_bigtts"
 	DefaultResource = "volc.service_type.10029"
+	// SampleRate 合成音频的采样率，长文本分段合成后按此采样率拼接
+	SampleRate = 24000
+	// AudioFormat 合成音频的编码格式
+	AudioFormat = "mp3"
 )
 
 type TTSSvc struct {
@@ -51,16 +56,21 @@ type ttsStreamResp struct {
 
 func (s *TTSSvc) Synthesize(ctx context.Context, text, speaker string) (resp *TTSResp, err error) {
 	// 第一次按用户入参尝试；失败(资源不匹配)则降级到默认普通音色+资源
-	if r, e := s.doOnce(ctx, text, speaker, pickResourceBySpeaker(speaker)); e == nil {
+	if r, e := s.doOnce(ctx, text, speaker, pickResourceBySpeaker(speaker), Prosody{}, nil); e == nil {
 		return r, nil
 	}
 	// fallback
-	return s.doOnce(ctx, text, DefaultSpeaker, defaultResourceId())
+	return s.doOnce(ctx, text, DefaultSpeaker, defaultResourceId(), Prosody{}, nil)
 }
 
 // SynthesizeWithResource 使用明确的资源ID进行合成，不做任何回退
-func (s *TTSSvc) SynthesizeWithResource(ctx context.Context, text, speaker, resourceId string) (*TTSResp, error) {
-	return s.doOnce(ctx, text, speaker, resourceId)
+func (s *TTSSvc) SynthesizeWithResource(ctx context.Context, text, speaker, resourceId string, prosody Prosody) (*TTSResp, error) {
+	return s.doOnce(ctx, text, speaker, resourceId, prosody, nil)
+}
+
+// SynthesizeStream 使用明确的资源ID进行流式合成，不做任何回退
+func (s *TTSSvc) SynthesizeStream(ctx context.Context, text, speaker, resourceId string, prosody Prosody, onAudio func(chunk []byte) error) (*TTSResp, error) {
+	return s.doOnce(ctx, text, speaker, resourceId, prosody, onAudio)
 }
 
 func pickResourceBySpeaker(speaker string) string {
@@ -93,7 +103,8 @@ func detectExplicitLanguage(speaker string) string {
 	return "zh,en,ja,es-mx,id,pt-br,de,fr"
 }
 
-func (s *TTSSvc) doOnce(ctx context.Context, text, speaker, resourceId string) (*TTSResp, error) {
+// doOnce 调用上游并逐行读取音频帧；onAudio 不为空时每解码一帧即回调
+func (s *TTSSvc) doOnce(ctx context.Context, text, speaker, resourceId string, prosody Prosody, onAudio func(chunk []byte) error) (*TTSResp, error) {
 	// 验证凭据
 	if volcCreds.AppId == "" || volcCreds.AccessKey == "" {
 		log.Printf("TTS ERROR: Missing Volc credentials")
@@ -107,20 +118,23 @@ func (s *TTSSvc) doOnce(ctx context.Context, text, speaker, resourceId string) (
 		"disable_markdown_filter":  true,
 	}
 
+	audioParams := map[string]any{
+		"format":           AudioFormat,
+		"sample_rate":      SampleRate,
+		"enable_timestamp": true,
+	}
+	prosody.apply(audioParams, additions)
+
 	// additions 必须是 JSON 字符串，不是对象
 	additionsJSON, _ := json.Marshal(additions)
 
 	payload := map[string]any{
 		"user": map[string]any{"uid": volcCreds.AppId},
 		"req_params": map[string]any{
-			"text":    text,
-			"speaker": speaker,
-			"audio_params": map[string]any{
-				"format":           "mp3",
-				"sample_rate":      24000,
-				"enable_timestamp": true,
-			},
-			"additions": string(additionsJSON),
+			"text":         text,
+			"speaker":      speaker,
+			"audio_params": audioParams,
+			"additions":    string(additionsJSON),
 		},
 	}
 	endpoint := SynthesizeURL
@@ -148,6 +162,7 @@ func (s *TTSSvc) doOnce(ctx context.Context, text, speaker, resourceId string) (
 	defer func() { _ = res.RawBody().Close() }()
 	reader := bufio.NewReader(res.RawBody())
 	var audio []byte
+	var sentences []TTSSentence
 	var sawMismatch bool
 	for {
 		line, err := reader.ReadBytes('\n')
@@ -168,9 +183,17 @@ func (s *TTSSvc) doOnce(ctx context.Context, text, speaker, resourceId string) (
 		if frame.Data != "" {
 			chunk, _ := base64.StdEncoding.DecodeString(frame.Data)
 			audio = append(audio, chunk...)
+			if onAudio != nil && len(chunk) > 0 {
+				if err := onAudio(chunk); err != nil {
+					log.Printf("TTS stream aborted: %v (speaker=%s, resource=%s)", err, speaker, resourceId)
+					return nil, err
+				}
+			}
 		}
 		if frame.Sentence != nil && frame.Sentence.Text != "" {
-			log.Printf("TTS sentence: %s (words: %d)", frame.Sentence.Text, len(frame.Sentence.Words))
+			if sentence, ok := frame.timing(); ok {
+				sentences = append(sentences, sentence)
+			}
 		}
 		if frame.Code == 20000000 {
 			break
@@ -189,6 +212,28 @@ func (s *TTSSvc) doOnce(ctx context.Context, text, speaker, resourceId string) (
 		log.Printf("TTS empty audio: speaker=%s, resource=%s", speaker, resourceId)
 		return nil, errcode.ErrTTSUpstream
 	}
-	log.Printf("TTS success: speaker=%s, resource=%s, bytes=%d", speaker, resourceId, len(audio))
-	return &TTSResp{Audio: audio, Size: len(audio), UsedSpeaker: speaker, UsedResourceId: resourceId, RequestId: reqID}, nil
+	log.Printf("TTS success: speaker=%s, resource=%s, bytes=%d, sentences=%d", speaker, resourceId, len(audio), len(sentences))
+	return &TTSResp{Audio: audio, Size: len(audio), UsedSpeaker: speaker, UsedResourceId: resourceId, RequestId: reqID, Sentences: sentences}, nil
+}
+
+// timing 将上游的句子帧（词的起止时间单位为秒）转换为毫秒时间戳，没有词级时间时返回 false
+func (f *ttsStreamResp) timing() (TTSSentence, bool) {
+	sentence := TTSSentence{Text: f.Sentence.Text}
+	for _, w := range f.Sentence.Words {
+		word := TTSWord{Word: w.Word, StartMs: secondsToMs(w.StartTime), EndMs: secondsToMs(w.EndTime)}
+		if word.EndMs < word.StartMs {
+			word.EndMs = word.StartMs
+		}
+		sentence.Words = append(sentence.Words, word)
+	}
+	if len(sentence.Words) == 0 {
+		return sentence, false
+	}
+	sentence.StartMs = sentence.Words[0].StartMs
+	sentence.EndMs = sentence.Words[len(sentence.Words)-1].EndMs
+	return sentence, true
+}
+
+func secondsToMs(s float64) int64 {
+	return int64(math.Round(s * 1000))
 }
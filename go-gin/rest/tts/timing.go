@@ -0,0 +1,53 @@
+package tts
+
+import "go-gin/util/subtitle"
+
+// TTSWord 一个词（中文为一个字）的朗读区间
+type TTSWord struct {
+	Word    string `json:"word"`
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+}
+
+// TTSSentence 一句的朗读区间，Words 为空时只有句级时间
+type TTSSentence struct {
+	Text    string    `json:"text"`
+	StartMs int64     `json:"start_ms"`
+	EndMs   int64     `json:"end_ms"`
+	Words   []TTSWord `json:"words,omitempty"`
+}
+
+// ShiftSentences 将时间戳整体平移 offsetMs（拼接多段音频时换算为整段时间），返回新切片
+func ShiftSentences(sentences []TTSSentence, offsetMs int64) []TTSSentence {
+	out := make([]TTSSentence, len(sentences))
+	for i, s := range sentences {
+		s.StartMs += offsetMs
+		s.EndMs += offsetMs
+		words := make([]TTSWord, len(s.Words))
+		for j, w := range s.Words {
+			w.StartMs += offsetMs
+			w.EndMs += offsetMs
+			words[j] = w
+		}
+		if len(words) > 0 {
+			s.Words = words
+		}
+		out[i] = s
+	}
+	return out
+}
+
+// TimingCues 将时间戳转换为字幕：words 为 true 时每个词一条（逐词高亮），否则每句一条
+func TimingCues(sentences []TTSSentence, words bool) []subtitle.Cue {
+	var cues []subtitle.Cue
+	for _, s := range sentences {
+		if !words || len(s.Words) == 0 {
+			cues = append(cues, subtitle.Cue{StartMs: s.StartMs, EndMs: s.EndMs, Text: s.Text})
+			continue
+		}
+		for _, w := range s.Words {
+			cues = append(cues, subtitle.Cue{StartMs: w.StartMs, EndMs: w.EndMs, Text: w.Word})
+		}
+	}
+	return cues
+}
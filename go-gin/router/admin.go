@@ -3,9 +3,15 @@ package router
 import (
 	"go-gin/controller"
 	"go-gin/internal/httpx"
+	"go-gin/middleware"
 )
 
 // RegisterAdminRoutes 临时管理接口（内测期间）
 func RegisterAdminRoutes(r *httpx.RouterGroup) {
 	r.POST("/admin/seed_quota", controller.AdminController.SeedQuota)
+	// 流水线查看与失败重驱：包含所有用户的数据并会触发计费调用，需管理令牌
+	g := r.Group("")
+	g.Before(middleware.AdminCheck()).GET("/admin/pipelines", controller.AdminController.Pipelines)
+	g.Before(middleware.AdminCheck()).GET("/admin/pipelines/:id", controller.AdminController.Pipeline)
+	g.Before(middleware.AdminCheck()).POST("/admin/pipelines/:id/redrive", controller.AdminController.RedrivePipeline)
 }
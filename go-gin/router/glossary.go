@@ -0,0 +1,25 @@
+package router
+
+import (
+	"go-gin/controller"
+	"go-gin/internal/httpx"
+	"go-gin/middleware"
+)
+
+// RegisterGlossaryRoutes 注册术语表相关路由
+func RegisterGlossaryRoutes(r *httpx.RouterGroup) {
+	g := r.Group("")
+	g.Before(middleware.TokenCheck()).GET("/glossary", controller.GlossaryController.List)
+	g.Before(middleware.TokenCheck()).POST("/glossary", controller.GlossaryController.Create)
+	g.Before(middleware.TokenCheck()).POST("/glossary/import", controller.GlossaryController.Import)
+	g.Before(middleware.TokenCheck()).PUT("/glossary/:id", controller.GlossaryController.Update)
+	g.Before(middleware.TokenCheck()).DELETE("/glossary/:id", controller.GlossaryController.Delete)
+	// 团队共享：所有者邀请其他账号，对方接受后共享生效
+	g.Before(middleware.TokenCheck()).GET("/glossary/members", controller.GlossaryController.Members)
+	g.Before(middleware.TokenCheck()).POST("/glossary/members", controller.GlossaryController.AddMember)
+	g.Before(middleware.TokenCheck()).DELETE("/glossary/members/:identity", controller.GlossaryController.RemoveMember)
+	g.Before(middleware.TokenCheck()).POST("/glossary/invites/:owner/accept", controller.GlossaryController.AcceptInvite)
+	g.Before(middleware.TokenCheck()).DELETE("/glossary/invites/:owner", controller.GlossaryController.LeaveShare)
+	// 术语译法校验
+	g.Before(middleware.TokenCheck()).GET("/yt/transcripts/:id/glossary", controller.GlossaryController.Check)
+}
@@ -10,4 +10,5 @@ import (
 func RegisterHistoryRoutes(r *httpx.RouterGroup) {
 	g := r.Group("")
 	g.Before(middleware.TokenCheck()).GET("/history/tts", controller.HistoryController.ListTTS)
+	g.Before(middleware.TokenCheck()).GET("/history/tts/:id/timings", controller.HistoryController.TTSTimings)
 }
@@ -12,8 +12,10 @@ func Init(route *httpx.Engine) {
 	api := route.Group("/api")
 	RegisterApiRoutes(api)
 	RegisterYtRoutes(api)
+	RegisterGlossaryRoutes(api)
 	RegisterTTSRoutes(api)
 	RegisterHistoryRoutes(api)
+	RegisterSearchRoutes(api)
 	RegisterAccountRoutes(api)
 	RegisterAuthRoutes(api)
 
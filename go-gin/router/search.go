@@ -0,0 +1,13 @@
+package router
+
+import (
+	"go-gin/controller"
+	"go-gin/internal/httpx"
+	"go-gin/middleware"
+)
+
+// RegisterSearchRoutes 注册全文搜索路由
+func RegisterSearchRoutes(r *httpx.RouterGroup) {
+	g := r.Group("")
+	g.Before(middleware.TokenCheck()).GET("/search", controller.SearchController.Search)
+}
@@ -10,4 +10,9 @@ import (
 func RegisterTTSRoutes(r *httpx.RouterGroup) {
 	g := r.Group("")
 	g.Before(middleware.TokenCheck()).POST("/tts/synthesize", controller.TTSController.Synthesize)
+	// 长文本异步合成进度
+	g.Before(middleware.TokenCheck()).GET("/tts/jobs/:id", controller.TTSController.Job)
+	// 流式合成：边合成边输出 audio/mpeg 或 SSE
+	g.Before(middleware.TokenCheck()).GET("/tts/stream", controller.TTSController.Stream)
+	g.Before(middleware.TokenCheck()).POST("/tts/stream", controller.TTSController.Stream)
 }
@@ -12,4 +12,23 @@ func RegisterYtRoutes(r *httpx.RouterGroup) {
 	g := r.Group("")
 	g.Before(middleware.TokenCheck()).POST("/yt/info", controller.YtController.Info)
 	g.Before(middleware.TokenCheck()).POST("/yt/text", controller.YtController.Text)
+	// 异步转录：提交任务后轮询状态
+	g.Before(middleware.TokenCheck()).POST("/yt/jobs", controller.YtController.SubmitJob)
+	g.Before(middleware.TokenCheck()).GET("/yt/jobs/:id", controller.YtController.Job)
+	// 用户编辑与修订历史
+	g.Before(middleware.TokenCheck()).PUT("/yt/transcripts/:id", controller.YtController.Edit)
+	g.Before(middleware.TokenCheck()).GET("/yt/transcripts/:id/revisions", controller.YtController.Revisions)
+	g.Before(middleware.TokenCheck()).GET("/yt/transcripts/:id/revisions/diff", controller.YtController.DiffRevisions)
+	g.Before(middleware.TokenCheck()).POST("/yt/transcripts/:id/revisions/:rev/restore", controller.YtController.RestoreRevision)
+	g.Before(middleware.TokenCheck()).GET("/yt/transcripts/:id/segments", controller.YtController.Segments)
+	g.Before(middleware.TokenCheck()).GET("/yt/transcripts/:id/subtitles", controller.YtController.Subtitles)
+	g.Before(middleware.TokenCheck()).GET("/yt/transcripts/:id/review", controller.YtController.Review)
+	g.Before(middleware.TokenCheck()).GET("/yt/transcripts/:id/speakers", controller.YtController.Speakers)
+	g.Before(middleware.TokenCheck()).PUT("/yt/transcripts/:id/speakers", controller.YtController.RenameSpeakers)
+	// 摘要、要点与章节（单独计量）
+	g.Before(middleware.TokenCheck()).POST("/yt/transcripts/:id/summarize", controller.YtController.Summarize)
+	// 基于转录的问答，历史按用户保存（与摘要共用 token 额度）
+	g.Before(middleware.TokenCheck()).POST("/yt/transcripts/:id/ask", controller.YtController.Ask)
+	g.Before(middleware.TokenCheck()).GET("/yt/transcripts/:id/questions", controller.YtController.Questions)
+	g.Before(middleware.TokenCheck()).DELETE("/yt/transcripts/:id/questions", controller.YtController.ClearQuestions)
 }
@@ -7,4 +7,7 @@ import (
 func Init() {
 	queue.AddHandler(NewSampleTaskHandler())
 	queue.AddHandler(NewSampleBTaskHandler())
+	queue.AddHandler(NewTranscriptTaskHandler())
+	queue.AddHandler(NewPipelineRedriveTaskHandler())
+	queue.AddHandler(NewTTSTaskHandler())
 }
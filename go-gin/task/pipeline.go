@@ -0,0 +1,28 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"go-gin/internal/queue"
+	"go-gin/logic"
+)
+
+const TypePipelineRedriveTask = "pipeline_redrive"
+
+type PipelineRedrivePayload struct {
+	RunId int64 `json:"run_id"`
+}
+
+func NewPipelineRedriveTask(runId int64) *queue.Task {
+	return queue.NewTask(TypePipelineRedriveTask, PipelineRedrivePayload{RunId: runId})
+}
+
+func NewPipelineRedriveTaskHandler() *queue.TaskHandler {
+	return queue.NewTaskHandler(TypePipelineRedriveTask, func(ctx context.Context, data []byte) error {
+		var p PipelineRedrivePayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return err
+		}
+		return logic.NewPipelineLogic().Redrive(ctx, p.RunId)
+	})
+}
@@ -0,0 +1,28 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"go-gin/internal/queue"
+	"go-gin/logic"
+)
+
+const TypeTranscriptTask = "transcript"
+
+type TranscriptTaskPayload struct {
+	JobId string `json:"job_id"`
+}
+
+func NewTranscriptTask(jobId string) *queue.Task {
+	return queue.NewTask(TypeTranscriptTask, TranscriptTaskPayload{JobId: jobId})
+}
+
+func NewTranscriptTaskHandler() *queue.TaskHandler {
+	return queue.NewTaskHandler(TypeTranscriptTask, func(ctx context.Context, data []byte) error {
+		var p TranscriptTaskPayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return err
+		}
+		return logic.NewTranscriptJobLogic().Run(ctx, p.JobId)
+	})
+}
@@ -0,0 +1,28 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"go-gin/internal/queue"
+	"go-gin/logic"
+)
+
+const TypeTTSTask = "tts_long"
+
+type TTSTaskPayload struct {
+	JobId string `json:"job_id"`
+}
+
+func NewTTSTask(jobId string) *queue.Task {
+	return queue.NewTask(TypeTTSTask, TTSTaskPayload{JobId: jobId})
+}
+
+func NewTTSTaskHandler() *queue.TaskHandler {
+	return queue.NewTaskHandler(TypeTTSTask, func(ctx context.Context, data []byte) error {
+		var p TTSTaskPayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return err
+		}
+		return logic.NewTTSJobLogic().Run(ctx, p.JobId)
+	})
+}
@@ -0,0 +1,184 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go-gin/rest/asr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeASRServer 本地模拟火山极速版、火山标准版、OpenAI 兼容转写接口与音频下载
+func fakeASRServer(t *testing.T) *httptest.Server {
+	volcResult := func(lang string) map[string]any {
+		return map[string]any{
+			"audio_info": map[string]any{"duration": 4000},
+			"result": map[string]any{
+				"text": "hello world. this is a test.",
+				"utterances": []map[string]any{
+					{"text": "hello world.", "start_time": 0, "end_time": 1500, "additions": map[string]any{"speaker": "1"}, "words": []map[string]any{
+						{"text": "hello", "start_time": 0, "end_time": 700, "confidence": 90},
+						{"text": "world", "start_time": 700, "end_time": 1500, "confidence": 80},
+					}},
+					{"text": "this is a test.", "start_time": 1800, "end_time": 4000, "additions": map[string]any{"speaker": "2"}},
+				},
+				"additions": map[string]any{"lid_lang": lang},
+			},
+		}
+	}
+	var queries atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/audio.mp3", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ID3fake-audio"))
+	})
+	mux.HandleFunc(asr.FlashURL, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Audio   map[string]string `json:"audio"`
+			Request map[string]any    `json:"request"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.NotEmpty(t, r.Header.Get("X-Api-Request-Id"))
+		assert.Equal(t, true, body.Request["enable_speaker_info"])
+		if body.Audio["url"] == "" && body.Audio["data"] == "" {
+			w.Write([]byte(`{"header":{"code":45000001,"message":"missing audio"}}`))
+			return
+		}
+		res := volcResult("en-US")
+		res["header"] = map[string]any{"code": 20000000}
+		json.NewEncoder(w).Encode(res)
+	})
+	mux.HandleFunc(asr.SubmitURL, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Audio map[string]string `json:"audio"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.NotEmpty(t, body.Audio["url"])
+		w.Header().Set("X-Api-Status-Code", "20000000")
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc(asr.QueryURL, func(w http.ResponseWriter, r *http.Request) {
+		// 第一次查询返回处理中，之后返回结果
+		if queries.Add(1) == 1 {
+			w.Header().Set("X-Api-Status-Code", "20000001")
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.Header().Set("X-Api-Status-Code", "20000000")
+		json.NewEncoder(w).Encode(volcResult("en-US"))
+	})
+	mux.HandleFunc(asr.TranscriptionsURL, func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "verbose_json", r.FormValue("response_format"))
+		assert.ElementsMatch(t, []string{"segment", "word"}, r.MultipartForm.Value["timestamp_granularities[]"])
+		f, _, err := r.FormFile("file")
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"missing file"}}`))
+			return
+		}
+		f.Close()
+		lang := "english"
+		if l := r.FormValue("language"); l != "" {
+			lang = l
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"text":     "hello world. this is a test.",
+			"language": lang,
+			"duration": 4.0,
+			"segments": []map[string]any{
+				{"start": 0.0, "end": 1.5, "text": " hello world.", "avg_logprob": -0.1, "speaker": "SPEAKER_03"},
+				{"start": 1.8, "end": 4.0, "text": " this is a test.", "avg_logprob": -0.2, "speaker": "SPEAKER_01"},
+			},
+			"words": []map[string]any{
+				{"word": "hello", "start": 0.0, "end": 0.7},
+				{"word": "world", "start": 0.7, "end": 1.5},
+			},
+		})
+	})
+	mux.HandleFunc("/broken"+asr.TranscriptionsURL, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"message":"invalid api key"}}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+// runASRConformance 所有提供方必须满足的行为
+func runASRConformance(t *testing.T, p asr.Provider, audio asr.Audio) {
+	ctx := context.Background()
+
+	resp, err := p.Recognize(ctx, audio, "auto")
+	if !assert.NoError(t, err, p.Name()) {
+		return
+	}
+	assert.Equal(t, "hello world. this is a test.", resp.Text)
+	assert.Equal(t, len([]rune(resp.Text)), resp.CharCount)
+	assert.Equal(t, "en", resp.Language)
+	if assert.Len(t, resp.Segments, 2) {
+		assert.Equal(t, "hello world.", resp.Segments[0].Text)
+		assert.Equal(t, int64(0), resp.Segments[0].StartMs)
+		assert.Equal(t, int64(1500), resp.Segments[0].EndMs)
+		assert.Equal(t, int64(1800), resp.Segments[1].StartMs)
+		assert.Equal(t, int64(4000), resp.Segments[1].EndMs)
+		// 说话人标识按首次出现顺序统一为 A、B
+		assert.Equal(t, "A", resp.Segments[0].Speaker)
+		assert.Equal(t, "B", resp.Segments[1].Speaker)
+		if assert.Len(t, resp.Segments[0].Words, 2) {
+			assert.Equal(t, "world", resp.Segments[0].Words[1].Text)
+			assert.Equal(t, int64(700), resp.Segments[0].Words[1].StartMs)
+		}
+	}
+
+	// 指定语言时结果语言为指定值
+	resp, err = p.Recognize(ctx, audio, "en-US")
+	if assert.NoError(t, err, p.Name()) {
+		assert.Equal(t, "en", resp.Language)
+	}
+}
+
+func TestASRProviderConformance(t *testing.T) {
+	srv := fakeASRServer(t)
+	defer srv.Close()
+	asr.SetVolcCreds(asr.VolcCreds{AppId: "app-id-123456", AccessKey: "access-key-123456", ASRResourceId: "volc.bigasr.auc_turbo"})
+
+	urlAudio := asr.Audio{URL: srv.URL + "/audio.mp3", Name: "audio.mp3"}
+	dataAudio := asr.Audio{Data: []byte("ID3fake-audio"), Name: "chunk_000.mp3"}
+	whisper := asr.NewWhisperProvider(asr.WhisperConfig{URL: srv.URL})
+
+	t.Run("volc_flash_url", func(t *testing.T) { runASRConformance(t, asr.NewVolcFlashProvider(srv.URL), urlAudio) })
+	t.Run("volc_flash_data", func(t *testing.T) { runASRConformance(t, asr.NewVolcFlashProvider(srv.URL), dataAudio) })
+	t.Run("volc_submit_url", func(t *testing.T) { runASRConformance(t, asr.NewVolcSubmitProvider(srv.URL), urlAudio) })
+	t.Run("whisper_url", func(t *testing.T) { runASRConformance(t, whisper, urlAudio) })
+	t.Run("whisper_data", func(t *testing.T) { runASRConformance(t, whisper, dataAudio) })
+
+	// 标准版不支持本地文件，故障转移到下一个提供方
+	t.Run("failover", func(t *testing.T) {
+		broken := asr.NewWhisperProvider(asr.WhisperConfig{URL: srv.URL + "/broken"})
+		runASRConformance(t, asr.NewFailover(broken, asr.NewVolcSubmitProvider(srv.URL), whisper), dataAudio)
+		assert.True(t, strings.Contains(asr.NewFailover(broken, whisper).Name(), ","))
+	})
+
+	// 上游未返回语种时只按文字系统推断，拉丁字母无法区分英、西、法等语言，结果为未知而不是 en
+	t.Run("latin_without_lid", func(t *testing.T) {
+		nolid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"header":{"code":20000000},"audio_info":{"duration":2000},"result":{"text":"hola mundo, esto es una prueba."}}`))
+		}))
+		defer nolid.Close()
+		resp, err := asr.NewVolcFlashProvider(nolid.URL).Recognize(context.Background(), urlAudio, "auto")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "", resp.Language)
+		}
+	})
+
+	t.Run("all_failed", func(t *testing.T) {
+		broken := asr.NewWhisperProvider(asr.WhisperConfig{URL: srv.URL + "/broken"})
+		_, err := asr.NewFailover(broken, asr.NewVolcSubmitProvider(srv.URL)).Recognize(context.Background(), dataAudio, "auto")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, asr.ErrProviderUnavailable)
+	})
+}
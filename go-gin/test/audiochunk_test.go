@@ -0,0 +1,111 @@
+package test
+
+import (
+	"testing"
+
+	"go-gin/internal/audiochunk"
+	"go-gin/rest/asr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudioChunkPlan(t *testing.T) {
+	opt := audiochunk.Options{WindowMs: 600_000, OverlapMs: 5_000, SlackMs: 60_000}
+
+	// 短音频不切分
+	chunks := audiochunk.Plan(300_000, nil, opt)
+	assert.Len(t, chunks, 1)
+
+	// 目标切点前有静音时在静音中点切分，无重叠
+	silences := []audiochunk.Silence{{StartMs: 580_000, EndMs: 582_000}}
+	chunks = audiochunk.Plan(1_000_000, silences, opt)
+	assert.Len(t, chunks, 2)
+	assert.True(t, chunks[0].BySilence)
+	assert.Equal(t, int64(581_000), chunks[0].EndMs)
+	assert.Equal(t, int64(581_000), chunks[1].StartMs)
+	assert.Equal(t, chunks[0].KeepTo, chunks[1].KeepFrom)
+
+	// 没有静音时按固定窗口切分并保留重叠，重叠区间以中点分配
+	chunks = audiochunk.Plan(1_300_000, nil, opt)
+	assert.Len(t, chunks, 3)
+	assert.Equal(t, int64(595_000), chunks[1].StartMs)
+	assert.Equal(t, int64(597_500), chunks[0].KeepTo)
+	assert.Equal(t, int64(597_500), chunks[1].KeepFrom)
+	assert.Equal(t, int64(1_300_001), chunks[2].KeepTo)
+}
+
+func TestMergeChunks(t *testing.T) {
+	chunks := []audiochunk.Chunk{
+		{Index: 0, StartMs: 0, EndMs: 10_000, KeepFrom: 0, KeepTo: 9_000},
+		{Index: 1, StartMs: 8_000, EndMs: 20_000, KeepFrom: 9_000, KeepTo: 20_000},
+	}
+	speakers := func(r *asr.ASRResp) []string {
+		var out []string
+		for _, seg := range r.Segments {
+			out = append(out, seg.Speaker)
+		}
+		return out
+	}
+
+	// 重叠区间内时间重合的说话人对齐到上一切片，对不上的使用新标识
+	merged := asr.MergeChunks(chunks, []*asr.ASRResp{
+		{Language: "en", Segments: []asr.Segment{
+			{StartMs: 0, EndMs: 4_000, Text: "one", Speaker: "A"},
+			{StartMs: 4_000, EndMs: 8_000, Text: "two", Speaker: "B"},
+			{StartMs: 8_000, EndMs: 10_000, Text: "three", Speaker: "A"},
+		}},
+		{Language: "en", Segments: []asr.Segment{
+			{StartMs: 0, EndMs: 2_000, Text: "three", Speaker: "B"},
+			{StartMs: 2_000, EndMs: 6_000, Text: "four", Speaker: "A"},
+			{StartMs: 6_000, EndMs: 9_000, Text: "five", Speaker: "C"},
+			{StartMs: 9_000, EndMs: 11_000, Text: "six", Speaker: "B"},
+		}},
+	}, "en")
+	assert.Equal(t, "one two three four five six", merged.Text)
+	assert.Equal(t, []string{"A", "B", "A", "C", "D", "A"}, speakers(merged))
+	assert.Equal(t, int64(10_000), merged.Segments[3].StartMs)
+
+	// 没有重叠的切片之间不对齐，说话人全部使用新标识
+	silence := []audiochunk.Chunk{
+		{Index: 0, StartMs: 0, EndMs: 10_000, KeepFrom: 0, KeepTo: 10_000, BySilence: true},
+		{Index: 1, StartMs: 10_000, EndMs: 20_000, KeepFrom: 10_000, KeepTo: 20_000},
+	}
+	merged = asr.MergeChunks(silence, []*asr.ASRResp{
+		{Segments: []asr.Segment{{StartMs: 0, EndMs: 9_000, Text: "one", Speaker: "A"}}},
+		{Segments: []asr.Segment{{StartMs: 0, EndMs: 9_000, Text: "two", Speaker: "A"}}},
+	}, "en")
+	assert.Equal(t, []string{"A", "B"}, speakers(merged))
+
+	// 两段都没有分句时按文本去掉重叠部分，忽略大小写与标点
+	merged = asr.MergeChunks(chunks, []*asr.ASRResp{
+		{Text: "The quick brown fox jumps over"},
+		{Text: "Fox jumps over, the lazy dog."},
+	}, "en")
+	assert.Equal(t, "The quick brown fox jumps over the lazy dog.", merged.Text)
+	merged = asr.MergeChunks(chunks, []*asr.ASRResp{
+		{Text: "今天天气很好，我们去公园散步吧"},
+		{Text: "我们去公园散步吧。然后回家"},
+	}, "zh")
+	assert.Equal(t, "今天天气很好，我们去公园散步吧然后回家", merged.Text)
+	// 重复过短视为巧合
+	merged = asr.MergeChunks(chunks, []*asr.ASRResp{{Text: "see you"}, {Text: "you know"}}, "en")
+	assert.Equal(t, "see you you know", merged.Text)
+
+	// 没有分句的切片整段归属本切片，相邻切片落在其时间范围内的分句丢弃
+	merged = asr.MergeChunks(chunks, []*asr.ASRResp{
+		{Text: "one two three"},
+		{Segments: []asr.Segment{
+			{StartMs: 1_500, EndMs: 2_000, Text: "three", Speaker: "A"},
+			{StartMs: 2_000, EndMs: 4_000, Text: "four", Speaker: "A"},
+		}},
+	}, "en")
+	assert.Equal(t, "one two three four", merged.Text)
+	merged = asr.MergeChunks(chunks, []*asr.ASRResp{
+		{Segments: []asr.Segment{
+			{StartMs: 0, EndMs: 7_000, Text: "one two", Speaker: "A"},
+			{StartMs: 8_500, EndMs: 9_500, Text: "three", Speaker: "A"},
+		}},
+		{Text: "three four"},
+	}, "en")
+	assert.Equal(t, "one two three four", merged.Text)
+}
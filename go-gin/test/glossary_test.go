@@ -0,0 +1,28 @@
+package test
+
+import (
+	"testing"
+
+	"go-gin/rest/translate"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlossaryCheck(t *testing.T) {
+	terms := []translate.Term{
+		{Source: "Kubernetes", Target: "K8s"},
+		{Source: "pod", Target: "容器组"},
+		{Source: "Go", Target: "Go 语言", CaseSensitive: true},
+	}
+
+	matched := translate.MatchTerms(terms, "Deploy a POD on kubernetes")
+	assert.Len(t, matched, 2)
+
+	// 不区分大小写的术语在原文中匹配，译文缺少指定译法时报告
+	v := translate.CheckGlossary(terms, 3, "Deploy a POD on kubernetes", "在 K8s 上部署一个 Pod")
+	assert.Equal(t, []translate.Violation{{Segment: 3, Source: "pod", Target: "容器组"}}, v)
+
+	// 区分大小写的术语不匹配小写原文
+	assert.Empty(t, translate.CheckGlossary(terms, 0, "let's go", "走吧"))
+	assert.Empty(t, translate.CheckGlossary(terms, 0, "Learn Go", "学习 Go 语言"))
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go-gin/internal/llm"
+	"go-gin/internal/llm/llmtest"
+	"go-gin/rest/translate"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLLMClient(t *testing.T) {
+	srv := llmtest.NewServer(nil)
+	defer srv.Close()
+	client := llm.NewClient(srv.Config("fake"))
+	ctx := context.Background()
+
+	resp, err := client.Chat(ctx, llm.Request{Messages: []llm.Message{llm.System("sys"), llm.User("你好")}})
+	assert.NoError(t, err)
+	assert.Equal(t, "你好", resp.Content)
+	assert.Equal(t, 7, resp.Usage.TotalTokens)
+	assert.Equal(t, "test-key", srv.Requests()[0].ApiKey)
+
+	var deltas []string
+	resp, err = client.ChatStream(ctx, llm.Request{Messages: []llm.Message{llm.User("abc")}}, func(d string) error {
+		deltas = append(deltas, d)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, deltas)
+	assert.Equal(t, "abc", resp.Content)
+	assert.Equal(t, "stop", resp.FinishReason)
+	assert.Equal(t, 6, resp.Usage.TotalTokens)
+
+	// 5xx 与 429 重试，4xx 直接失败
+	srv.FailNext(http.StatusBadGateway, http.StatusTooManyRequests)
+	resp, err = client.Chat(ctx, llm.Request{Messages: []llm.Message{llm.User("retry")}})
+	assert.NoError(t, err)
+	assert.Equal(t, "retry", resp.Content)
+
+	srv.FailNext(http.StatusUnauthorized)
+	_, err = client.Chat(ctx, llm.Request{Messages: []llm.Message{llm.User("x")}})
+	var apiErr *llm.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.Status)
+}
+
+func TestTranslateOnLLM(t *testing.T) {
+	line := regexp.MustCompile(`\[(\d+)\] (.*)`)
+	srv := llmtest.NewServer(func(messages []llm.Message) llmtest.Reply {
+		var out []string
+		for _, m := range line.FindAllStringSubmatch(messages[len(messages)-1].Content, -1) {
+			out = append(out, fmt.Sprintf("[%s] T:%s", m[1], m[2]))
+		}
+		return llmtest.Reply{Content: strings.Join(out, "\n")}
+	})
+	defer srv.Close()
+	llm.Register(srv.Config("fake-translate"))
+	translate.SetProvider("fake-translate")
+	translate.SetMemoryEnabled(false)
+	defer translate.SetProvider("")
+	defer translate.SetMemoryEnabled(true)
+
+	resp, err := translate.NewTranslateSvc().TranslateSegments(context.Background(), []string{"one", " ", "two"}, "en", "zh")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"T:one", " ", "T:two"}, resp.Texts)
+	assert.Equal(t, "fake-model", srv.Requests()[0].Model)
+}
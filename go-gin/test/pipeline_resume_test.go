@@ -0,0 +1,152 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-gin/const/errcode"
+	"go-gin/logic"
+	"go-gin/model"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// memPipelineStore 内存中的流水线存储
+type memPipelineStore struct {
+	runs   map[int64]*model.PipelineRun
+	stages []*model.PipelineStage
+}
+
+func newMemPipelineStore(runs ...model.PipelineRun) *memPipelineStore {
+	s := &memPipelineStore{runs: map[int64]*model.PipelineRun{}}
+	for i := range runs {
+		s.runs[runs[i].Id] = &runs[i]
+	}
+	return s
+}
+
+func (s *memPipelineStore) GetRun(_ context.Context, id int64) (*model.PipelineRun, error) {
+	run, ok := s.runs[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *run
+	return &cp, nil
+}
+
+func (s *memPipelineStore) ListRuns(_ context.Context, _ string, _, _ int) ([]model.PipelineRun, int64, error) {
+	return nil, 0, nil
+}
+
+func (s *memPipelineStore) UpdateRun(_ context.Context, id int64, updates map[string]any) error {
+	if status, ok := updates["status"].(string); ok {
+		s.runs[id].Status = status
+	}
+	if stage, ok := updates["current_stage"].(string); ok {
+		s.runs[id].CurrentStage = stage
+	}
+	return nil
+}
+
+func (s *memPipelineStore) ListStages(_ context.Context, runId int64) ([]model.PipelineStage, error) {
+	var items []model.PipelineStage
+	for _, st := range s.stages {
+		if st.RunId == runId {
+			items = append(items, *st)
+		}
+	}
+	return items, nil
+}
+
+func (s *memPipelineStore) SaveStage(_ context.Context, stage *model.PipelineStage) error {
+	cp := *stage
+	if stage.Id == 0 {
+		stage.Id = int64(len(s.stages) + 1)
+		cp.Id = stage.Id
+		s.stages = append(s.stages, &cp)
+		return nil
+	}
+	s.stages[stage.Id-1] = &cp
+	return nil
+}
+
+func (s *memPipelineStore) stage(name string) *model.PipelineStage {
+	for _, st := range s.stages {
+		if st.Stage == name {
+			return st
+		}
+	}
+	return nil
+}
+
+func TestPipelineResumeFromFailedStage(t *testing.T) {
+	ctx := context.Background()
+	store := newMemPipelineStore(
+		model.PipelineRun{Id: 1, Status: model.PipelineStatusRunning},
+		model.PipelineRun{Id: 2, Status: model.PipelineStatusDone},
+	)
+	logic.SetPipelineStore(store)
+	defer logic.SetPipelineStore(model.NewPipelineModel())
+
+	type info struct {
+		Title string `json:"title"`
+	}
+	runStages := func(run *model.PipelineRun, infoCalls *int, asrErr error) (info, error) {
+		r, err := logic.NewPipelineRunner(ctx, run)
+		assert.NoError(t, err)
+		var out info
+		if err := r.Step(ctx, model.PipelineStageInfo, nil, &out, func() error {
+			*infoCalls++
+			out.Title = "视频标题"
+			return nil
+		}); err != nil {
+			return out, err
+		}
+		r.Skip(ctx, model.PipelineStageCaptions, "no captions")
+		var text string
+		err = r.Step(ctx, model.PipelineStageASR, out, &text, func() error {
+			if asrErr != nil {
+				return asrErr
+			}
+			text = "识别结果"
+			return nil
+		})
+		return out, err
+	}
+
+	// 首次执行：ASR 阶段失败，流水线标记为失败
+	run, _ := store.GetRun(ctx, 1)
+	infoCalls := 0
+	_, err := runStages(run, &infoCalls, errors.New("asr timeout"))
+	assert.EqualError(t, err, "asr timeout")
+	assert.Equal(t, 1, infoCalls)
+	assert.Equal(t, model.PipelineStatusFailed, store.runs[1].Status)
+	assert.Equal(t, model.StageStatusFailed, store.stage(model.PipelineStageASR).Status)
+
+	// 仅失败的流水线可以重新驱动
+	_, err = logic.NewPipelineLogic().PrepareRedrive(ctx, 2)
+	assert.ErrorIs(t, err, errcode.ErrPipelineNotFailed)
+	_, err = logic.NewPipelineLogic().PrepareRedrive(ctx, 3)
+	assert.ErrorIs(t, err, errcode.ErrPipelineNotFound)
+	run, err = logic.NewPipelineLogic().PrepareRedrive(ctx, 1)
+	assert.NoError(t, err)
+
+	// 续跑：已完成阶段复用输出不再执行，跳过的阶段保持跳过，失败阶段重试
+	out, err := runStages(run, &infoCalls, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, infoCalls)
+	assert.Equal(t, "视频标题", out.Title)
+
+	infoStage := store.stage(model.PipelineStageInfo)
+	assert.Equal(t, model.StageStatusDone, infoStage.Status)
+	assert.Equal(t, 1, infoStage.Attempts)
+	assert.Equal(t, model.StageStatusSkipped, store.stage(model.PipelineStageCaptions).Status)
+	asrStage := store.stage(model.PipelineStageASR)
+	assert.Equal(t, model.StageStatusDone, asrStage.Status)
+	assert.Equal(t, 2, asrStage.Attempts)
+	assert.Equal(t, `"识别结果"`, asrStage.Output)
+	assert.Empty(t, asrStage.ErrorMsg)
+	assert.Len(t, store.stages, 3)
+}
@@ -0,0 +1,188 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"go-gin/const/errcode"
+	"go-gin/internal/llm"
+	"go-gin/internal/llm/llmtest"
+	"go-gin/logic"
+	"go-gin/model"
+	"go-gin/typing"
+	"go-gin/util/retrieval"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetrievalBM25(t *testing.T) {
+	assert.Equal(t, []string{"go", "1", "22", "发", "布", "发布"}, retrieval.Tokenize("Go 1.22 发布"))
+
+	idx := retrieval.NewIndex([]string{
+		"today we talk about the weather and the rain",
+		"the new compiler makes builds faster; compiler flags explained",
+		"主持人介绍了编译器的优化",
+		"",
+	})
+	hits := idx.Search("Compiler speed", 10)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, 1, hits[0].Index)
+	hits = idx.Search("编译器", 10)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, 2, hits[0].Index)
+	assert.Empty(t, idx.Search("nothing matches", 10))
+
+	assert.InDelta(t, 1.0, retrieval.Cosine([]float64{1, 2}, []float64{2, 4}), 1e-9)
+	assert.Equal(t, 0.0, retrieval.Cosine([]float64{1}, []float64{1, 2}))
+}
+
+func TestLLMEmbed(t *testing.T) {
+	srv := llmtest.NewServer(nil)
+	defer srv.Close()
+	client := llm.NewClient(srv.Config("fake"))
+	ctx := context.Background()
+
+	docs := []string{"the weather is rainy today", "compiler optimizations in the new release"}
+	vectors, usage, err := client.Embed(ctx, docs)
+	assert.NoError(t, err)
+	assert.Len(t, vectors, 2)
+	assert.Len(t, vectors[0], llmtest.EmbeddingDims)
+	assert.Greater(t, usage.TotalTokens, 0)
+
+	qv, _, err := client.Embed(ctx, []string{"which compiler optimizations"})
+	assert.NoError(t, err)
+	hits := retrieval.RankByVector(qv[0], vectors, 1)
+	assert.Equal(t, 1, hits[0].Index)
+
+	cfg := srv.Config("no-embedding")
+	cfg.EmbeddingModel = ""
+	_, _, err = llm.NewClient(cfg).Embed(ctx, docs)
+	assert.ErrorIs(t, err, llm.ErrEmbeddingNotConfigured)
+}
+
+func TestRetrievalSnippet(t *testing.T) {
+	assert.Equal(t, "a <em>Compiler</em> &amp; a <em>compiler</em>", retrieval.Snippet("a  Compiler & a compiler", []string{"compiler"}, 40))
+	assert.Equal(t, "…二段<em>编译器</em>第三…", retrieval.Snippet("第一段第二段编译器第三段", []string{"编译器"}, 2))
+	assert.Equal(t, "abcd…", retrieval.Snippet("abcdefgh", []string{"zz"}, 2))
+	assert.Equal(t, "<em>go 1.22</em>", retrieval.Snippet("go 1.22", []string{"go", "go 1.22"}, 10))
+}
+
+// memSearchStore 只有 owner 拥有数据，记录每次查询的 identity 与检索表达式
+type memSearchStore struct {
+	owner      string
+	segments   []model.SegmentMatch
+	cards      []model.TranscriptCard
+	identities []string
+	againsts   []string
+}
+
+func (s *memSearchStore) record(identity, against string) bool {
+	s.identities = append(s.identities, identity)
+	s.againsts = append(s.againsts, against)
+	return identity == s.owner
+}
+
+func (s *memSearchStore) Segments(_ context.Context, identity, against string, _ int) ([]model.SegmentMatch, error) {
+	if !s.record(identity, against) {
+		return nil, nil
+	}
+	return s.segments, nil
+}
+
+func (s *memSearchStore) Transcripts(_ context.Context, identity, against string, _ int) ([]model.TranscriptMatch, error) {
+	s.record(identity, against)
+	return nil, nil
+}
+
+func (s *memSearchStore) Titles(_ context.Context, identity, against string, _ int) ([]model.TranscriptMatch, error) {
+	s.record(identity, against)
+	return nil, nil
+}
+
+func (s *memSearchStore) Cards(_ context.Context, ids []int64) ([]model.TranscriptCard, error) {
+	var out []model.TranscriptCard
+	for _, c := range s.cards {
+		for _, id := range ids {
+			if c.TranscriptId == id {
+				out = append(out, c)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (s *memSearchStore) TTS(_ context.Context, identity, against string, _ int) ([]model.TTSMatch, error) {
+	s.record(identity, against)
+	return nil, nil
+}
+
+func TestSearch(t *testing.T) {
+	store := &memSearchStore{
+		owner: "13800000001",
+		segments: []model.SegmentMatch{
+			{TranscriptId: 1, SegIndex: 4, StartMs: 83_500, EndMs: 86_000, OriginalText: "The new COMPILER is faster", Score: 2},
+			{TranscriptId: 2, SegIndex: 0, StartMs: 0, EndMs: 3_000, OriginalText: "Faster builds", TranslatedText: "新的优化器让构建更快", Score: 1},
+		},
+		cards: []model.TranscriptCard{
+			{TranscriptId: 1, SourceSite: "youtube", VideoId: "yt123", Title: "Compiler Deep Dive", OriginalText: "about the compiler"},
+			{TranscriptId: 2, SourceSite: "bilibili", VideoId: "BV1xx", Title: "构建提速", OriginalText: "builds", TranslatedText: "构建与优化器"},
+		},
+	}
+	logic.SetSearchStore(store)
+	ctx := context.Background()
+
+	// 去掉全文检索操作符，过短与重复（不区分大小写）的词丢弃，每个词按短语必须出现
+	reply, err := logic.NewSearchLogic().Search(ctx, store.owner, typing.SearchReq{Q: `+Compiler* -"优化器" a compiler @x(`, Type: "transcript"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Compiler", "优化器"}, reply.Terms)
+	assert.Equal(t, []string{`+"Compiler" +"优化器"`}, uniqueStrings(store.againsts))
+
+	if assert.Len(t, reply.Transcripts, 2) {
+		yt, bili := reply.Transcripts[0], reply.Transcripts[1]
+		assert.Equal(t, "https://www.youtube.com/watch?v=yt123", yt.Url)
+		assert.Equal(t, "<em>Compiler</em> Deep Dive", yt.Title)
+		if assert.Len(t, yt.Segments, 1) {
+			assert.Equal(t, "https://www.youtube.com/watch?v=yt123&t=83s", yt.Segments[0].Url)
+			assert.Equal(t, "01:23", yt.Segments[0].Start)
+			// 大小写不同也高亮，保留原文大小写
+			assert.Equal(t, "The new <em>COMPILER</em> is faster", yt.Segments[0].Snippet)
+		}
+		assert.Equal(t, "https://www.bilibili.com/video/BV1xx", bili.Url)
+		if assert.Len(t, bili.Segments, 1) {
+			// 起点为 0 时不带时间点；原文没有命中时取译文高亮
+			assert.Equal(t, "https://www.bilibili.com/video/BV1xx", bili.Segments[0].Url)
+			assert.Equal(t, "新的<em>优化器</em>让构建更快", bili.Segments[0].Snippet)
+		}
+		assert.Equal(t, "构建与<em>优化器</em>", bili.Snippet)
+	}
+
+	// 所有查询都按调用者限定范围，其他用户搜不到
+	store.identities = nil
+	reply, err = logic.NewSearchLogic().Search(ctx, "13800000002", typing.SearchReq{Q: "compiler"})
+	assert.NoError(t, err)
+	assert.Empty(t, reply.Transcripts)
+	assert.Empty(t, reply.TTS)
+	assert.Equal(t, []string{"13800000002"}, uniqueStrings(store.identities))
+
+	// 没有身份时不查询
+	store.identities = nil
+	reply, err = logic.NewSearchLogic().Search(ctx, "", typing.SearchReq{Q: "compiler"})
+	assert.NoError(t, err)
+	assert.Empty(t, reply.Transcripts)
+	assert.Empty(t, store.identities)
+
+	_, err = logic.NewSearchLogic().Search(ctx, store.owner, typing.SearchReq{Q: `+ -"a" *`})
+	assert.ErrorIs(t, err, errcode.ErrSearchQueryTooShort)
+}
+
+func uniqueStrings(items []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, it := range items {
+		if !seen[it] {
+			seen[it] = true
+			out = append(out, it)
+		}
+	}
+	return out
+}
@@ -0,0 +1,207 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go-gin/const/errcode"
+	"go-gin/internal/component/redisx"
+	"go-gin/internal/httpx"
+	"go-gin/logic"
+	"go-gin/model"
+	"go-gin/router"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// fakeTokenRedis 最小的 RESP 服务：任意 token 都存在，identity 字段固定返回 identity
+func fakeTokenRedis(t *testing.T, identity string) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveTokenRedis(conn, identity)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func serveTokenRedis(conn net.Conn, identity string) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readRESPArray(r)
+		if err != nil {
+			return
+		}
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "PING":
+			reply = "+PONG\r\n"
+		case "EXISTS":
+			reply = ":1\r\n"
+		case "HGET":
+			reply = fmt.Sprintf("$%d\r\n%s\r\n", len(identity), identity)
+		default:
+			// HELLO、CLIENT SETINFO 等返回错误，客户端按 RESP2 继续
+			reply = "-ERR unknown command\r\n"
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func readRESPArray(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("bad array header %q", line)
+	}
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:size]))
+	}
+	return args, nil
+}
+
+// memTranscriptStore 内存中的转录、修订与分句
+type memTranscriptStore struct {
+	transcripts map[int64]*model.YoutubeTranscript
+	owners      map[int64]string
+	revisions   map[int64][]model.YoutubeTranscriptRevision
+	segments    map[int64][]model.YoutubeTranscriptSegment
+}
+
+func (s *memTranscriptStore) Get(_ context.Context, id int64) (*model.YoutubeTranscript, error) {
+	t, ok := s.transcripts[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return t, nil
+}
+
+func (s *memTranscriptStore) Owns(_ context.Context, identity string, id int64) (bool, error) {
+	return s.owners[id] == identity, nil
+}
+
+func (s *memTranscriptStore) ForkOf(_ context.Context, identity string, sourceId int64) (*model.YoutubeTranscript, error) {
+	for _, t := range s.transcripts {
+		if t.ForkedFrom == sourceId && t.OwnerIdentity == identity {
+			return t, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *memTranscriptStore) GetRevision(_ context.Context, id int64, revNo int) (*model.YoutubeTranscriptRevision, error) {
+	for _, rev := range s.revisions[id] {
+		if rev.RevNo == revNo {
+			return &rev, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *memTranscriptStore) ListSegments(_ context.Context, id int64) ([]model.YoutubeTranscriptSegment, error) {
+	return s.segments[id], nil
+}
+
+// routeReply 统一响应结构
+type routeReply struct {
+	Code int             `json:"code"`
+	Data json.RawMessage `json:"data"`
+}
+
+func TestRevisionDiffRoute(t *testing.T) {
+	redisx.InitConfig(redisx.Config{Addr: fakeTokenRedis(t, "13800000001")})
+	redisx.Init()
+
+	base := model.YoutubeTranscriptRevision{RevNo: 1, OriginalText: "hello world", TranslatedText: "你好世界"}
+	base.SetSegments([]model.RevisionSegment{{Index: 0, OriginalText: "hello world", TranslatedText: "你好世界"}})
+	logic.SetTranscriptStore(&memTranscriptStore{
+		transcripts: map[int64]*model.YoutubeTranscript{
+			7: {Id: 7, OriginalText: "hello world", TranslatedText: "你好，世界"},
+			8: {Id: 8, OriginalText: "other"},
+		},
+		owners:    map[int64]string{7: "13800000001", 8: "13800000002"},
+		revisions: map[int64][]model.YoutubeTranscriptRevision{7: {base}},
+		segments: map[int64][]model.YoutubeTranscriptSegment{
+			7: {{TranscriptId: 7, SegIndex: 0, OriginalText: "hello world", TranslatedText: "你好，世界"}},
+		},
+	})
+
+	engine := httpx.New()
+	router.RegisterYtRoutes(engine.Group("/api"))
+	get := func(path string) routeReply {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("token", "t1")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		var reply routeReply
+		if err := json.Unmarshal(w.Body.Bytes(), &reply); err != nil {
+			t.Fatalf("%s: %v, body: %s", path, err, w.Body.String())
+		}
+		return reply
+	}
+
+	// 查询参数在绑定 uri 之后才绑定，from 不能在 uri 阶段被判为缺失
+	reply := get("/api/yt/transcripts/7/revisions/diff?from=1")
+	if assert.Equal(t, http.StatusOK, reply.Code, string(reply.Data)) {
+		var diff struct {
+			From     int `json:"from"`
+			To       int `json:"to"`
+			Segments []struct {
+				Index          int `json:"index"`
+				TranslatedText []struct {
+					Type string `json:"type"`
+					Text string `json:"text"`
+				} `json:"translated_text"`
+			} `json:"segments"`
+		}
+		assert.NoError(t, json.Unmarshal(reply.Data, &diff))
+		assert.Equal(t, 1, diff.From)
+		assert.Equal(t, 0, diff.To)
+		if assert.Len(t, diff.Segments, 1) {
+			assert.Equal(t, 0, diff.Segments[0].Index)
+			assert.NotEmpty(t, diff.Segments[0].TranslatedText)
+		}
+	}
+
+	assert.Equal(t, errcode.ErrRevisionNotFound.Code, get("/api/yt/transcripts/7/revisions/diff").Code)
+	assert.Equal(t, errcode.ErrRevisionNotFound.Code, get("/api/yt/transcripts/7/revisions/diff?from=2").Code)
+	// 不属于调用者的转录按不存在处理
+	assert.Equal(t, errcode.ErrTranscriptNotFound.Code, get("/api/yt/transcripts/8/revisions/diff?from=1").Code)
+}
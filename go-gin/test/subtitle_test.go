@@ -0,0 +1,110 @@
+package test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-gin/util/subtitle"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files")
+
+var sampleCues = []subtitle.Cue{
+	{StartMs: 0, EndMs: 2500, Text: "Welcome back to the channel, today we are talking about <subtitles> & captions."},
+	{StartMs: 2500, EndMs: 2500, Text: "Zero-length cue gets a minimum duration."},
+	{StartMs: 3723456, EndMs: 3725000, Text: "   "},
+	{StartMs: 3725000, EndMs: 3729990, Text: "Bilingual line {with braces}\n欢迎回到频道，今天我们来聊聊字幕的时间轴和自动换行问题。"},
+}
+
+func TestSubtitleGolden(t *testing.T) {
+	for _, f := range []subtitle.Format{subtitle.FormatSRT, subtitle.FormatVTT, subtitle.FormatASS} {
+		t.Run(string(f), func(t *testing.T) {
+			got, err := subtitle.Render(f, sampleCues, subtitle.Options{Title: "sample"})
+			assert.NoError(t, err)
+			golden := filepath.Join("testdata", "subtitle", "sample."+f.Ext())
+			if *updateGolden {
+				assert.NoError(t, os.WriteFile(golden, got, 0o644))
+			}
+			want, err := os.ReadFile(golden)
+			assert.NoError(t, err)
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+}
+
+func TestSubtitleWrap(t *testing.T) {
+	assert.Equal(t, []string{"the quick brown", "fox jumps over", "the lazy dog"}, subtitle.Wrap("the quick brown fox jumps over the lazy dog", 15))
+	assert.Equal(t, []string{"今天天气很好，", "我们去公园。"}, subtitle.Wrap("今天天气很好，我们去公园。", 14))
+	assert.Equal(t, []string{"supercalifragilistic", "ok"}, subtitle.Wrap("supercalifragilistic ok", 10))
+	assert.Nil(t, subtitle.Wrap("   ", 10))
+}
+
+func TestSubtitleParseFormat(t *testing.T) {
+	f, ok := subtitle.ParseFormat("")
+	assert.True(t, ok)
+	assert.Equal(t, subtitle.FormatSRT, f)
+	f, ok = subtitle.ParseFormat("VTT")
+	assert.True(t, ok)
+	assert.Equal(t, "text/vtt; charset=utf-8", f.ContentType())
+	_, ok = subtitle.ParseFormat("txt")
+	assert.False(t, ok)
+}
+
+func TestSubtitleParseVTT(t *testing.T) {
+	// YouTube 自动字幕：内联时间标签 + 滚动重复行
+	data := "WEBVTT\nKind: captions\nLanguage: en\n\n" +
+		"00:00:00.000 --> 00:00:02.000 align:start position:0%\nhello<00:00:00.500><c> world</c>\n\n" +
+		"00:00:02.000 --> 00:00:04.000 align:start position:0%\nhello world\nthis is &amp; that\n\n" +
+		"NOTE comment block\n\n" +
+		"01:00.000 --> 01:01.500\n<v Speaker>last line</v>\n"
+	cues, err := subtitle.ParseVTT([]byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, []subtitle.Cue{
+		{StartMs: 0, EndMs: 2000, Text: "hello world"},
+		{StartMs: 2000, EndMs: 4000, Text: "this is & that"},
+		{StartMs: 60000, EndMs: 61500, Text: "last line"},
+	}, cues)
+
+	_, err = subtitle.ParseVTT([]byte("00:00:00.000 --> 00:00:01.000\nno header\n"))
+	assert.Error(t, err)
+}
+
+func TestSubtitleParseSRTAndSRV3(t *testing.T) {
+	cues, err := subtitle.Parse("srt", []byte("1\r\n00:00:01,000 --> 00:00:02,500\r\n<i>first</i>\r\nline\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nsecond\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []subtitle.Cue{
+		{StartMs: 1000, EndMs: 2500, Text: "first line"},
+		{StartMs: 3000, EndMs: 4000, Text: "second"},
+	}, cues)
+
+	srv3 := `<?xml version="1.0" encoding="utf-8" ?><timedtext format="3"><body>` +
+		`<p t="1200" d="1800"><s>we&#39;re</s><s t="300"> live</s></p><p t="3000" d="10" a="1"> </p>` +
+		`<p t="3100" d="900">done</p></body></timedtext>`
+	cues, err = subtitle.Parse("srv3", []byte(srv3))
+	assert.NoError(t, err)
+	assert.Equal(t, []subtitle.Cue{
+		{StartMs: 1200, EndMs: 3000, Text: "we're live"},
+		{StartMs: 3100, EndMs: 4000, Text: "done"},
+	}, cues)
+
+	_, err = subtitle.Parse("json", nil)
+	assert.Error(t, err)
+}
+
+func TestSubtitleShortClock(t *testing.T) {
+	assert.Equal(t, "01:05", subtitle.FormatShortClock(65_400))
+	assert.Equal(t, "1:02:03", subtitle.FormatShortClock(3_723_000))
+
+	ms, ok := subtitle.ParseClock("[12:34]")
+	assert.True(t, ok)
+	assert.Equal(t, int64(754_000), ms)
+	ms, ok = subtitle.ParseClock("1:02:03.5")
+	assert.True(t, ok)
+	assert.Equal(t, int64(3_723_500), ms)
+	_, ok = subtitle.ParseClock("abc")
+	assert.False(t, ok)
+}
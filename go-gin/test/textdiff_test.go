@@ -0,0 +1,41 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"go-gin/util/textdiff"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextDiff(t *testing.T) {
+	a := "Speaker A: 你好，欢迎收听。今天聊聊字幕。\nSpeaker B: Thanks. Glad to be here!"
+	b := "Alice: 你好，欢迎收听。今天聊聊字幕。\nSpeaker B: Thanks. Happy to be here!"
+
+	ops := textdiff.Diff(a, b)
+	assert.True(t, textdiff.Changed(ops))
+
+	// 拼回旧文本与新文本
+	var oldText, newText strings.Builder
+	for _, op := range ops {
+		if op.Type != textdiff.OpInsert {
+			oldText.WriteString(op.Text)
+		}
+		if op.Type != textdiff.OpDelete {
+			newText.WriteString(op.Text)
+		}
+	}
+	assert.Equal(t, a, oldText.String())
+	assert.Equal(t, b, newText.String())
+
+	assert.Equal(t, []textdiff.Op{
+		{Type: textdiff.OpDelete, Text: "Speaker A: 你好，欢迎收听。"},
+		{Type: textdiff.OpInsert, Text: "Alice: 你好，欢迎收听。"},
+		{Type: textdiff.OpEqual, Text: "今天聊聊字幕。\nSpeaker B: Thanks. "},
+		{Type: textdiff.OpDelete, Text: "Glad to be here!"},
+		{Type: textdiff.OpInsert, Text: "Happy to be here!"},
+	}, ops)
+
+	assert.False(t, textdiff.Changed(textdiff.Diff(a, a)))
+}
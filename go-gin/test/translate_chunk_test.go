@@ -0,0 +1,129 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-gin/const/errcode"
+	"go-gin/internal/llm"
+	"go-gin/internal/llm/llmtest"
+	"go-gin/rest/translate"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var numberedLine = regexp.MustCompile(`\[(\d+)\] (.*)`)
+
+// fakeBatchReply 逐行回显 "[n] T:原文"；单句请求没有序号，直接回显
+func fakeBatchReply(content string, drop int) llmtest.Reply {
+	matches := numberedLine.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		parts := strings.SplitN(content, "\n\n", 2)
+		return llmtest.Reply{Content: "T:" + parts[len(parts)-1]}
+	}
+	var out []string
+	for _, m := range matches[:len(matches)-drop] {
+		out = append(out, fmt.Sprintf("[%s] T:%s", m[1], m[2]))
+	}
+	return llmtest.Reply{Content: strings.Join(out, "\n")}
+}
+
+func TestTranslateSegmentsBatching(t *testing.T) {
+	segments := func(n int, text func(i int) string) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = text(i)
+		}
+		return out
+	}
+	cases := []struct {
+		name     string
+		segments []string
+		handler  func(calls int, content string) llmtest.Reply
+		requests int
+		wantErr  bool
+	}{
+		{
+			name:     "按分句数分批",
+			segments: segments(100, func(i int) string { return fmt.Sprintf("s%d", i) }),
+			requests: 2,
+		},
+		{
+			name:     "按 token 预算分批",
+			segments: segments(3, func(i int) string { return strings.Repeat(string(rune('甲'+i)), 900) }),
+			requests: 2,
+		},
+		{
+			name:     "行数不足时拆分重试",
+			segments: []string{"a", "b", "c", "d"},
+			handler: func(calls int, content string) llmtest.Reply {
+				if strings.Count(content, "\n[") >= 3 {
+					return fakeBatchReply(content, 1) // 四行的批次少返回一行
+				}
+				return fakeBatchReply(content, 0)
+			},
+			requests: 3,
+		},
+		{
+			name:     "失败的批次单独重试",
+			segments: segments(100, func(i int) string { return fmt.Sprintf("s%d", i) }),
+			handler: func(calls int, content string) llmtest.Reply {
+				if strings.Contains(content, "[1] s80\n") && calls == 0 {
+					return llmtest.Reply{Status: http.StatusBadGateway, Content: "busy"}
+				}
+				return fakeBatchReply(content, 0)
+			},
+			requests: 3,
+		},
+		{
+			// 重试只在 llm 客户端一层进行：1 次请求 + MaxRetries(2) 次重试
+			name:     "批次持续失败时不叠加重试",
+			segments: []string{"a", "b"},
+			handler: func(calls int, content string) llmtest.Reply {
+				return llmtest.Reply{Status: http.StatusBadGateway, Content: "busy"}
+			},
+			requests: 3,
+			wantErr:  true,
+		},
+	}
+	translate.SetMemoryEnabled(false)
+	defer translate.SetMemoryEnabled(true)
+	defer translate.SetProvider("")
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			calls := map[string]int{}
+			srv := llmtest.NewServer(func(messages []llm.Message) llmtest.Reply {
+				content := messages[len(messages)-1].Content
+				if tc.handler == nil {
+					return fakeBatchReply(content, 0)
+				}
+				mu.Lock()
+				n := calls[content]
+				calls[content]++
+				mu.Unlock()
+				return tc.handler(n, content)
+			})
+			defer srv.Close()
+			llm.Register(srv.Config("fake-batch"))
+			translate.SetProvider("fake-batch")
+
+			resp, err := translate.NewTranslateSvc().TranslateSegments(context.Background(), tc.segments, "en", "zh")
+			assert.Len(t, srv.Requests(), tc.requests)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, errcode.ErrTranslateUp)
+				return
+			}
+			assert.NoError(t, err)
+			for i, text := range resp.Texts {
+				assert.Equal(t, "T:"+tc.segments[i], text)
+			}
+		})
+	}
+}
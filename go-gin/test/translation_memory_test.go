@@ -0,0 +1,24 @@
+package test
+
+import (
+	"testing"
+
+	"go-gin/rest/translate"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslationMemoryKey(t *testing.T) {
+	a := []translate.Term{{Source: "pod", Target: "容器组"}, {Source: "Kubernetes", Target: "K8s"}}
+	b := []translate.Term{{Source: "Kubernetes", Target: "K8s"}, {Source: "pod", Target: "容器组"}}
+	v := translate.GlossaryVersion(a)
+	assert.Equal(t, v, translate.GlossaryVersion(b))
+	assert.Empty(t, translate.GlossaryVersion(nil))
+
+	key := translate.MemoryKey("Thanks for watching.", "en-US", "zh", translate.ProviderDeepSeek, v)
+	// 语言代码规范化、首尾空白不影响键
+	assert.Equal(t, key, translate.MemoryKey(" Thanks for watching. ", "en", "zh-CN", translate.ProviderDeepSeek, v))
+	// 提供方或术语表版本不同则不复用
+	assert.NotEqual(t, key, translate.MemoryKey("Thanks for watching.", "en", "zh", translate.ProviderBailian, v))
+	assert.NotEqual(t, key, translate.MemoryKey("Thanks for watching.", "en", "zh", translate.ProviderDeepSeek, ""))
+}
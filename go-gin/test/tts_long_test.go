@@ -0,0 +1,182 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"go-gin/internal/audiochunk"
+	"go-gin/rest/tts"
+	"go-gin/util/subtitle"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTSSplitText(t *testing.T) {
+	assert.Nil(t, tts.SplitText("  ", 10))
+	assert.Equal(t, []string{"短文本。"}, tts.SplitText("短文本。", 10))
+
+	// 整句合并，不在小数点处切分
+	chunks := tts.SplitText("第一句话。第二句话！版本 1.5 发布了? Yes. 最后", 12)
+	assert.Equal(t, []string{"第一句话。第二句话！", "版本 1.5 发布了?", "Yes. 最后"}, chunks)
+
+	// 单句超长时在逗号处切分，仍超长时硬切
+	long := strings.Repeat("很长", 10) + "，" + strings.Repeat("字", 25) + "。"
+	chunks = tts.SplitText(long, 12)
+	for _, c := range chunks {
+		assert.LessOrEqual(t, utf8.RuneCountInString(c), 12)
+	}
+	assert.Equal(t, long, strings.Join(chunks, ""))
+}
+
+// mp3Frame 构造 MPEG2 Layer III 单声道 64kbps 的帧，srIndex 0=22050 1=24000
+func mp3Frame(srIndex byte, fill byte) []byte {
+	rate := []int{22050, 24000}[srIndex]
+	f := bytes.Repeat([]byte{fill}, 72000*64/rate)
+	copy(f, []byte{0xFF, 0xF3, 0x80 | srIndex<<2, 0xC0})
+	return f
+}
+
+func TestAudioChunkJoinMP3(t *testing.T) {
+	id3 := []byte{'I', 'D', '3', 3, 0, 0, 0, 0, 0, 4, 1, 2, 3, 4}
+	info := mp3Frame(1, 0)
+	copy(info[13:], "Info")
+	part1 := bytes.Join([][]byte{id3, info, mp3Frame(1, 1), mp3Frame(1, 2), mp3Frame(1, 3)}, nil)
+	tag := append([]byte("TAG"), make([]byte, 125)...)
+	part2 := bytes.Join([][]byte{mp3Frame(1, 4), mp3Frame(1, 5), tag}, nil)
+
+	joined, infos, err := audiochunk.JoinMP3Frames([][]byte{part1, part2})
+	assert.NoError(t, err)
+	assert.Equal(t, 5*192, len(joined))
+	assert.Equal(t, []audiochunk.MP3Info{
+		{SampleRate: 24000, Frames: 3, Samples: 1728, DurationMs: 72},
+		{SampleRate: 24000, Frames: 2, Samples: 1152, DurationMs: 48, OffsetMs: 72},
+	}, infos)
+	assert.Equal(t, byte(1), joined[4])
+	assert.Equal(t, byte(5), joined[len(joined)-1])
+
+	// LAME 扩展记录了编码延迟与补齐：可播放时长扣除二者，但按帧拼接后它们仍会播放，
+	// 后一段的起点按前一段的全部帧计算
+	lame := mp3Frame(1, 0)
+	copy(lame[13:], "Info")
+	lame[20] = 0
+	copy(lame[21:], "LAME")
+	copy(lame[42:], []byte{0x24, 0x02, 0x40}) // 延迟 576，补齐 576
+	part3 := bytes.Join([][]byte{lame, mp3Frame(1, 1), mp3Frame(1, 2), mp3Frame(1, 3)}, nil)
+	_, infos, err = audiochunk.JoinMP3Frames([][]byte{part3, part2})
+	assert.NoError(t, err)
+	assert.Equal(t, []audiochunk.MP3Info{
+		{SampleRate: 24000, Frames: 3, Samples: 1728, Delay: 576, DurationMs: 24, OffsetMs: 24},
+		{SampleRate: 24000, Frames: 2, Samples: 1152, DurationMs: 48, OffsetMs: 72},
+	}, infos)
+
+	_, _, err = audiochunk.JoinMP3Frames([][]byte{part1, mp3Frame(0, 6)})
+	assert.ErrorIs(t, err, audiochunk.ErrSampleRateMismatch)
+	_, _, err = audiochunk.JoinMP3Frames([][]byte{part1, []byte("not audio")})
+	assert.ErrorIs(t, err, audiochunk.ErrNoMP3Frames)
+}
+
+func TestTTSSentenceCacheKey(t *testing.T) {
+	// 逐句切分，单独的标点并入前一句，空白规范化
+	sentences := tts.SplitSentences("第一句话。  Hello   world! ！ 第三句", 20)
+	assert.Equal(t, []string{"第一句话。", "Hello world!！", "第三句"}, sentences)
+
+	key := tts.SentenceKey("Hello world!", "speaker_a", tts.DefaultResource, tts.Prosody{})
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, tts.SentenceKey("  Hello \n world! ", "speaker_a", tts.DefaultResource, tts.Prosody{}))
+	assert.NotEqual(t, key, tts.SentenceKey("Hello world!", "speaker_b", tts.DefaultResource, tts.Prosody{}))
+	assert.NotEqual(t, key, tts.SentenceKey("Hello world!", "speaker_a", "volc.megatts.default", tts.Prosody{}))
+}
+
+func TestTTSTimingCues(t *testing.T) {
+	sentences := []tts.TTSSentence{
+		{Text: "你好。", StartMs: 0, EndMs: 600, Words: []tts.TTSWord{{Word: "你", StartMs: 0, EndMs: 300}, {Word: "好", StartMs: 300, EndMs: 600}}},
+		{Text: "Hi.", StartMs: 700, EndMs: 900},
+	}
+	shifted := tts.ShiftSentences(sentences, 1000)
+	assert.Equal(t, int64(1300), shifted[0].Words[1].StartMs)
+	assert.Equal(t, int64(1900), shifted[1].EndMs)
+	assert.Equal(t, int64(300), sentences[0].Words[1].StartMs, "原切片不应被修改")
+
+	assert.Len(t, tts.TimingCues(shifted, false), 2)
+	words := tts.TimingCues(shifted, true)
+	assert.Equal(t, []string{"你", "好", "Hi."}, []string{words[0].Text, words[1].Text, words[2].Text})
+
+	vtt, err := subtitle.Render(subtitle.FormatVTT, words, subtitle.Options{})
+	assert.NoError(t, err)
+	assert.Contains(t, string(vtt), "00:00:01.300 --> 00:00:01.600\n好")
+}
+
+func TestTTSSynthesizeStream(t *testing.T) {
+	frames := []string{
+		`{"code":0,"data":"` + base64.StdEncoding.EncodeToString([]byte("abc")) + `"}`,
+		`{"code":0,"sentence":{"text":"你好","words":[{"word":"你","startTime":0.1,"endTime":0.3},{"word":"好","startTime":0.3,"endTime":0.55}]}}`,
+		`{"code":0,"data":"` + base64.StdEncoding.EncodeToString([]byte("def")) + `"}`,
+		`{"code":20000000,"message":"ok"}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "volc.service_type.10029", r.Header.Get("X-Api-Resource-Id"))
+		for _, f := range frames {
+			_, _ = w.Write([]byte(f + "\n"))
+		}
+	}))
+	defer srv.Close()
+	tts.SetVolcCreds(tts.VolcCreds{AppId: "app", AccessKey: "key"})
+	svc := tts.NewTTSSvc(srv.URL)
+
+	var chunks []string
+	resp, err := svc.SynthesizeStream(context.Background(), "你好", "zh_speaker", "volc.service_type.10029", tts.Prosody{}, func(chunk []byte) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abc", "def"}, chunks)
+	assert.Equal(t, "abcdef", string(resp.Audio))
+	assert.Equal(t, []tts.TTSSentence{{Text: "你好", StartMs: 100, EndMs: 550, Words: []tts.TTSWord{
+		{Word: "你", StartMs: 100, EndMs: 300}, {Word: "好", StartMs: 300, EndMs: 550},
+	}}}, resp.Sentences)
+
+	// 回调返回错误时中止
+	_, err = svc.SynthesizeStream(context.Background(), "你好", "zh_speaker", "volc.service_type.10029", tts.Prosody{}, func([]byte) error {
+		return context.Canceled
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTTSProsody(t *testing.T) {
+	// 倍率 1 等同默认，不改变缓存键
+	assert.Equal(t, "", tts.Prosody{SpeedRatio: 1, LoudnessRatio: 1, EmotionScale: 3}.Key())
+	base := tts.SentenceKey("你好", "s", tts.DefaultResource, tts.Prosody{})
+	assert.Equal(t, base, tts.SentenceKey("你好", "s", tts.DefaultResource, tts.Prosody{SpeedRatio: 1}))
+	assert.NotEqual(t, base, tts.SentenceKey("你好", "s", tts.DefaultResource, tts.Prosody{SpeedRatio: 1.2}))
+	assert.NotEqual(t, base, tts.SentenceKey("你好", "s", tts.DefaultResource, tts.Prosody{Emotion: "Happy"}))
+
+	var body struct {
+		ReqParams struct {
+			AudioParams map[string]any `json:"audio_params"`
+			Additions   string         `json:"additions"`
+		} `json:"req_params"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"code":0,"data":"` + base64.StdEncoding.EncodeToString([]byte("abc")) + `"}` + "\n"))
+		_, _ = w.Write([]byte(`{"code":20000000}` + "\n"))
+	}))
+	defer srv.Close()
+	tts.SetVolcCreds(tts.VolcCreds{AppId: "app", AccessKey: "key"})
+
+	prosody := tts.Prosody{SpeedRatio: 1.5, LoudnessRatio: 0.5, Pitch: -3, Emotion: "Happy", EmotionScale: 4}
+	_, err := tts.NewTTSSvc(srv.URL).SynthesizeWithResource(context.Background(), "你好", "zh_speaker", tts.DefaultResource, prosody)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(50), body.ReqParams.AudioParams["speech_rate"])
+	assert.Equal(t, float64(-50), body.ReqParams.AudioParams["loudness_rate"])
+	assert.Equal(t, "happy", body.ReqParams.AudioParams["emotion"])
+	assert.Equal(t, float64(4), body.ReqParams.AudioParams["emotion_scale"])
+	assert.Contains(t, body.ReqParams.Additions, `"post_process":{"pitch":-3}`)
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go-gin/logic"
+	"go-gin/model"
+	"go-gin/rest/tts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memSentenceStore 内存中的分句音频缓存
+type memSentenceStore struct {
+	mu    sync.Mutex
+	items map[string]model.TTSSentenceCache
+}
+
+func (s *memSentenceStore) FindByHashes(_ context.Context, hashes []string) ([]model.TTSSentenceCache, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []model.TTSSentenceCache
+	for _, h := range hashes {
+		if item, ok := s.items[h]; ok {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+func (s *memSentenceStore) Save(_ context.Context, item *model.TTSSentenceCache) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[item.Hash] = *item
+	return nil
+}
+
+func (s *memSentenceStore) Touch(context.Context, []string) error { return nil }
+
+func (s *memSentenceStore) PurgeUnused(context.Context, time.Time, int) (int64, error) { return 0, nil }
+
+// fakeTTSSvc 每次合成返回一帧 mp3
+type fakeTTSSvc struct {
+	mu    sync.Mutex
+	texts []string
+}
+
+func (f *fakeTTSSvc) Synthesize(ctx context.Context, text, speaker string) (*tts.TTSResp, error) {
+	return f.SynthesizeWithResource(ctx, text, speaker, tts.DefaultResource, tts.Prosody{})
+}
+
+func (f *fakeTTSSvc) SynthesizeWithResource(_ context.Context, text, _, _ string, _ tts.Prosody) (*tts.TTSResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.texts = append(f.texts, text)
+	return &tts.TTSResp{Audio: mp3Frame(1, byte(len(f.texts)))}, nil
+}
+
+func (f *fakeTTSSvc) SynthesizeStream(ctx context.Context, text, speaker, resourceId string, prosody tts.Prosody, onAudio func([]byte) error) (*tts.TTSResp, error) {
+	resp, err := f.SynthesizeWithResource(ctx, text, speaker, resourceId, prosody)
+	if err != nil {
+		return nil, err
+	}
+	return resp, onAudio(resp.Audio)
+}
+
+// recordProgress 记录进度回调
+type recordProgress struct {
+	mu      sync.Mutex
+	starts  [][2]int
+	updates []int
+}
+
+func (p *recordProgress) Start(total, done int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.starts = append(p.starts, [2]int{total, done})
+}
+
+func (p *recordProgress) Update(done int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.updates = append(p.updates, done)
+}
+
+func TestTTSSynthesizePlanProgressWithCacheHits(t *testing.T) {
+	ctx := context.Background()
+	const speaker = "zh_speaker"
+	cachedKey := tts.SentenceKey("第一句。", speaker, tts.DefaultResource, tts.Prosody{})
+	store := &memSentenceStore{items: map[string]model.TTSSentenceCache{
+		cachedKey: {Hash: cachedKey, Text: "第一句。", Audio: mp3Frame(1, 9)},
+	}}
+	logic.SetTTSSentenceStore(store)
+	defer logic.SetTTSSentenceStore(model.NewTTSSentenceCacheModel())
+	svc := &fakeTTSSvc{}
+	prev := tts.Svc
+	tts.Svc = svc
+	defer func() { tts.Svc = prev }()
+
+	l := logic.NewTTSLogic()
+	plan := l.PlanTTS(ctx, "第一句。第二句。第三句。", speaker, tts.DefaultResource, tts.Prosody{})
+	assert.Equal(t, 1, plan.Hits())
+	assert.Equal(t, len([]rune("第二句。第三句。")), plan.NewChars())
+
+	progress := &recordProgress{}
+	resp, err := l.SynthesizePlan(ctx, plan, progress)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Audio, 3*192)
+	assert.ElementsMatch(t, []string{"第二句。", "第三句。"}, svc.texts)
+	// 新合成的分句在计费后才写回缓存，合成本身不写
+	assert.Len(t, store.items, 1)
+
+	// 命中缓存的片段在开始时就计入已完成，任务必须先被标记为开始再更新进度
+	assert.Equal(t, [][2]int{{3, 1}}, progress.starts)
+	assert.Equal(t, []int{2, 3}, progress.updates)
+}
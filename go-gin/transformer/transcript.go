@@ -0,0 +1,24 @@
+package transformer
+
+import (
+	"go-gin/model"
+	"go-gin/typing"
+)
+
+// ConvertSegmentsToItems names 为说话人显示名，未改名的说话人显示为 Speaker A
+func ConvertSegmentsToItems(segments []model.YoutubeTranscriptSegment, names map[string]string) []typing.YtSegmentItem {
+	resp := make([]typing.YtSegmentItem, 0, len(segments))
+	for _, v := range segments {
+		resp = append(resp, typing.YtSegmentItem{
+			Index:          v.SegIndex,
+			StartMs:        v.StartMs,
+			EndMs:          v.EndMs,
+			OriginalText:   v.OriginalText,
+			TranslatedText: v.TranslatedText,
+			Confidence:     v.Confidence,
+			Speaker:        v.Speaker,
+			SpeakerName:    model.SpeakerDisplayName(v.Speaker, names),
+		})
+	}
+	return resp
+}
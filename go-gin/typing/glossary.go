@@ -0,0 +1,77 @@
+package typing
+
+type GlossaryTermReq struct {
+	SourceTerm    string `json:"source_term" binding:"required,max=255" label:"原文术语"`
+	TargetTerm    string `json:"target_term" binding:"required,max=255" label:"指定译法"`
+	CaseSensitive bool   `json:"case_sensitive" label:"区分大小写"`
+}
+
+type GlossaryTermUpdateReq struct {
+	Id            int64  `uri:"id" binding:"required" label:"术语ID"`
+	SourceTerm    string `json:"source_term" binding:"omitempty,max=255" label:"原文术语"`
+	TargetTerm    string `json:"target_term" binding:"omitempty,max=255" label:"指定译法"`
+	CaseSensitive *bool  `json:"case_sensitive" label:"区分大小写"`
+}
+
+type GlossaryTermIdReq struct {
+	Id int64 `uri:"id" binding:"required" label:"术语ID"`
+}
+
+type GlossaryTermItem struct {
+	Id            int64  `json:"id"`
+	SourceTerm    string `json:"source_term"`
+	TargetTerm    string `json:"target_term"`
+	CaseSensitive bool   `json:"case_sensitive"`
+	Owner         string `json:"owner"`
+	Shared        bool   `json:"shared"` // 来自团队成员共享的术语，只读
+	UpdatedAt     string `json:"updated_at"`
+}
+
+type GlossaryListReply struct {
+	Version string             `json:"version"` // 生效术语集合的版本，术语变化时改变
+	Items   []GlossaryTermItem `json:"items"`
+}
+
+type GlossaryImportReply struct {
+	Created int      `json:"created"`
+	Updated int      `json:"updated"`
+	Skipped int      `json:"skipped"`
+	Errors  []string `json:"errors"` // 被跳过的行及原因
+}
+
+type GlossaryMemberReq struct {
+	Identity string `json:"identity" binding:"required,max=64" label:"成员"`
+}
+
+type GlossaryMemberUriReq struct {
+	Identity string `uri:"identity" binding:"required" label:"成员"`
+}
+
+type GlossaryOwnerUriReq struct {
+	Owner string `uri:"owner" binding:"required" label:"所有者"`
+}
+
+type GlossaryMemberItem struct {
+	Identity  string `json:"identity"`
+	Status    string `json:"status"` // pending 待对方接受/accepted 已接受
+	CreatedAt string `json:"created_at"`
+}
+
+type GlossaryMembersReply struct {
+	Members      []GlossaryMemberItem `json:"members"`        // 我共享给的成员
+	SharedWithMe []string             `json:"shared_with_me"` // 共享给我且已接受的所有者
+	Invites      []string             `json:"invites"`        // 邀请我、待我接受的所有者
+}
+
+type GlossaryViolationItem struct {
+	SegIndex int    `json:"seg_index"` // -1 表示整段文本
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+}
+
+type GlossaryCheckReply struct {
+	TranscriptId int64                   `json:"transcript_id"`
+	Version      string                  `json:"version"`
+	Terms        int                     `json:"terms"` // 原文中出现的术语数
+	Violations   []GlossaryViolationItem `json:"violations"`
+}
@@ -0,0 +1,23 @@
+package typing
+
+import "go-gin/model"
+
+type PipelineListReq struct {
+	Status string `form:"status" binding:"omitempty,oneof=running done failed" label:"状态"`
+	Page   int    `form:"page" label:"页码"`
+	Size   int    `form:"size" label:"每页数量"`
+}
+
+type PipelineReq struct {
+	Id int64 `uri:"id" binding:"required" label:"流水线ID"`
+}
+
+type PipelineDetailReply struct {
+	Run    *model.PipelineRun    `json:"run"`
+	Stages []model.PipelineStage `json:"stages"`
+}
+
+type PipelineRedriveReply struct {
+	RunId int64  `json:"run_id"`
+	JobId string `json:"job_id"`
+}
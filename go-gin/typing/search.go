@@ -0,0 +1,47 @@
+package typing
+
+type SearchReq struct {
+	Q string `form:"q" binding:"required,max=100" label:"搜索词"`
+	// Type 搜索范围：all | transcript | tts（默认 all）
+	Type  string `form:"type" binding:"omitempty,oneof=all transcript tts" label:"搜索范围"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50" label:"条数"`
+}
+
+// SearchSegmentHit 命中的分句，Url 为带时间点的原视频链接
+type SearchSegmentHit struct {
+	Index   int    `json:"index"`
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+	Start   string `json:"start"` // 如 05:23 或 1:02:03
+	Snippet string `json:"snippet"`
+	Url     string `json:"url"`
+}
+
+type SearchTranscriptHit struct {
+	TranscriptId int64              `json:"transcript_id"`
+	Language     string             `json:"language"`
+	SourceSite   string             `json:"source_site"`
+	VideoId      string             `json:"video_id"`
+	Title        string             `json:"title"` // 已高亮
+	Snippet      string             `json:"snippet"`
+	Url          string             `json:"url"`
+	Segments     []SearchSegmentHit `json:"segments"` // 按时间排序，最多 3 条
+	Score        float64            `json:"score"`
+}
+
+type SearchTTSHit struct {
+	Id        int64   `json:"id"`
+	Speaker   string  `json:"speaker"`
+	Snippet   string  `json:"snippet"`
+	AudioUrl  string  `json:"audio_url"`
+	CreatedAt string  `json:"created_at"`
+	Score     float64 `json:"score"`
+}
+
+// SearchReply 片段中的命中词以 <em></em> 包裹，其余文本已做 HTML 转义
+type SearchReply struct {
+	Query       string                `json:"query"`
+	Terms       []string              `json:"terms"`
+	Transcripts []SearchTranscriptHit `json:"transcripts"`
+	TTS         []SearchTTSHit        `json:"tts"`
+}
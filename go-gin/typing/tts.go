@@ -1,11 +1,87 @@
 package typing
 
+import "go-gin/rest/tts"
+
+// TTSProsody 合成韵律参数，不传时使用音色默认
+type TTSProsody struct {
+	SpeedRatio    float64 `form:"speed_ratio" json:"speed_ratio" binding:"omitempty,gte=0.5,lte=2" label:"语速"`
+	LoudnessRatio float64 `form:"loudness_ratio" json:"loudness_ratio" binding:"omitempty,gte=0.5,lte=2" label:"音量"`
+	Pitch         int     `form:"pitch" json:"pitch" binding:"omitempty,gte=-12,lte=12" label:"音调"`
+	Emotion       string  `form:"emotion" json:"emotion" binding:"omitempty,max=32,alphanum" label:"情感"`
+	EmotionScale  int     `form:"emotion_scale" json:"emotion_scale" binding:"omitempty,gte=1,lte=5" label:"情感强度"`
+}
+
+func (p TTSProsody) Prosody() tts.Prosody {
+	return tts.Prosody{
+		SpeedRatio:    p.SpeedRatio,
+		LoudnessRatio: p.LoudnessRatio,
+		Pitch:         p.Pitch,
+		Emotion:       p.Emotion,
+		EmotionScale:  p.EmotionScale,
+	}.Normalize()
+}
+
 type TTSSynthesizeReq struct {
 	Text       string `form:"text" binding:"required" label:"文本"`
 	Speaker    string `form:"speaker" binding:"required" label:"说话人"`
 	UseMyVoice bool   `form:"use_my_voice" json:"use_my_voice"`
+	TTSProsody
+}
+
+type TTSStreamReq struct {
+	Text       string `form:"text" binding:"required" label:"文本"`
+	Speaker    string `form:"speaker" binding:"required" label:"说话人"`
+	UseMyVoice bool   `form:"use_my_voice" json:"use_my_voice"`
+	Format     string `form:"format" binding:"omitempty,oneof=mpeg sse" label:"输出格式"` // mpeg 直接输出 audio/mpeg（默认），sse 输出 base64 音频帧事件
+	TTSProsody
+}
+
+// TTSStreamDone 流式合成结束时 SSE done 事件的数据
+type TTSStreamDone struct {
+	Id          int64  `json:"id"`
+	AudioUrl    string `json:"audio_url"`
+	CharCount   int    `json:"char_count"`
+	BilledChars int    `json:"billed_chars"`
+	DurationMs  int64  `json:"duration_ms"`
+	Cached      bool   `json:"cached"` // 命中历史记录，未推送音频帧，直接使用 audio_url
 }
 
 type TTSSynthesizeReply struct {
 	AudioUrl string `json:"audio_url"`
 }
+
+type TTSJobReq struct {
+	JobId string `uri:"id" binding:"required" label:"任务ID"`
+}
+
+type TTSJobSubmitReply struct {
+	JobId     string `json:"job_id"`
+	Status    string `json:"status"`
+	CharCount int    `json:"char_count"`
+	Async     bool   `json:"async"` // 恒为 true，区别于同步合成的返回
+}
+
+type TTSJobReply struct {
+	JobId       string `json:"job_id"`
+	Status      string `json:"status"` // queued/running/done/failed
+	CharCount   int    `json:"char_count"`
+	TotalChunks int    `json:"total_chunks"`
+	DoneChunks  int    `json:"done_chunks"`
+	Progress    int    `json:"progress"` // 0~100
+	HistoryId   int64  `json:"history_id,omitempty"`
+	AudioUrl    string `json:"audio_url,omitempty"`
+	ErrorCode   int    `json:"error_code,omitempty"`
+	ErrorMsg    string `json:"error_msg,omitempty"`
+}
+
+type TTSTimingsReq struct {
+	Id     int64  `uri:"id" binding:"required" label:"合成记录ID"`
+	Format string `form:"format" binding:"omitempty,oneof=json vtt" label:"格式"`
+	Level  string `form:"level" binding:"omitempty,oneof=sentence word" label:"字幕粒度"` // 仅 vtt：sentence 每句一条，word 每词一条
+}
+
+type TTSTimingsReply struct {
+	Id         int64             `json:"id"`
+	DurationMs int64             `json:"duration_ms"`
+	Sentences  []tts.TTSSentence `json:"sentences"` // 旧记录没有时间戳时为空数组
+}
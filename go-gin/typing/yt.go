@@ -1,5 +1,7 @@
 package typing
 
+import "go-gin/util/textdiff"
+
 type YtInfoReq struct {
 	IdOrUrl  string `form:"id_or_url" json:"id_or_url" binding:"required" label:"视频ID或链接"`
 	Platform string `form:"platform" json:"platform" binding:"omitempty" label:"平台类型"`
@@ -16,11 +18,241 @@ type YtInfoReply struct {
 }
 
 type YtTextReq struct {
-	IdOrUrl   string `form:"id_or_url" json:"id_or_url" binding:"required" label:"视频ID或链接"`
-	TargetLan string `form:"target_lang" json:"target_lang" binding:"omitempty" label:"目标语言"`
-	Platform  string `form:"platform" json:"platform" binding:"omitempty" label:"平台类型"`
+	IdOrUrl    string `form:"id_or_url" json:"id_or_url" binding:"required" label:"视频ID或链接"`
+	TargetLan  string `form:"target_lang" json:"target_lang" binding:"omitempty" label:"目标语言"`
+	SourceLang string `form:"source_lang" json:"source_lang" binding:"omitempty" label:"源语言"` // auto 或语言代码
+	Platform   string `form:"platform" json:"platform" binding:"omitempty" label:"平台类型"`
+	// ForceRefresh 忽略已有转录，重新识别与翻译
+	ForceRefresh bool `form:"force_refresh" json:"force_refresh"`
 }
 
 type YtTextReply struct {
+	TranscriptId   int64  `json:"transcript_id"`
+	DetectedLang   string `json:"detected_lang"`
+	TextSource     string `json:"text_source"` // asr | manual_caption | auto_caption
 	TranslatedText string `json:"translated_text"`
 }
+
+type YtJobReq struct {
+	JobId string `uri:"id" binding:"required" label:"任务ID"`
+}
+
+type YtJobSubmitReply struct {
+	JobId string `json:"job_id"`
+	Stage string `json:"stage"`
+}
+
+type YtJobReply struct {
+	JobId          string `json:"job_id"`
+	Stage          string `json:"stage"`
+	ErrorCode      int    `json:"error_code"`
+	ErrorMsg       string `json:"error_msg"`
+	TranscriptId   int64  `json:"transcript_id"`
+	TranslatedText string `json:"translated_text"`
+}
+
+type YtTranscriptReq struct {
+	Id int64 `uri:"id" binding:"required" label:"转录ID"`
+}
+
+type YtSegmentItem struct {
+	Index          int     `json:"index"`
+	StartMs        int64   `json:"start_ms"`
+	EndMs          int64   `json:"end_ms"`
+	OriginalText   string  `json:"original_text"`
+	TranslatedText string  `json:"translated_text"`
+	Confidence     float64 `json:"confidence"`
+	Speaker        string  `json:"speaker"`      // 说话人标识，未分离时为空
+	SpeakerName    string  `json:"speaker_name"` // 显示名，未改名时为 Speaker A
+}
+
+type YtSegmentsReply struct {
+	TranscriptId int64           `json:"transcript_id"`
+	Items        []YtSegmentItem `json:"items"`
+}
+
+type YtSubtitleReq struct {
+	Id     int64  `uri:"id" binding:"required" label:"转录ID"`
+	Format string `form:"format" binding:"omitempty,oneof=srt vtt ass" label:"字幕格式"`
+	Track  string `form:"track" binding:"omitempty,oneof=original translated bilingual" label:"字幕轨道"`
+}
+
+type YtSpeakerItem struct {
+	Speaker  string `json:"speaker"`
+	Name     string `json:"name"` // 显示名，未改名时为 Speaker A
+	Renamed  bool   `json:"renamed"`
+	Segments int    `json:"segments"` // 该说话人的分句数
+}
+
+type YtSpeakersReply struct {
+	TranscriptId int64           `json:"transcript_id"`
+	Items        []YtSpeakerItem `json:"items"`
+}
+
+type YtRenameSpeakersReq struct {
+	Id int64 `uri:"id" binding:"required" label:"转录ID"`
+	// Names 说话人标识到显示名，显示名为空表示恢复默认
+	Names map[string]string `json:"names" label:"说话人名称"`
+}
+
+type YtReviewReq struct {
+	Id int64 `uri:"id" binding:"required" label:"转录ID"`
+	// Threshold 置信度阈值（1~100），低于该值的词需要复核；为空时使用配置的默认值
+	Threshold int `form:"threshold" binding:"omitempty,min=1,max=100" label:"置信度阈值"`
+}
+
+type YtReviewWord struct {
+	SegIndex   int    `json:"seg_index"`
+	Text       string `json:"text"`
+	StartMs    int64  `json:"start_ms"`
+	EndMs      int64  `json:"end_ms"`
+	Confidence int    `json:"confidence"`
+}
+
+// YtReviewSpan 同一分句内相邻低置信度词合并成的片段；分句没有词级信息时为整句
+type YtReviewSpan struct {
+	SegIndex      int    `json:"seg_index"`
+	StartMs       int64  `json:"start_ms"`
+	EndMs         int64  `json:"end_ms"`
+	Text          string `json:"text"`
+	MinConfidence int    `json:"min_confidence"`
+	Words         int    `json:"words"`
+}
+
+type YtReviewReply struct {
+	TranscriptId int64          `json:"transcript_id"`
+	Threshold    int            `json:"threshold"`
+	ScoredWords  int            `json:"scored_words"` // 带置信度的词数
+	Words        []YtReviewWord `json:"words"`
+	Spans        []YtReviewSpan `json:"spans"`
+}
+
+// YtSegmentEdit 单个分句的修改，字段为空表示不修改
+type YtSegmentEdit struct {
+	Index          int     `json:"index"`
+	OriginalText   *string `json:"original_text"`
+	TranslatedText *string `json:"translated_text"`
+}
+
+// YtTranscriptEditReq 修改转录：按分句修改后重写整段文本；整段修改仅限没有分句的转录
+type YtTranscriptEditReq struct {
+	Id             int64           `uri:"id" binding:"required" label:"转录ID"`
+	OriginalText   *string         `json:"original_text" label:"原文"`
+	TranslatedText *string         `json:"translated_text" label:"译文"`
+	Segments       []YtSegmentEdit `json:"segments" label:"分句"`
+	Note           string          `json:"note" binding:"omitempty,max=255" label:"修改说明"`
+}
+
+type YtRevisionItem struct {
+	RevNo     int    `json:"rev_no"`
+	Source    string `json:"source"` // auto | user | restore
+	Author    string `json:"author"` // 调用者本人的修订为其身份，其他用户为 other，自动生成为空
+	Note      string `json:"note"`
+	CreatedAt string `json:"created_at"`
+	Current   bool   `json:"current"` // 是否为当前生效的修订
+}
+
+type YtRevisionsReply struct {
+	TranscriptId int64            `json:"transcript_id"`
+	Edited       bool             `json:"edited"` // 被用户编辑过的转录不会被自动刷新覆盖
+	Items        []YtRevisionItem `json:"items"`
+}
+
+type YtTranscriptEditReply struct {
+	TranscriptId   int64  `json:"transcript_id"` // 用户编辑副本的ID，首次修改共享转录时会变化
+	RevNo          int    `json:"rev_no"`
+	OriginalText   string `json:"original_text"`
+	TranslatedText string `json:"translated_text"`
+}
+
+type YtRevisionReq struct {
+	Id    int64 `uri:"id" binding:"required" label:"转录ID"`
+	RevNo int   `uri:"rev" binding:"required" label:"修订号"`
+}
+
+type YtRevisionDiffReq struct {
+	Id int64 `uri:"id" binding:"required" label:"转录ID"`
+	// From 起始修订号（与 uri 共用结构体，先绑定 uri 时尚无查询参数，必填在逻辑层校验）
+	From int `form:"from" label:"起始修订号"`
+	// To 为空时与当前文本比较
+	To int `form:"to" binding:"omitempty,min=1" label:"目标修订号"`
+}
+
+type YtSegmentDiff struct {
+	Index          int           `json:"index"`
+	OriginalText   []textdiff.Op `json:"original_text,omitempty"`
+	TranslatedText []textdiff.Op `json:"translated_text,omitempty"`
+}
+
+type YtRevisionDiffReply struct {
+	TranscriptId   int64           `json:"transcript_id"`
+	From           int             `json:"from"`
+	To             int             `json:"to"` // 0 表示当前文本
+	OriginalText   []textdiff.Op   `json:"original_text"`
+	TranslatedText []textdiff.Op   `json:"translated_text"`
+	Segments       []YtSegmentDiff `json:"segments"` // 仅包含有变化的分句
+}
+
+type YtSummarizeReq struct {
+	Id int64 `uri:"id" binding:"required" label:"转录ID"`
+	// Language 摘要语言，为空时使用转录的目标语言
+	Language string `json:"language" binding:"omitempty,max=16" label:"摘要语言"`
+	// Force 忽略缓存重新生成
+	Force bool `json:"force" label:"重新生成"`
+}
+
+type YtChapterItem struct {
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+	Start   string `json:"start"` // 如 05:23 或 1:02:03，便于展示与跳转
+	Title   string `json:"title"`
+	Summary string `json:"summary,omitempty"`
+}
+
+type YtSummaryReply struct {
+	TranscriptId int64           `json:"transcript_id"`
+	Language     string          `json:"language"`
+	Summary      string          `json:"summary"`
+	KeyPoints    []string        `json:"key_points"`
+	Chapters     []YtChapterItem `json:"chapters"`
+	Cached       bool            `json:"cached"` // 命中缓存时不计费
+	Tokens       int             `json:"tokens"` // 本次计费的 token 数
+	UpdatedAt    string          `json:"updated_at"`
+}
+
+type YtAskReq struct {
+	Id int64 `uri:"id" binding:"required" label:"转录ID"`
+	// Question 问题（与 uri 共用结构体，非空在逻辑层校验）
+	Question string `json:"question" binding:"max=500" label:"问题"`
+}
+
+type YtAskCitation struct {
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+	Start   string `json:"start"` // 如 05:23 或 1:02:03，便于展示与跳转
+	Text    string `json:"text"`
+}
+
+type YtAskReply struct {
+	Id           int64           `json:"id"`
+	TranscriptId int64           `json:"transcript_id"`
+	Question     string          `json:"question"`
+	Answer       string          `json:"answer"`
+	Citations    []YtAskCitation `json:"citations"`
+	Retrieval    string          `json:"retrieval"` // full | bm25 | embedding
+	Tokens       int             `json:"tokens"`
+	CreatedAt    string          `json:"created_at"`
+}
+
+type YtQuestionsReq struct {
+	Id int64 `uri:"id" binding:"required" label:"转录ID"`
+	// BeforeId 翻页游标，返回 id 更小的记录
+	BeforeId int64 `form:"before_id" binding:"omitempty,min=0" label:"游标"`
+	Limit    int   `form:"limit" binding:"omitempty,min=1,max=100" label:"条数"`
+}
+
+type YtQuestionsReply struct {
+	TranscriptId int64        `json:"transcript_id"`
+	Items        []YtAskReply `json:"items"` // 按时间倒序
+	HasMore      bool         `json:"has_more"`
+}
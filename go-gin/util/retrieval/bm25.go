@@ -0,0 +1,157 @@
+package retrieval
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 参数，取常用默认值
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Tokenize 切分检索词：拉丁字母与数字按词切分并转小写，中日韩文字按单字与相邻二字切分
+func Tokenize(text string) []string {
+	var tokens []string
+	var word []rune
+	var prev rune
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			tokens = append(tokens, string(r))
+			if prev != 0 {
+				tokens = append(tokens, string([]rune{prev, r}))
+			}
+			prev = r
+			continue
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			word = append(word, r)
+		default:
+			flushWord()
+		}
+		prev = 0
+	}
+	flushWord()
+	return tokens
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
+
+// Index 文档集合上的 BM25 索引
+type Index struct {
+	docs   []map[string]int
+	lens   []int
+	avgLen float64
+	df     map[string]int
+}
+
+// NewIndex 为文档建立索引，文档下标即 Search 返回的下标
+func NewIndex(docs []string) *Index {
+	idx := &Index{docs: make([]map[string]int, len(docs)), lens: make([]int, len(docs)), df: map[string]int{}}
+	total := 0
+	for i, doc := range docs {
+		tf := map[string]int{}
+		tokens := Tokenize(doc)
+		for _, t := range tokens {
+			tf[t]++
+		}
+		for t := range tf {
+			idx.df[t]++
+		}
+		idx.docs[i] = tf
+		idx.lens[i] = len(tokens)
+		total += len(tokens)
+	}
+	if len(docs) > 0 {
+		idx.avgLen = float64(total) / float64(len(docs))
+	}
+	return idx
+}
+
+// Hit 检索结果
+type Hit struct {
+	Index int
+	Score float64
+}
+
+// Search 返回得分最高的 k 个文档（得分为 0 的不返回），按得分降序
+func (idx *Index) Search(query string, k int) []Hit {
+	terms := map[string]bool{}
+	for _, t := range Tokenize(query) {
+		terms[t] = true
+	}
+	n := float64(len(idx.docs))
+	var hits []Hit
+	for i, tf := range idx.docs {
+		score := 0.0
+		for t := range terms {
+			f := float64(tf[t])
+			if f == 0 {
+				continue
+			}
+			df := float64(idx.df[t])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			score += idf * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*float64(idx.lens[i])/idx.avgLen))
+		}
+		if score > 0 {
+			hits = append(hits, Hit{Index: i, Score: score})
+		}
+	}
+	sortHits(hits)
+	if k > 0 && len(hits) > k {
+		hits = hits[:k]
+	}
+	return hits
+}
+
+// Cosine 余弦相似度，任一向量为零向量或维度不同时返回 0
+func Cosine(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// RankByVector 按与 query 的余弦相似度排序，返回前 k 个
+func RankByVector(query []float64, vectors [][]float64, k int) []Hit {
+	hits := make([]Hit, 0, len(vectors))
+	for i, v := range vectors {
+		hits = append(hits, Hit{Index: i, Score: Cosine(query, v)})
+	}
+	sortHits(hits)
+	if k > 0 && len(hits) > k {
+		hits = hits[:k]
+	}
+	return hits
+}
+
+// sortHits 得分降序，同分时下标小的在前
+func sortHits(hits []Hit) {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Index < hits[j].Index
+	})
+}
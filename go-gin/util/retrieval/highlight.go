@@ -0,0 +1,94 @@
+package retrieval
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// 高亮标记，其余文本会做 HTML 转义，前端可直接渲染
+const (
+	HighlightOpen  = "<em>"
+	HighlightClose = "</em>"
+)
+
+// Snippet 截取第一个命中词附近 radius 个字符的片段并高亮所有命中词（不区分大小写）；
+// 没有命中时返回开头的片段，截断处以 … 标记
+func Snippet(text string, terms []string, radius int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	spans := matchSpans(runes, terms)
+
+	start, end := 0, len(runes)
+	if len(spans) > 0 {
+		start = max(0, spans[0][0]-radius)
+		end = min(len(runes), spans[0][1]+radius)
+	} else if end > 2*radius {
+		end = 2 * radius
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	pos := start
+	for _, sp := range spans {
+		if sp[1] <= start || sp[0] >= end {
+			continue
+		}
+		s, e := max(sp[0], start), min(sp[1], end)
+		sb.WriteString(html.EscapeString(string(runes[pos:s])))
+		sb.WriteString(HighlightOpen)
+		sb.WriteString(html.EscapeString(string(runes[s:e])))
+		sb.WriteString(HighlightClose)
+		pos = e
+	}
+	sb.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+// matchSpans 返回所有命中区间 [start, end)（按 rune 下标），重叠的区间会合并
+func matchSpans(runes []rune, terms []string) [][2]int {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	mark := make([]bool, len(runes))
+	for _, term := range terms {
+		t := []rune(strings.ToLower(strings.TrimSpace(term)))
+		if len(t) == 0 {
+			continue
+		}
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if equalRunes(lower[i:i+len(t)], t) {
+				for j := i; j < i+len(t); j++ {
+					mark[j] = true
+				}
+			}
+		}
+	}
+	var spans [][2]int
+	for i := 0; i < len(mark); i++ {
+		if !mark[i] {
+			continue
+		}
+		j := i
+		for j < len(mark) && mark[j] {
+			j++
+		}
+		spans = append(spans, [2]int{i, j})
+		i = j
+	}
+	return spans
+}
+
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,187 @@
+package subtitle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Parse 按扩展名解析字幕文件为字幕条目，支持 vtt、srt 与 YouTube srv3
+func Parse(ext string, data []byte) ([]Cue, error) {
+	switch strings.ToLower(strings.TrimPrefix(ext, ".")) {
+	case "vtt":
+		return ParseVTT(data)
+	case "srt":
+		return ParseSRT(data)
+	case "srv3":
+		return ParseSRV3(data)
+	}
+	return nil, fmt.Errorf("subtitle: unsupported input format %q", ext)
+}
+
+var (
+	// 00:01:02.345 或 01:02.345（VTT 小时可省略），SRT 用逗号分隔毫秒
+	timingRe = regexp.MustCompile(`^\s*((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})\s*-->\s*((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})`)
+	// VTT 内联标签：<c>、</c>、<00:00:01.500>、<v Speaker> 等
+	tagRe = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ParseVTT 解析 WebVTT；YouTube 自动字幕的滚动重复行会被去除
+func ParseVTT(data []byte) ([]Cue, error) {
+	blocks := splitBlocks(data)
+	if len(blocks) == 0 || !strings.HasPrefix(strings.TrimPrefix(blocks[0][0], "\ufeff"), "WEBVTT") {
+		return nil, fmt.Errorf("subtitle: missing WEBVTT header")
+	}
+	var cues []Cue
+	lastLine := ""
+	for _, block := range blocks[1:] {
+		start, end, lines, ok := parseCueBlock(block)
+		if !ok {
+			continue // NOTE、STYLE、REGION 等非字幕块
+		}
+		var kept []string
+		for _, line := range lines {
+			line = cleanCueLine(line)
+			if line == "" {
+				continue
+			}
+			// 滚动字幕会在下一条开头重复上一条的最后一行
+			if len(kept) == 0 && line == lastLine {
+				continue
+			}
+			kept = append(kept, line)
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		lastLine = kept[len(kept)-1]
+		cues = append(cues, Cue{StartMs: start, EndMs: end, Text: strings.Join(kept, " ")})
+	}
+	return cues, nil
+}
+
+// ParseSRT 解析 SubRip
+func ParseSRT(data []byte) ([]Cue, error) {
+	var cues []Cue
+	for _, block := range splitBlocks(data) {
+		start, end, lines, ok := parseCueBlock(block)
+		if !ok {
+			continue
+		}
+		var kept []string
+		for _, line := range lines {
+			if line = cleanCueLine(line); line != "" {
+				kept = append(kept, line)
+			}
+		}
+		if len(kept) > 0 {
+			cues = append(cues, Cue{StartMs: start, EndMs: end, Text: strings.Join(kept, " ")})
+		}
+	}
+	return cues, nil
+}
+
+// srv3 YouTube timedtext format=3：<p t="起始毫秒" d="时长毫秒">文本<s>词</s></p>
+type srv3Doc struct {
+	Body struct {
+		Paragraphs []struct {
+			T     int64  `xml:"t,attr"`
+			D     int64  `xml:"d,attr"`
+			Inner string `xml:",innerxml"`
+		} `xml:"p"`
+	} `xml:"body"`
+}
+
+// ParseSRV3 解析 YouTube srv3 字幕
+func ParseSRV3(data []byte) ([]Cue, error) {
+	var doc srv3Doc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("subtitle: parse srv3: %w", err)
+	}
+	var cues []Cue
+	for _, p := range doc.Body.Paragraphs {
+		text := strings.Join(strings.Fields(cleanCueLine(p.Inner)), " ")
+		if text == "" {
+			continue
+		}
+		cues = append(cues, Cue{StartMs: p.T, EndMs: p.T + p.D, Text: text})
+	}
+	return cues, nil
+}
+
+// splitBlocks 按空行切分，返回每块的非空行
+func splitBlocks(data []byte) [][]string {
+	var blocks [][]string
+	var cur []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r ")
+		if strings.TrimSpace(line) == "" {
+			if len(cur) > 0 {
+				blocks = append(blocks, cur)
+				cur = nil
+			}
+			continue
+		}
+		cur = append(cur, line)
+	}
+	if len(cur) > 0 {
+		blocks = append(blocks, cur)
+	}
+	return blocks
+}
+
+// parseCueBlock 在块中查找时间轴行，返回其后的文本行
+func parseCueBlock(block []string) (int64, int64, []string, bool) {
+	for i, line := range block {
+		m := timingRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		start, ok1 := parseClock(m[1])
+		end, ok2 := parseClock(m[2])
+		if !ok1 || !ok2 {
+			return 0, 0, nil, false
+		}
+		return start, end, block[i+1:], true
+	}
+	return 0, 0, nil, false
+}
+
+// parseClock 解析 [HH:]MM:SS.mmm / HH:MM:SS,mmm
+func parseClock(s string) (int64, bool) {
+	s = strings.Replace(s, ",", ".", 1)
+	main, frac, _ := strings.Cut(s, ".")
+	parts := strings.Split(main, ":")
+	var ms int64
+	for _, p := range parts {
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		ms = ms*60 + n
+	}
+	ms *= 1000
+	if frac != "" {
+		for len(frac) < 3 {
+			frac += "0"
+		}
+		n, err := strconv.ParseInt(frac[:3], 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		ms += n
+	}
+	return ms, true
+}
+
+// cleanCueLine 去除内联标签并反转义 HTML 实体
+func cleanCueLine(line string) string {
+	return strings.TrimSpace(html.UnescapeString(tagRe.ReplaceAllString(line, "")))
+}
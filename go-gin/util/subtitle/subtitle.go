@@ -0,0 +1,227 @@
+package subtitle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format 字幕格式
+type Format string
+
+const (
+	FormatSRT Format = "srt"
+	FormatVTT Format = "vtt"
+	FormatASS Format = "ass"
+)
+
+// DefaultLineWidth 每行最大显示宽度（半角计 1，全角计 2）
+const DefaultLineWidth = 42
+
+// minCueDurationMs 结束时间不晚于开始时间时补齐的最短时长
+const minCueDurationMs = 1000
+
+// Cue 一条字幕；Text 中的 "\n" 表示强制换行（如双语字幕的两段）
+type Cue struct {
+	StartMs int64
+	EndMs   int64
+	Text    string
+}
+
+// Options 输出选项
+type Options struct {
+	LineWidth int    // 自动折行宽度，<=0 时使用 DefaultLineWidth
+	Title     string // ASS 标题
+}
+
+// ParseFormat 解析格式字符串，空字符串默认为 srt
+func ParseFormat(s string) (Format, bool) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case "", FormatSRT:
+		return FormatSRT, true
+	case FormatVTT:
+		return FormatVTT, true
+	case FormatASS:
+		return FormatASS, true
+	}
+	return "", false
+}
+
+// ContentType 返回下载时使用的 Content-Type
+func (f Format) ContentType() string {
+	switch f {
+	case FormatVTT:
+		return "text/vtt; charset=utf-8"
+	case FormatASS:
+		return "text/x-ssa; charset=utf-8"
+	default:
+		return "application/x-subrip; charset=utf-8"
+	}
+}
+
+// Ext 返回文件扩展名（不含点）
+func (f Format) Ext() string { return string(f) }
+
+// Write 按格式输出字幕，空文本的字幕会被跳过，序号连续
+func Write(w io.Writer, f Format, cues []Cue, opt Options) error {
+	if opt.LineWidth <= 0 {
+		opt.LineWidth = DefaultLineWidth
+	}
+	bw := bufio.NewWriter(w)
+	switch f {
+	case FormatSRT:
+		writeSRT(bw, cues, opt)
+	case FormatVTT:
+		writeVTT(bw, cues, opt)
+	case FormatASS:
+		writeASS(bw, cues, opt)
+	default:
+		return fmt.Errorf("subtitle: unsupported format %q", f)
+	}
+	return bw.Flush()
+}
+
+// Render 按格式输出为字节
+func Render(f Format, cues []Cue, opt Options) ([]byte, error) {
+	var sb strings.Builder
+	if err := Write(&sb, f, cues, opt); err != nil {
+		return nil, err
+	}
+	return []byte(sb.String()), nil
+}
+
+func writeSRT(w *bufio.Writer, cues []Cue, opt Options) {
+	n := 0
+	for _, c := range cues {
+		lines := cueLines(c.Text, opt.LineWidth)
+		if len(lines) == 0 {
+			continue
+		}
+		n++
+		start, end := cueRange(c)
+		fmt.Fprintf(w, "%d\n%s --> %s\n", n, formatClock(start, ","), formatClock(end, ","))
+		for _, line := range lines {
+			w.WriteString(line)
+			w.WriteString("\n")
+		}
+		w.WriteString("\n")
+	}
+}
+
+func writeVTT(w *bufio.Writer, cues []Cue, opt Options) {
+	w.WriteString("WEBVTT\n\n")
+	n := 0
+	for _, c := range cues {
+		lines := cueLines(c.Text, opt.LineWidth)
+		if len(lines) == 0 {
+			continue
+		}
+		n++
+		start, end := cueRange(c)
+		fmt.Fprintf(w, "%d\n%s --> %s\n", n, formatClock(start, "."), formatClock(end, "."))
+		for _, line := range lines {
+			w.WriteString(escapeVTT(line))
+			w.WriteString("\n")
+		}
+		w.WriteString("\n")
+	}
+}
+
+func writeASS(w *bufio.Writer, cues []Cue, opt Options) {
+	title := opt.Title
+	if title == "" {
+		title = "Transcript"
+	}
+	w.WriteString("[Script Info]\n")
+	fmt.Fprintf(w, "Title: %s\n", strings.ReplaceAll(title, "\n", " "))
+	w.WriteString("ScriptType: v4.00+\n")
+	w.WriteString("WrapStyle: 0\n")
+	w.WriteString("ScaledBorderAndShadow: yes\n")
+	w.WriteString("PlayResX: 1920\n")
+	w.WriteString("PlayResY: 1080\n\n")
+	w.WriteString("[V4+ Styles]\n")
+	w.WriteString("Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	w.WriteString("Style: Default,Arial,54,&H00FFFFFF,&H000000FF,&H00000000,&H64000000,0,0,0,0,100,100,0,0,1,2,1,2,60,60,50,1\n\n")
+	w.WriteString("[Events]\n")
+	w.WriteString("Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+	for _, c := range cues {
+		lines := cueLines(c.Text, opt.LineWidth)
+		if len(lines) == 0 {
+			continue
+		}
+		start, end := cueRange(c)
+		escaped := make([]string, 0, len(lines))
+		for _, line := range lines {
+			escaped = append(escaped, escapeASS(line))
+		}
+		fmt.Fprintf(w, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", formatASSClock(start), formatASSClock(end), strings.Join(escaped, `\N`))
+	}
+}
+
+// cueLines 对每个强制换行段落分别折行
+func cueLines(text string, width int) []string {
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		lines = append(lines, Wrap(para, width)...)
+	}
+	return lines
+}
+
+func cueRange(c Cue) (int64, int64) {
+	start, end := c.StartMs, c.EndMs
+	if start < 0 {
+		start = 0
+	}
+	if end <= start {
+		end = start + minCueDurationMs
+	}
+	return start, end
+}
+
+// formatClock 输出 HH:MM:SS<sep>mmm（SRT 用逗号，VTT 用点）
+func formatClock(ms int64, sep string) string {
+	h := ms / 3600000
+	m := ms / 60000 % 60
+	s := ms / 1000 % 60
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, sep, ms%1000)
+}
+
+// FormatShortClock 输出 MM:SS，超过一小时输出 H:MM:SS，用于章节与引用等展示
+func FormatShortClock(ms int64) string {
+	if ms < 0 {
+		ms = 0
+	}
+	h := ms / 3600000
+	m := ms / 60000 % 60
+	s := ms / 1000 % 60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// ParseClock 解析 [H:]MM:SS[.mmm] 形式的时间点，返回毫秒
+func ParseClock(s string) (int64, bool) {
+	s = strings.Trim(strings.TrimSpace(s), "[]")
+	if s == "" {
+		return 0, false
+	}
+	return parseClock(s)
+}
+
+// formatASSClock 输出 H:MM:SS.cc（厘秒）
+func formatASSClock(ms int64) string {
+	h := ms / 3600000
+	m := ms / 60000 % 60
+	s := ms / 1000 % 60
+	return fmt.Sprintf("%d:%02d:%02d.%02d", h, m, s, ms%1000/10)
+}
+
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func escapeVTT(s string) string { return vttEscaper.Replace(s) }
+
+var assEscaper = strings.NewReplacer("{", `\{`, "}", `\}`)
+
+func escapeASS(s string) string { return assEscaper.Replace(s) }
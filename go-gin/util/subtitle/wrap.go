@@ -0,0 +1,107 @@
+package subtitle
+
+import (
+	"strings"
+	"unicode"
+)
+
+// token 折行的最小单位：西文单词或单个全角字符
+type token struct {
+	text  string
+	width int
+	space bool // 原文中前面是否有空白，决定拼接时是否补空格
+}
+
+// closingPunct 不应出现在行首的全角标点，折行时并入前一个字符
+const closingPunct = "，。！？、；：」』）》〉】”’…"
+
+// Wrap 按显示宽度折行：西文按单词、中日韩按字符断行，超长单词单独成行
+func Wrap(text string, width int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if width <= 0 {
+		width = DefaultLineWidth
+	}
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+	for _, t := range tokenize(text) {
+		sep := 0
+		if lineWidth > 0 && t.space {
+			sep = 1
+		}
+		if lineWidth > 0 && lineWidth+sep+t.width > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+			sep = 0
+		}
+		if sep == 1 {
+			line.WriteByte(' ')
+		}
+		line.WriteString(t.text)
+		lineWidth += sep + t.width
+	}
+	if lineWidth > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+func tokenize(text string) []token {
+	var tokens []token
+	var word strings.Builder
+	wordWidth := 0
+	space := false
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		tokens = append(tokens, token{text: word.String(), width: wordWidth, space: space})
+		word.Reset()
+		wordWidth = 0
+		space = false
+	}
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+			space = true
+		case strings.ContainsRune(closingPunct, r) && word.Len() > 0:
+			word.WriteRune(r)
+			wordWidth += runeWidth(r)
+		case strings.ContainsRune(closingPunct, r) && len(tokens) > 0 && !space:
+			last := &tokens[len(tokens)-1]
+			last.text += string(r)
+			last.width += runeWidth(r)
+		case isWide(r):
+			flush()
+			tokens = append(tokens, token{text: string(r), width: runeWidth(r), space: space})
+			space = false
+		default:
+			word.WriteRune(r)
+			wordWidth += runeWidth(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// isWide 判断是否为全角字符（中日韩文字与全角标点）
+func isWide(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r) ||
+		(r >= 0x3000 && r <= 0x303F) ||
+		(r >= 0xFF00 && r <= 0xFF60)
+}
+
+func runeWidth(r rune) int {
+	if isWide(r) {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,129 @@
+package textdiff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 差异类型
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// Op 一段差异：equal 两边相同，delete 仅在旧文本中，insert 仅在新文本中
+type Op struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// 超过该规模的中间差异不再逐句比较，整段按删除+插入输出，避免 O(n*m) 内存过大
+const maxCells = 4_000_000
+
+// Diff 按句比较两段文本（换行与句末标点处切分），相邻同类片段会合并
+func Diff(a, b string) []Op {
+	ta, tb := Tokenize(a), Tokenize(b)
+
+	// 去掉公共前后缀，编辑通常只涉及少数句子
+	prefix := 0
+	for prefix < len(ta) && prefix < len(tb) && ta[prefix] == tb[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(ta)-prefix && suffix < len(tb)-prefix && ta[len(ta)-1-suffix] == tb[len(tb)-1-suffix] {
+		suffix++
+	}
+
+	var ops []Op
+	ops = appendOp(ops, OpEqual, ta[:prefix]...)
+	ops = append(ops, lcs(ta[prefix:len(ta)-suffix], tb[prefix:len(tb)-suffix])...)
+	ops = appendOp(ops, OpEqual, ta[len(ta)-suffix:]...)
+	return ops
+}
+
+// Changed 差异中是否有插入或删除
+func Changed(ops []Op) bool {
+	for _, op := range ops {
+		if op.Type != OpEqual {
+			return true
+		}
+	}
+	return false
+}
+
+func lcs(a, b []string) []Op {
+	var ops []Op
+	if len(a)*len(b) > maxCells {
+		ops = appendOp(ops, OpDelete, a...)
+		return appendOp(ops, OpInsert, b...)
+	}
+	// dp[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	dp := make([][]int32, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = appendOp(ops, OpEqual, a[i])
+			i++
+			j++
+		case dp[i+1][j] >= dp[i][j+1]:
+			ops = appendOp(ops, OpDelete, a[i])
+			i++
+		default:
+			ops = appendOp(ops, OpInsert, b[j])
+			j++
+		}
+	}
+	ops = appendOp(ops, OpDelete, a[i:]...)
+	return appendOp(ops, OpInsert, b[j:]...)
+}
+
+func appendOp(ops []Op, typ string, texts ...string) []Op {
+	for _, t := range texts {
+		if n := len(ops); n > 0 && ops[n-1].Type == typ {
+			ops[n-1].Text += t
+			continue
+		}
+		ops = append(ops, Op{Type: typ, Text: t})
+	}
+	return ops
+}
+
+// Tokenize 在换行与句末标点后切分，切分点后的空白归入前一句，拼接后与原文一致
+func Tokenize(s string) []string {
+	var tokens []string
+	start := 0
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if !isBoundary(runes[i]) {
+			continue
+		}
+		// 连续标点（?!、……）与其后的空白一起归入当前句
+		for i+1 < len(runes) && (isBoundary(runes[i+1]) || unicode.IsSpace(runes[i+1])) {
+			i++
+		}
+		tokens = append(tokens, string(runes[start:i+1]))
+		start = i + 1
+	}
+	if start < len(runes) {
+		tokens = append(tokens, string(runes[start:]))
+	}
+	return tokens
+}
+
+func isBoundary(r rune) bool {
+	return r == '\n' || strings.ContainsRune("。！？；.!?;", r)
+}